@@ -0,0 +1,81 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// aliasesTag lists, comma-separated, other names an arg may be given under, like
+// `aliases:"old_name,legacy_name"`, so that args can be renamed without breaking old clients.  When
+// more than one of the names is given, the arg's own name wins, and then the aliases in the order
+// they are listed.
+const aliasesTag = "aliases"
+
+// ExposeAliases controls whether ArgsConfig adds an arg for each alias in an aliases tag, with the
+// same type and a description saying which arg replaces it.  graphql-go rejects args that aren't in
+// the schema, so aliases must be exposed for clients to keep using them; unexposed aliases are only
+// accepted by LoadArgs calls on hand-written args.  While exposed, an aliased arg isn't made
+// non-null by its tags, since clients may send an alias instead.  It is off by default.
+func (e *ArgLoader) ExposeAliases(expose bool) {
+	e.exposeAliases = expose
+}
+
+// fieldAliases returns the names in field's aliases tag.
+func fieldAliases(field reflect.StructField) []string {
+	var out []string
+	for _, alias := range strings.Split(field.Tag.Get(aliasesTag), ",") {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			out = append(out, alias)
+		}
+	}
+	return out
+}
+
+// lookupArg returns the value given for f in args, under its name or one of its aliases, and the
+// name it was given under.
+func lookupArg(args map[string]interface{}, f argField) (interface{}, string, bool) {
+	if v, ok := args[f.name]; ok {
+		return v, f.name, true
+	}
+	for _, alias := range f.aliases {
+		if v, ok := args[alias]; ok {
+			return v, alias, true
+		}
+	}
+	return nil, f.name, false
+}
+
+// aliasConfigs returns the types and descriptions of the exposed aliases of fields, whose own types
+// are in types, keyed by alias.  It returns an error if an alias collides with another arg.
+func (e *ArgLoader) aliasConfigs(fields []argField, types map[string]graphql.Input) (map[string]*graphql.ArgumentConfig, error) {
+	if !e.exposeAliases {
+		return nil, nil
+	}
+	out := map[string]*graphql.ArgumentConfig{}
+	for _, f := range fields {
+		for _, alias := range f.aliases {
+			if _, taken := types[alias]; taken {
+				return nil, fmt.Errorf("%s: alias %s is already the name of an arg", f.field.Name, alias)
+			}
+			if _, taken := out[alias]; taken {
+				return nil, fmt.Errorf("%s: alias %s is already used by another arg", f.field.Name, alias)
+			}
+			out[alias] = &graphql.ArgumentConfig{
+				Type:        nullable(types[f.name]),
+				Description: fmt.Sprintf("Deprecated: use %s instead.", f.name),
+			}
+		}
+	}
+	return out, nil
+}
+
+// nullable strips the non-null wrapper from t, if it has one.
+func nullable(t graphql.Input) graphql.Input {
+	if nonNull, ok := t.(*graphql.NonNull); ok {
+		return nonNull.OfType
+	}
+	return t
+}
@@ -0,0 +1,244 @@
+package graphqlhelpers
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// PersistedQueryStore holds the query texts of automatic persisted queries, by the hex sha256 hash
+// of the text.
+type PersistedQueryStore interface {
+	// Get returns the query with hash, and whether there is one.
+	Get(ctx context.Context, hash string) (query string, ok bool, err error)
+	// Put stores query under hash.
+	Put(ctx context.Context, hash, query string) error
+}
+
+// DefaultMaxPersistedQueries is the most queries a store from NewMemoryQueryStore holds.
+const DefaultMaxPersistedQueries = 1000
+
+// memoryQueryStore is a PersistedQueryStore held in memory, evicting the least recently used query
+// once it's full.
+type memoryQueryStore struct {
+	mu  sync.Mutex
+	max int
+	// the stored queries, most recently used first.
+	order   *list.List
+	queries map[string]*list.Element
+}
+
+// persistedEntry is a query held by a memoryQueryStore.
+type persistedEntry struct {
+	hash  string
+	query string
+}
+
+// NewMemoryQueryStore returns a PersistedQueryStore that holds up to DefaultMaxPersistedQueries
+// queries in memory, for one process.
+func NewMemoryQueryStore() PersistedQueryStore {
+	return NewMemoryQueryStoreSize(DefaultMaxPersistedQueries)
+}
+
+// NewMemoryQueryStoreSize returns a PersistedQueryStore that holds up to max queries in memory, for
+// one process.  Any client can register queries, so the store is bounded: once it's full, storing
+// a query evicts the least recently used one, which clients then register again.
+func NewMemoryQueryStoreSize(max int) PersistedQueryStore {
+	if max < 1 {
+		max = 1
+	}
+	return &memoryQueryStore{max: max, order: list.New(), queries: map[string]*list.Element{}}
+}
+
+func (m *memoryQueryStore) Get(ctx context.Context, hash string) (string, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	el, ok := m.queries[hash]
+	if !ok {
+		return "", false, nil
+	}
+	m.order.MoveToFront(el)
+	return el.Value.(*persistedEntry).query, true, nil
+}
+
+func (m *memoryQueryStore) Put(ctx context.Context, hash, query string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if el, ok := m.queries[hash]; ok {
+		el.Value.(*persistedEntry).query = query
+		m.order.MoveToFront(el)
+		return nil
+	}
+	m.queries[hash] = m.order.PushFront(&persistedEntry{hash: hash, query: query})
+	for m.order.Len() > m.max {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.queries, oldest.Value.(*persistedEntry).hash)
+	}
+	return nil
+}
+
+// persistedQueryError is a GraphQL error response for a persisted query request that can't be
+// served.
+type persistedQueryError struct {
+	status  int
+	message string
+	code    string
+}
+
+func (e *persistedQueryError) Error() string {
+	return e.message
+}
+
+func (e *persistedQueryError) write(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(e.status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []map[string]interface{}{{
+			"message":    e.message,
+			"extensions": map[string]interface{}{"code": e.code},
+		}},
+	})
+}
+
+// PersistedQueryMiddleware wraps a GraphQL HTTP handler (such as a graphql-go handler) with
+// automatic persisted queries, as implemented by Apollo clients.
+//
+// A request whose persistedQuery extension has a sha256Hash but no query is served the query
+// stored under that hash, filled in before the request reaches next.  When the hash is unknown,
+// the response is a PersistedQueryNotFound error, and the client retries with the query text too;
+// that query is checked against the hash and stored, so later requests can send the hash alone.
+// Requests without the extension pass through untouched.  Both GET requests, with the query and
+// extensions URL params, and POST requests with JSON bodies, including batches, are supported.
+// POST bodies are read whole, so limit their size before this middleware, as NewHandler does with
+// WithPersistedQueries.  Any client can store queries, so store should be bounded, as the stores
+// from NewMemoryQueryStore are, or expire its queries.
+func PersistedQueryMiddleware(store PersistedQueryStore, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		switch r.Method {
+		case http.MethodGet:
+			err = persistGetRequest(store, r)
+		case http.MethodPost:
+			err = persistPostRequest(store, r)
+		}
+		if err != nil {
+			if pqErr, ok := err.(*persistedQueryError); ok {
+				pqErr.write(w)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func persistGetRequest(store PersistedQueryStore, r *http.Request) error {
+	params := r.URL.Query()
+	raw := params.Get("extensions")
+	if raw == "" {
+		return nil
+	}
+	var extensions map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &extensions); err != nil {
+		return &persistedQueryError{http.StatusBadRequest, "extensions is not valid JSON", "BAD_REQUEST"}
+	}
+	query, ok, err := persistedQuery(r.Context(), store, params.Get("query"), extensions)
+	if err != nil || !ok {
+		return err
+	}
+	params.Set("query", query)
+	r.URL.RawQuery = params.Encode()
+	return nil
+}
+
+func persistPostRequest(store PersistedQueryStore, r *http.Request) error {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	r.Body.Close()
+	// whatever happens, leave the body readable for next.
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	batch := true
+	var requests []map[string]interface{}
+	if err := json.Unmarshal(body, &requests); err != nil {
+		batch = false
+		var single map[string]interface{}
+		if err := json.Unmarshal(body, &single); err != nil {
+			// not JSON, or not GraphQL requests.  next will report it.
+			return nil
+		}
+		requests = []map[string]interface{}{single}
+	}
+	changed := false
+	for _, req := range requests {
+		extensions, _ := req["extensions"].(map[string]interface{})
+		text, _ := req["query"].(string)
+		query, ok, err := persistedQuery(r.Context(), store, text, extensions)
+		if err != nil {
+			return err
+		}
+		if ok {
+			req["query"] = query
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	if batch {
+		body, err = json.Marshal(requests)
+	} else {
+		body, err = json.Marshal(requests[0])
+	}
+	if err != nil {
+		return err
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	r.ContentLength = int64(len(body))
+	return nil
+}
+
+// persistedQuery returns the query text a request with the given query and extensions should run,
+// and whether it differs from query.
+func persistedQuery(ctx context.Context, store PersistedQueryStore, query string, extensions map[string]interface{}) (string, bool, error) {
+	pq, _ := extensions["persistedQuery"].(map[string]interface{})
+	if pq == nil {
+		return "", false, nil
+	}
+	if version, _ := pq["version"].(float64); version != 1 {
+		return "", false, &persistedQueryError{http.StatusBadRequest, "unsupported persisted query version", "PERSISTED_QUERY_NOT_SUPPORTED"}
+	}
+	hash, _ := pq["sha256Hash"].(string)
+	if hash == "" {
+		return "", false, &persistedQueryError{http.StatusBadRequest, "persistedQuery has no sha256Hash", "BAD_REQUEST"}
+	}
+	if query != "" {
+		sum := sha256.Sum256([]byte(query))
+		if hex.EncodeToString(sum[:]) != hash {
+			return "", false, &persistedQueryError{http.StatusBadRequest, "provided sha256Hash does not match query", "BAD_REQUEST"}
+		}
+		if err := store.Put(ctx, hash, query); err != nil {
+			return "", false, fmt.Errorf("cannot store persisted query: %v", err)
+		}
+		return "", false, nil
+	}
+	stored, ok, err := store.Get(ctx, hash)
+	if err != nil {
+		return "", false, fmt.Errorf("cannot load persisted query: %v", err)
+	}
+	if !ok {
+		return "", false, &persistedQueryError{http.StatusOK, "PersistedQueryNotFound", "PERSISTED_QUERY_NOT_FOUND"}
+	}
+	return stored, true, nil
+}
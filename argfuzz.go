@@ -0,0 +1,252 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"time"
+
+	"github.com/graphql-go/graphql"
+)
+
+// ArgGenerator produces random arg maps for args structs, for property-based and fuzz tests of
+// resolvers and loaders.  Valid maps hold every required arg, and a value of the right type for
+// every arg they hold, as declared by the struct's tags; Invalid maps break exactly one of those
+// rules.  Validate methods aren't consulted, so a valid map may still fail them.
+//
+//	gen := loader.NewArgGenerator(seed)
+//	for i := 0; i < 1000; i++ {
+//		args, err := gen.Valid(UserArgs{})
+//		...
+//		p := testhelpers.NewResolveParams(args)
+//	}
+//
+// Maps hold values as graphql-go passes them to resolvers, and lists respect minitems tags.  Args of
+// registered types the generator knows nothing about, like custom scalars, are left out of valid
+// maps, and make generating one fail if they're required.
+type ArgGenerator struct {
+	loader *ArgLoader
+	rand   *rand.Rand
+
+	// MaxDepth limits how deeply input objects and lists are nested.  It defaults to 3.
+	MaxDepth int
+	// MaxListLen limits the length of generated lists.  It defaults to 3.
+	MaxListLen int
+}
+
+// NewArgGenerator returns a generator for the args structs of this loader, seeded with seed so
+// failures can be reproduced.
+func (e *ArgLoader) NewArgGenerator(seed int64) *ArgGenerator {
+	return &ArgGenerator{loader: e, rand: rand.New(rand.NewSource(seed)), MaxDepth: 3, MaxListLen: 3}
+}
+
+// NewArgGenerator returns a generator for args structs configured by the default loader.
+func NewArgGenerator(seed int64) *ArgGenerator {
+	return Default().NewArgGenerator(seed)
+}
+
+// Valid returns a random valid arg map for the args struct (or pointer to one) args.
+func (g *ArgGenerator) Valid(args interface{}) (map[string]interface{}, error) {
+	t, err := argsStructType(args)
+	if err != nil {
+		return nil, err
+	}
+	return g.validStruct(t, 0)
+}
+
+// Invalid returns a random invalid arg map for the args struct args, and a description of what
+// makes it invalid.  It fails if the struct has no args that can be made invalid.
+func (g *ArgGenerator) Invalid(args interface{}) (map[string]interface{}, string, error) {
+	t, err := argsStructType(args)
+	if err != nil {
+		return nil, "", err
+	}
+	m, err := g.validStruct(t, 0)
+	if err != nil {
+		return nil, "", err
+	}
+	var candidates []argField
+	for _, f := range g.loader.argFields(t) {
+		if !f.rest && (isRequired(f.field) || g.mismatch(f.field.Type) != nil) {
+			candidates = append(candidates, f)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, "", fmt.Errorf("%v has no required args, and none of its args can have the wrong type", t)
+	}
+	f := candidates[g.rand.Intn(len(candidates))]
+	bad := g.mismatch(f.field.Type)
+	if isRequired(f.field) && (bad == nil || g.rand.Intn(2) == 0) {
+		delete(m, f.name)
+		return m, fmt.Sprintf("required arg %s is missing", f.name), nil
+	}
+	m[f.name] = bad
+	return m, fmt.Sprintf("arg %s is %#v, which can't be loaded into a %v", f.name, bad, f.field.Type), nil
+}
+
+func argsStructType(args interface{}) (reflect.Type, error) {
+	t := reflect.TypeOf(args)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%v is not a struct", reflect.TypeOf(args))
+	}
+	return t, nil
+}
+
+func (g *ArgGenerator) validStruct(t reflect.Type, depth int) (map[string]interface{}, error) {
+	m := map[string]interface{}{}
+	for _, f := range g.loader.argFields(t) {
+		if f.rest {
+			continue
+		}
+		required := isRequired(f.field)
+		// deep inside input objects, only required fields are set, so that recursive types end.
+		if !required && (depth >= g.MaxDepth || g.rand.Intn(3) == 0) {
+			continue
+		}
+		list, err := listTags(f.field)
+		if err != nil {
+			return nil, err
+		}
+		v, ok, err := g.value(f.field.Type, depth, list.minItems)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", f.name, err)
+		}
+		oneof, err := oneofValues(f.field)
+		if err != nil {
+			return nil, err
+		}
+		if oneof != nil {
+			v = g.choose(v, oneof)
+		}
+		if !ok {
+			if required {
+				return nil, fmt.Errorf("cannot generate a value for required arg %s of type %v", f.name, f.field.Type)
+			}
+			continue
+		}
+		m[f.name] = v
+	}
+	return m, nil
+}
+
+// value returns a random value that loads into Go type t, or false if the generator can't make one.
+// Lists get at least minItems items.
+func (g *ArgGenerator) value(t reflect.Type, depth, minItems int) (interface{}, bool, error) {
+	if gqlType, ok := g.loader.gqlTypes[t]; ok {
+		if t == reflect.TypeOf(time.Time{}) && gqlType == graphql.String {
+			// times are RFC3339 strings unless UseDateTime was called.
+			return time.Unix(g.rand.Int63n(4102444800), 0).UTC().Format(time.RFC3339), true, nil
+		}
+		v, ok := g.graphqlValue(gqlType)
+		return v, ok, nil
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.value(t.Elem(), depth, minItems)
+	case reflect.Slice, reflect.Array:
+		n := g.rand.Intn(g.MaxListLen + 1)
+		if depth >= g.MaxDepth {
+			n = 0
+		}
+		if n < minItems {
+			n = minItems
+		}
+		if t.Kind() == reflect.Array {
+			n = t.Len()
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			v, ok, err := g.value(t.Elem(), depth+1, 0)
+			if err != nil || !ok {
+				return nil, ok, err
+			}
+			items[i] = v
+		}
+		return items, true, nil
+	case reflect.Struct:
+		if vt, ok := optionalValueType(t); ok {
+			return g.value(vt, depth, minItems)
+		}
+		m, err := g.validStruct(t, depth+1)
+		return m, err == nil, err
+	}
+	return nil, false, fmt.Errorf("no loader function found for type %v", t)
+}
+
+// graphqlValue returns a random value of a registered graphql type.
+func (g *ArgGenerator) graphqlValue(t graphql.Output) (interface{}, bool) {
+	switch t {
+	case graphql.Int:
+		return g.rand.Intn(2001) - 1000, true
+	case graphql.Float:
+		return g.rand.NormFloat64() * 1000, true
+	case graphql.Boolean:
+		return g.rand.Intn(2) == 0, true
+	case graphql.String, graphql.ID:
+		return g.string(), true
+	case graphql.DateTime:
+		return time.Unix(g.rand.Int63n(4102444800), 0).UTC(), true
+	}
+	if enum, ok := t.(*graphql.Enum); ok && len(enum.Values()) > 0 {
+		values := enum.Values()
+		return values[g.rand.Intn(len(values))].Value, true
+	}
+	return nil, false
+}
+
+// choose replaces each string in a generated value with one of values, for oneof args.
+func (g *ArgGenerator) choose(v interface{}, values []string) interface{} {
+	switch v := v.(type) {
+	case string:
+		return values[g.rand.Intn(len(values))]
+	case []interface{}:
+		for i := range v {
+			v[i] = g.choose(v[i], values)
+		}
+	}
+	return v
+}
+
+// string returns a random string, sometimes empty and sometimes with characters that need escaping
+// or aren't ASCII.
+func (g *ArgGenerator) string() string {
+	runes := []rune("abcXYZ019 _-'\"\\/<>&é漢🙂")
+	out := make([]rune, g.rand.Intn(12))
+	for i := range out {
+		out[i] = runes[g.rand.Intn(len(runes))]
+	}
+	return string(out)
+}
+
+// mismatch returns a value that can't be loaded into Go type t, or nil if the generator doesn't
+// know one.
+func (g *ArgGenerator) mismatch(t reflect.Type) interface{} {
+	if gqlType, ok := g.loader.gqlTypes[t]; ok {
+		switch gqlType {
+		case graphql.Int, graphql.Float:
+			return "not a number"
+		case graphql.Boolean:
+			return "not a bool"
+		case graphql.String, graphql.ID:
+			return 42
+		case graphql.DateTime:
+			return "not a time"
+		}
+		return nil
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.mismatch(t.Elem())
+	case reflect.Slice, reflect.Array:
+		return "not a list"
+	case reflect.Struct:
+		if vt, ok := optionalValueType(t); ok {
+			return g.mismatch(vt)
+		}
+		return "not an input object"
+	}
+	return nil
+}
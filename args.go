@@ -6,8 +6,13 @@ import (
 	"fmt"
 	"reflect"
 	"runtime"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
+	"unicode"
+	"unsafe"
 
 	"github.com/graphql-go/graphql"
 )
@@ -16,6 +21,7 @@ const (
 	argTag      = "arg"
 	requiredTag = "required"
 	descTag     = "desc"
+	errMsgTag   = "errmsg"
 )
 
 // DefaultLoaders are for extra types beyond the 4 scalar types built into GraphQL.
@@ -37,8 +43,6 @@ var BaseLoaders = []struct {
 	{LoaderFunc: LoadFloat, GqlType: graphql.Float},
 }
 
-var defaultLoader *ArgLoader
-
 // New returns a ArgLoader with all default loader funcs enabled.
 func New() (*ArgLoader, error) {
 	ec, err := Base()
@@ -71,6 +75,25 @@ func Empty() *ArgLoader {
 	ec := &ArgLoader{}
 	ec.loaderFuncs = map[reflect.Type]func(interface{}) (reflect.Value, error){}
 	ec.gqlTypes = map[reflect.Type]graphql.Output{}
+	ec.builtinLoaders = map[reflect.Type]bool{}
+	ec.inputObjects = map[reflect.Type]*graphql.InputObject{}
+	ec.filterOpTypes = map[string]*graphql.InputObject{}
+	ec.typeNames = map[string]graphql.Output{}
+	ec.naming = DefaultNaming{}
+	ec.dumpFuncs = map[reflect.Type]func(reflect.Value) (interface{}, error){}
+	ec.outputTypes = map[reflect.Type]graphql.Output{}
+	ec.objects = map[reflect.Type]*graphql.Object{}
+	ec.flagTypes = map[reflect.Type]*flagEnum{}
+	ec.valuerOutputs = map[reflect.Type]bool{}
+	ec.transforms = defaultTransforms()
+	ec.sanitizers = map[string]Sanitizer{}
+	ec.oneofEnums = map[string]*graphql.Enum{}
+	ec.descriptions = map[reflect.Type]map[string]string{}
+	ec.directives = map[string]*Directive{}
+	ec.inputFieldDirectives = map[string][]string{}
+	ec.fieldCosts = map[string]costSpec{}
+	ec.fieldArgTypes = map[*graphql.Field]reflect.Type{}
+	ec.resultCache = NewMemoryCache()
 	return ec
 }
 
@@ -81,8 +104,134 @@ type ArgLoader struct {
 	// reflect value of that type.
 	loaderFuncs map[reflect.Type]func(interface{}) (reflect.Value, error)
 
+	// the types whose loader func is one of the base loader funcs, which loadStruct can set
+	// without calling them.
+	builtinLoaders map[reflect.Type]bool
+
 	// a map from reflect types to the graphql types that should be used for their arguments.
 	gqlTypes map[reflect.Type]graphql.Output
+
+	// a map from reflect types to functions that serialize values of that type for DumpArgs, for
+	// types whose graphql type can't serialize them itself.
+	dumpFuncs map[reflect.Type]func(reflect.Value) (interface{}, error)
+
+	// input objects generated for struct types that have no registered loader func.
+	inputObjects map[reflect.Type]*graphql.InputObject
+
+	// operator input objects (like IntFilter) shared by generated filters, keyed by name.
+	filterOpTypes map[string]*graphql.InputObject
+
+	// output types registered with RegisterOutput.
+	outputTypes map[reflect.Type]graphql.Output
+
+	// objects generated for struct types used as outputs.
+	objects map[reflect.Type]*graphql.Object
+
+	// flag enums registered with RegisterFlagEnum, whose bitmasks are resolved as lists.
+	flagTypes map[reflect.Type]*flagEnum
+
+	// output types resolved as the value of their driver.Valuer Value method, like the database/sql
+	// null types registered with RegisterSQLNullTypes.
+	valuerOutputs map[reflect.Type]bool
+
+	// string transforms available to transform tags, by name.
+	transforms map[string]Transform
+
+	// sanitizers available to sanitize tags, by name.
+	sanitizers map[string]Sanitizer
+
+	// enums generated for oneof tags, by name.
+	oneofEnums map[string]*graphql.Enum
+
+	// field descriptions registered with RegisterDescriptions, by struct type and Go field name.
+	descriptions map[reflect.Type]map[string]string
+
+	// directives available to directive tags, by name.
+	directives map[string]*Directive
+
+	// the directives shown in SDL on generated input object fields, keyed by "Type.field".
+	inputFieldDirectives map[string][]string
+
+	// interfaces registered with RegisterInterface and RegisterInterfaceFor, in registration order.
+	interfaces []*interfaceDef
+
+	// middleware installed with Use, wrapping every resolver the loader builds.
+	middleware []Middleware
+
+	// hooks installed with OnLoad, called around every LoadArgs.
+	loadHooks []LoadHook
+
+	// hooks that adjust generated arg configs, installed with OnArgumentConfig.
+	argConfigHooks []argConfigHook
+
+	// hooks installed with OnDeprecatedArg, called for deprecated args and aliases.
+	deprecationHooks []DeprecationHook
+
+	// enforces role and perm tags.
+	accessChecker AccessChecker
+
+	// enforces ratelimit tags.
+	rateLimiter RateLimiter
+
+	// stores the results of cached fields.
+	resultCache ResultCache
+
+	// the secret key of cached results, from SetHashKey or made on first use.
+	hashKey     []byte
+	hashKeyOnce sync.Once
+
+	// decides who can see output fields with a visibility tag.
+	visible VisibilityFunc
+
+	// the coordinates of the types and fields to hide from introspection.
+	hidden []string
+
+	// field costs from cost tags and SetFieldCost, keyed by schema coordinate like "Query.users".
+	fieldCosts map[string]costSpec
+
+	// the args struct types of fields built by the loader whose args have cost tags, until a
+	// SchemaBuilder gives them a coordinate to record the costs at.
+	fieldArgTypes map[*graphql.Field]reflect.Type
+
+	// the names of all the named graphql types the loader knows about, so that generated types
+	// don't collide with them.
+	typeNames map[string]graphql.Output
+
+	// whether to resolve generated input object name collisions by prefixing the package name.
+	qualifyNames bool
+
+	// how generated input objects and untagged args are named.
+	naming NamingStrategy
+
+	// whether exported fields without an arg tag are treated as args.
+	implicitArgs bool
+
+	// whether ArgsConfig adds args for the aliases in aliases tags.
+	exposeAliases bool
+
+	// how much of a bad arg value appears in the errors it causes.
+	errorValues ErrorValuePolicy
+
+	// rewrites the messages of load and validation errors, or nil to leave them.
+	translator ErrorTranslator
+
+	// load plans of struct types, by reflect.Type.
+	plans sync.Map
+}
+
+// ImplicitArgs controls whether exported struct fields without an arg tag are treated as args,
+// named by the loader's NamingStrategy (camelCase by default).  Fields can still be excluded with
+// `arg:"-"`, and other tags like required and desc work as usual.  It is off by default.
+func (e *ArgLoader) ImplicitArgs(implicit bool) {
+	e.implicitArgs = implicit
+	e.resetPlans()
+}
+
+// QualifyDuplicateNames controls what happens when two different structs would generate input
+// objects with the same name (e.g. billing.Input and shipping.Input).  By default that is an error.
+// If qualify is true, the second one is prefixed with its package name instead (BillingInput).
+func (e *ArgLoader) QualifyDuplicateNames(qualify bool) {
+	e.qualifyNames = qualify
 }
 
 // ArgsConfig takes a struct instance with appropriate struct tags on its fields and returns a map
@@ -112,21 +261,310 @@ func (e *ArgLoader) SafeArgsConfig(i interface{}) (graphql.FieldConfigArgument,
 	}
 
 	out := graphql.FieldConfigArgument{}
-	for i := 0; i < structType.NumField(); i++ {
-		field := structType.Field(i)
-		argName, ok := field.Tag.Lookup(argTag)
-		if !ok {
-			// this field doesn't have our tag.  Skip.
+	fields := e.argFields(structType)
+	for _, f := range fields {
+		if f.rest {
+			// rest fields collect args configured elsewhere, so they have no config of their own.
+			if f.field.Type != restType {
+				return nil, fmt.Errorf("%s must be a map[string]interface{} to use the rest option", f.field.Name)
+			}
 			continue
 		}
-		out[argName] = &graphql.ArgumentConfig{
-			Type:        e.gqlTypes[field.Type],
-			Description: field.Tag.Get(descTag),
+		gqlType, err := e.fieldInputType(structType, f.field)
+		if err != nil {
+			return nil, fmt.Errorf("cannot configure %s: %v", f.field.Name, err)
+		}
+		if e.exposeAliases && len(f.aliases) > 0 {
+			gqlType = nullable(gqlType)
+		}
+		cfg := &graphql.ArgumentConfig{
+			Type:        gqlType,
+			Description: e.argDescription(structType, f),
 		}
+		e.configureArg(structType, f.field, cfg)
+		out[f.name] = cfg
+	}
+	types := map[string]graphql.Input{}
+	for name, cfg := range out {
+		types[name] = cfg.Type
+	}
+	aliases, err := e.aliasConfigs(fields, types)
+	if err != nil {
+		return nil, err
+	}
+	for alias, cfg := range aliases {
+		out[alias] = cfg
 	}
 	return out, nil
 }
 
+// inputType returns the graphql type to be used for arguments of the given Go type.  Registered
+// types are used as-is.  Slices and arrays of supported types become lists, and structs that have
+// not been registered become input objects whose fields are taken from their arg tags.
+func (e *ArgLoader) inputType(t reflect.Type) (graphql.Input, error) {
+	if gqlType, ok := e.gqlTypes[t]; ok {
+		return gqlType, nil
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		// pointers are just a way of making optional or recursive fields in Go. they have the same
+		// graphql type as what they point to.
+		return e.inputType(t.Elem())
+	case reflect.Slice, reflect.Array:
+		elemType, err := e.inputType(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return graphql.NewList(elemType), nil
+	case reflect.Struct:
+		if vt, ok := optionalValueType(t); ok {
+			// Optional and Null args have the type of their value, and can always be null.
+			gqlType, err := e.inputType(vt)
+			if nonNull, ok := gqlType.(*graphql.NonNull); ok {
+				gqlType = nonNull.OfType
+			}
+			return gqlType, err
+		}
+		return e.inputObject(t)
+	}
+	return nil, fmt.Errorf("no loader function found for type %v", t)
+}
+
+// inputObject generates a graphql input object for a struct type.  Generated objects are cached so
+// that a struct used in several places always maps to the same graphql type.  The object is cached
+// before its fields are generated, and the fields are handed to graphql-go through a thunk, so that
+// self-referencing structs (like a filter with "And []Filter") resolve to the object being built
+// instead of recursing forever.
+func (e *ArgLoader) inputObject(t reflect.Type) (*graphql.InputObject, error) {
+	if obj, ok := e.inputObjects[t]; ok {
+		return obj, nil
+	}
+	name, err := e.inputObjectName(t)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.requiredCycle(t); err != nil {
+		return nil, err
+	}
+	var fields graphql.InputObjectConfigFieldMap
+	obj := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: name,
+		Fields: graphql.InputObjectConfigFieldMapThunk(func() graphql.InputObjectConfigFieldMap {
+			return fields
+		}),
+	})
+	e.inputObjects[t] = obj
+	e.typeNames[name] = obj
+
+	generated := graphql.InputObjectConfigFieldMap{}
+	argFields := e.argFields(t)
+	for _, f := range argFields {
+		if f.rest {
+			continue
+		}
+		gqlType, err := e.fieldInputType(t, f.field)
+		if err != nil {
+			delete(e.inputObjects, t)
+			delete(e.typeNames, name)
+			return nil, fmt.Errorf("cannot configure %s.%s: %v", t.Name(), f.field.Name, err)
+		}
+		if e.exposeAliases && len(f.aliases) > 0 {
+			gqlType = nullable(gqlType)
+		}
+		cfg := &graphql.InputObjectFieldConfig{
+			Type:        gqlType,
+			Description: e.argDescription(t, f),
+		}
+		e.configureInputField(t, f.field, cfg)
+		generated[f.name] = cfg
+		if directives, _ := e.fieldDirectives(f.field); len(sdlDirectives(directives)) > 0 {
+			e.inputFieldDirectives[name+"."+f.name] = sdlDirectives(directives)
+		}
+	}
+	types := map[string]graphql.Input{}
+	for name, cfg := range generated {
+		types[name] = cfg.Type
+	}
+	aliases, err := e.aliasConfigs(argFields, types)
+	if err != nil {
+		delete(e.inputObjects, t)
+		delete(e.typeNames, name)
+		return nil, fmt.Errorf("cannot configure %s: %v", t.Name(), err)
+	}
+	for alias, cfg := range aliases {
+		generated[alias] = &graphql.InputObjectFieldConfig{Type: cfg.Type, Description: cfg.Description}
+	}
+	fields = generated
+	return obj, nil
+}
+
+// inputObjectName picks the graphql name for the input object generated from t, making sure that it
+// doesn't collide with any other type known to the loader.  graphql-go would otherwise happily build
+// a schema with two different types sharing a name, and only one of them would be usable.
+func (e *ArgLoader) inputObjectName(t reflect.Type) (string, error) {
+	if t.Name() == "" {
+		return "", fmt.Errorf("cannot generate an input object for anonymous struct %v. "+
+			"declare a named type for it", t)
+	}
+	name := e.naming.InputObjectName(t)
+	if _, taken := e.typeNames[name]; !taken {
+		return name, nil
+	}
+	if !e.qualifyNames {
+		return "", fmt.Errorf("cannot generate input object %s for %v: the name is already used by %s. "+
+			"rename the struct, or call QualifyDuplicateNames(true) to prefix duplicates with their package name",
+			name, t, e.typeDescription(name))
+	}
+	qualified := qualifiedName(t, name)
+	if _, taken := e.typeNames[qualified]; taken {
+		return "", fmt.Errorf("cannot generate input object for %v: both %s and %s are already used by %s and %s",
+			t, name, qualified, e.typeDescription(name), e.typeDescription(qualified))
+	}
+	return qualified, nil
+}
+
+// typeDescription describes whatever is using a graphql type name, for error messages.
+func (e *ArgLoader) typeDescription(name string) string {
+	for t, obj := range e.inputObjects {
+		if obj.Name() == name {
+			return fmt.Sprintf("the input object generated for %v", t)
+		}
+	}
+	return fmt.Sprintf("the registered %s type", name)
+}
+
+// qualifiedName prefixes the name generated for a type with its (capitalized) package name, so that
+// billing.Input becomes BillingInput.
+func qualifiedName(t reflect.Type, name string) string {
+	pkg := t.PkgPath()
+	if idx := strings.LastIndex(pkg, "/"); idx >= 0 {
+		pkg = pkg[idx+1:]
+	}
+	var b strings.Builder
+	upper := true
+	for _, r := range pkg {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String() + name
+}
+
+// requiredCycle returns an error if t can reach itself through a chain of required struct fields.
+// Such a type can be configured, but no finite input could ever satisfy it.  Lists are not
+// followed, since an empty list satisfies a required list argument.
+func (e *ArgLoader) requiredCycle(t reflect.Type) error {
+	return e.walkRequired(t, t, nil, map[reflect.Type]bool{t: true})
+}
+
+func (e *ArgLoader) walkRequired(root, t reflect.Type, path []string, seen map[reflect.Type]bool) error {
+	for _, f := range e.argFields(t) {
+		if !isRequired(f.field) {
+			continue
+		}
+		ft := f.field.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if _, registered := e.gqlTypes[ft]; registered || ft.Kind() != reflect.Struct {
+			continue
+		}
+		step := append(path[:len(path):len(path)], t.Name()+"."+f.name)
+		if ft == root {
+			return fmt.Errorf("%s -> %s is a cycle of required fields, so no input could satisfy it. "+
+				"make one of the fields optional", strings.Join(step, " -> "), root.Name())
+		}
+		if seen[ft] {
+			continue
+		}
+		seen[ft] = true
+		if err := e.walkRequired(root, ft, step, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isRequired reports whether a field's required tag is set to true, or its minitems tag demands at
+// least one item.
+func isRequired(field reflect.StructField) bool {
+	required, _ := strconv.ParseBool(field.Tag.Get(requiredTag))
+	list, _ := listTags(field)
+	return required || list.minItems > 0
+}
+
+// argField is a struct field that has been tagged as an argument.
+type argField struct {
+	name  string
+	index []int
+	field reflect.StructField
+
+	// other names the arg may be given under, from its aliases tag.
+	aliases []string
+
+	// rest fields receive all the args that no other field consumed.
+	rest bool
+}
+
+// restOption is the arg tag option (as in `arg:",rest"`) for a map[string]interface{} field that
+// receives all the args that don't match another field.
+const restOption = "rest"
+
+var restType = reflect.TypeOf(map[string]interface{}{})
+
+// argFields returns the argument fields of a struct type.  Untagged embedded structs have their
+// fields promoted, so that reusable groups of args (like PageArgs) can be mixed into any args
+// struct.  Other untagged fields are skipped unless the loader has implicit args enabled.  Fields
+// tagged `arg:"-"` are always skipped.
+func (e *ArgLoader) argFields(t reflect.Type) []argField {
+	var out []argField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, ok := field.Tag.Lookup(argTag)
+		if name == "-" {
+			continue
+		}
+		if _, isLoader := field.Tag.Lookup(loaderTag); isLoader && !ok {
+			// a DataLoader, not an arg.
+			continue
+		}
+		if !ok {
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				for _, f := range e.argFields(field.Type) {
+					f.index = append([]int{i}, f.index...)
+					out = append(out, f)
+				}
+				continue
+			}
+			if !e.implicitArgs || field.PkgPath != "" {
+				// this field doesn't have our tag.  Skip.
+				continue
+			}
+		}
+		var opts []string
+		if idx := strings.Index(name, ","); idx >= 0 {
+			name, opts = name[:idx], strings.Split(name[idx+1:], ",")
+		}
+		f := argField{name: name, index: []int{i}, field: field, aliases: fieldAliases(field)}
+		for _, opt := range opts {
+			if opt == restOption {
+				f.rest = true
+			}
+		}
+		if f.name == "" && !f.rest {
+			f.name = e.naming.ArgName(field)
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
 // RegisterParser takes a func (string) (<anytype>, error) and registers it on the ArgLoader as
 // the parser for <anytype>
 func (e *ArgLoader) Register(f interface{}, gqlType graphql.Output) error {
@@ -164,25 +602,210 @@ func (e *ArgLoader) Register(f interface{}, gqlType graphql.Output) error {
 	}
 
 	callable := reflect.ValueOf(f)
+	for _, base := range BaseLoaders {
+		if reflect.ValueOf(base.LoaderFunc).Pointer() == callable.Pointer() {
+			e.builtinLoaders[t.Out(0)] = true
+		}
+	}
 	wrapped := func(i interface{}) (v reflect.Value, err error) {
 		defer func() {
 			p := recover()
 			if p != nil {
 				// we panicked running the inner loader func.
-				err = fmt.Errorf("%s panicked: %s", fname, p)
+				err = newLoaderPanicError(fname, i, p)
 			}
 		}()
 		returnvals := callable.Call([]reflect.Value{reflect.ValueOf(i)})
 		if !returnvals[1].IsNil() {
-			return reflect.Value{}, fmt.Errorf("%v", returnvals[1])
+			// returned as it is, so that ValueErrors can be found with errors.As.
+			return reflect.Value{}, returnvals[1].Interface().(error)
 		}
 		return returnvals[0], nil
 	}
-	e.loaderFuncs[t.Out(0)] = wrapped
-	e.gqlTypes[t.Out(0)] = gqlType
+	return e.register(t.Out(0), wrapped, gqlType)
+}
+
+// RegisterAll registers many loader funcs at once, each for the graphql type it's keyed by, so a
+// service with many custom scalars can register them together:
+//
+//	err := loader.RegisterAll(map[graphql.Output]interface{}{
+//		UUIDScalar:  LoadUUID,
+//		MoneyScalar: LoadMoney,
+//	})
+//
+// The map is keyed by graphql type because Go funcs can't be map keys.  Loader funcs are
+// registered in order of their types' names, stopping at the first one that fails.
+func (e *ArgLoader) RegisterAll(loaders map[graphql.Output]interface{}) error {
+	types := make([]graphql.Output, 0, len(loaders))
+	for gqlType := range loaders {
+		types = append(types, gqlType)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].String() < types[j].String() })
+	for _, gqlType := range types {
+		if err := e.Register(loaders[gqlType], gqlType); err != nil {
+			return fmt.Errorf("could not register loader func for %s: %v", gqlType, err)
+		}
+	}
+	return nil
+}
+
+// MustRegisterAll is like RegisterAll, but panics if a loader func can't be registered.
+func (e *ArgLoader) MustRegisterAll(loaders map[graphql.Output]interface{}) {
+	if err := e.RegisterAll(loaders); err != nil {
+		mustPanic("could not register loader funcs", err)
+	}
+}
+
+// register installs an already-wrapped loader func and graphql type for t.
+func (e *ArgLoader) register(t reflect.Type, loaderFunc func(interface{}) (reflect.Value, error), gqlType graphql.Output) error {
+	if _, alreadyRegistered := e.loaderFuncs[t]; alreadyRegistered {
+		return fmt.Errorf("a loader func has already been registered for the %v type", t)
+	}
+	e.loaderFuncs[t] = loaderFunc
+	e.gqlTypes[t] = gqlType
+	switch gqlType.(type) {
+	case *graphql.List, *graphql.NonNull:
+	default:
+		e.typeNames[gqlType.Name()] = gqlType
+	}
+	return nil
+}
+
+// UseDateTime makes time.Time args and results graphql-go's DateTime scalar instead of RFC3339
+// Strings, for args configured from now on.  Clients then declare time variables as DateTime
+// rather than String, so switching an existing schema over breaks them.
+func (e *ArgLoader) UseDateTime() {
+	t := reflect.TypeOf(time.Time{})
+	e.gqlTypes[t] = graphql.DateTime
+	e.typeNames[graphql.DateTime.Name()] = graphql.DateTime
+}
+
+// UseDateTime makes time.Time args and results DateTimes on the default loader.
+func UseDateTime() {
+	Default().UseDateTime()
+}
+
+// ArgError is returned by LoadArgs when a particular arg is missing or can't be loaded.
+type ArgError struct {
+	// Arg is the name of the graphql arg.
+	Arg string
+	// Path leads from Arg to the nested input object field or list item that failed, if it wasn't
+	// Arg itself.
+	Path ArgPath
+	Err  error
+}
+
+// ArgPath is the path to a value in the args of a field: arg and input object field names, and
+// list indexes.
+type ArgPath []interface{}
+
+// String returns the path in the form "input.addresses[2].zip".
+func (p ArgPath) String() string {
+	var b strings.Builder
+	for _, elem := range p {
+		if idx, ok := elem.(int); ok {
+			fmt.Fprintf(&b, "[%d]", idx)
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte('.')
+		}
+		fmt.Fprint(&b, elem)
+	}
+	return b.String()
+}
+
+// itemError is an error loading a list item.
+type itemError struct {
+	index int
+	err   error
+}
+
+// Error names the item, with the indexes of the items it's nested in for nested lists, as in
+// "item [1][0]: x is not a float".
+func (e *itemError) Error() string {
+	indexes := []int{e.index}
+	err := e.err
+	for {
+		inner, ok := err.(*itemError)
+		if !ok {
+			break
+		}
+		indexes, err = append(indexes, inner.index), inner.err
+	}
+	if len(indexes) == 1 {
+		return fmt.Sprintf("item %d: %v", e.index, err)
+	}
+	var b strings.Builder
+	for _, idx := range indexes {
+		fmt.Fprintf(&b, "[%d]", idx)
+	}
+	return fmt.Sprintf("item %s: %v", b.String(), err)
+}
+
+// Unwrap returns the error loading the item.
+func (e *itemError) Unwrap() error {
+	return e.err
+}
+
+// argPath returns the path, below the arg being loaded, to the value that caused err, an error
+// returned by loadValue.
+func argPath(err error) ArgPath {
+	switch err := err.(type) {
+	case *itemError:
+		return append(ArgPath{err.index}, argPath(err.err)...)
+	case *ArgError:
+		return append(ArgPath{err.Arg}, err.Path...)
+	}
 	return nil
 }
 
+func (e *ArgError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ArgError) Unwrap() error {
+	return e.Err
+}
+
+// messageError is an error shown with a different message than the error it wraps.
+type messageError struct {
+	msg string
+	err error
+}
+
+func (e *messageError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the error with the original message.
+func (e *messageError) Unwrap() error {
+	return e.err
+}
+
+// withErrMsg replaces the message of err, caused by field, with the field's errmsg tag, if it has
+// one.  The tag lets the copy users see be written next to the arg:
+//
+//	Email string `arg:"email" required:"true" errmsg:"Please provide a valid email"`
+//
+// It's used when the arg is required and missing, or can't be loaded, which includes a nested input
+// object failing its Validate method.  The original error can still be reached with errors.Unwrap.
+func withErrMsg(field reflect.StructField, err error) error {
+	if msg, ok := field.Tag.Lookup(errMsgTag); ok && msg != "" {
+		return &messageError{msg: msg, err: err}
+	}
+	return err
+}
+
+// Validator may be implemented by args structs (or structs nested in them) that need to check
+// their values once they have been loaded, such as cross-field constraints.
+type Validator interface {
+	Validate() error
+}
+
+var validatorType = reflect.TypeOf((*Validator)(nil)).Elem()
+
 // Load loads arguments from the provided map into the provided struct.
 func (e *ArgLoader) LoadArgs(p graphql.ResolveParams, c interface{}) error {
 	// assert that c is a struct.
@@ -195,44 +818,200 @@ func (e *ArgLoader) LoadArgs(p graphql.ResolveParams, c interface{}) error {
 	if structType.Kind() != reflect.Struct {
 		return fmt.Errorf("%v is not a pointer to a struct", c)
 	}
-	structVal := reflect.ValueOf(c).Elem()
+	done := e.startLoad(p, c)
+	if len(e.deprecationHooks) > 0 {
+		e.reportDeprecated(p, structType, p.Args, "")
+	}
+	err := e.authorize(p.Context, structType, p.Args)
+	if err == nil {
+		err = injectDataLoaders(p.Context, reflect.ValueOf(c).Elem())
+	}
+	if err == nil {
+		err = e.loadStruct(explicitNulls(p), reflect.ValueOf(c).Elem())
+		if err != nil {
+			err = e.translate(p.Context, p.Args, err)
+		}
+	}
+	if err == nil {
+		err = e.rateLimit(p.Context, p.Args, reflect.ValueOf(c).Elem())
+	}
+	done(err)
+	return err
+}
 
-	for i := 0; i < structType.NumField(); i++ {
-		field := structType.Field(i)
-		argKey, ok := field.Tag.Lookup(argTag)
+// loadStruct populates the tagged fields of structVal from args, then validates the result.
+func (e *ArgLoader) loadStruct(args map[string]interface{}, structVal reflect.Value) error {
+	plan, err := e.plan(structVal.Type())
+	if err != nil {
+		return err
+	}
+	var base unsafe.Pointer
+	if structVal.CanAddr() {
+		base = unsafe.Pointer(structVal.UnsafeAddr())
+	}
+	for i := range plan.fields {
+		f := &plan.fields[i]
+		field := f.field
+		interfaceVal, argKey, ok := lookupArg(args, f.argField)
+		if interfaceVal == nil && !f.optional {
+			// only Optional and Null fields tell a null arg apart from an omitted one.
+			ok = false
+		}
 		if !ok {
-			// this field doesn't have our tag.  Skip.
+			// could not find the key we're looking for in map.  is it required?
+			if f.required || f.list.minItems > 0 {
+				return &ArgError{Arg: argKey, Err: withErrMsg(field, fmt.Errorf("%s is required", argKey))}
+			}
+			continue
+		}
+
+		if f.set != nil && base != nil && f.set(unsafe.Pointer(uintptr(base)+f.offset), interfaceVal) {
 			continue
 		}
+		err = f.list.check(interfaceVal)
+		var toSet reflect.Value
+		if err == nil {
+			toSet, err = e.loadValue(field.Type, interfaceVal)
+		}
+		if err == nil {
+			toSet, err = applyDirectives(transformValue(toSet, f.transforms), f.directives)
+		}
+		if err == nil {
+			err = e.checkOneof(toSet, f.oneof)
+		}
+		if err != nil {
+			return &ArgError{
+				Arg:  argKey,
+				Path: argPath(err),
+				Err:  withErrMsg(field, fmt.Errorf("cannot populate %s: %w", field.Name, err)),
+			}
+		}
+		structVal.FieldByIndex(f.index).Set(toSet)
+	}
+	if plan.rest != nil {
+		loadRest(args, plan, structVal)
+	}
+	if !plan.validates {
+		return nil
+	}
+	return validate(structVal)
+}
+
+// loadRest puts every arg that didn't match one of the plan's fields into its rest field.
+func loadRest(args map[string]interface{}, plan *loadPlan, structVal reflect.Value) {
+	unmatched := map[string]interface{}{}
+	for k, v := range args {
+		if !plan.matched[k] {
+			unmatched[k] = v
+		}
+	}
+	structVal.FieldByIndex(plan.rest.index).Set(reflect.ValueOf(unmatched))
+}
 
-		interfaceVal, ok := p.Args[argKey]
+// loadValue converts a raw argument value into a reflect value of type t, using the registered
+// loader func for t if there is one.
+func (e *ArgLoader) loadValue(t reflect.Type, i interface{}) (reflect.Value, error) {
+	if loaderFunc, ok := e.loaderFuncs[t]; ok {
+		v, err := loaderFunc(i)
+		if err != nil {
+			return reflect.Value{}, e.valueError(err, i)
+		}
+		return v, nil
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		v, err := e.loadValue(t.Elem(), i)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out := reflect.New(t.Elem())
+		out.Elem().Set(v)
+		return out, nil
+	case reflect.Slice:
+		items, ok := i.([]interface{})
 		if !ok {
-			// could not find the key we're looking for in map.  is it required?
-			requiredVal, ok := field.Tag.Lookup(requiredTag)
-			if !ok {
-				// no required tag, so it's not required.
-				continue
+			return reflect.Value{}, e.valueError(&ValueError{Value: i, Problem: "is not a list"}, i)
+		}
+		out := reflect.MakeSlice(t, len(items), len(items))
+		for idx, item := range items {
+			v, err := e.loadValue(t.Elem(), item)
+			if err != nil {
+				return reflect.Value{}, &itemError{index: idx, err: err}
 			}
-			required, err := strconv.ParseBool(requiredVal)
+			out.Index(idx).Set(v)
+		}
+		return out, nil
+	case reflect.Array:
+		// arrays are lists that must have exactly as many items as the array.
+		items, ok := i.([]interface{})
+		if !ok {
+			return reflect.Value{}, e.valueError(&ValueError{Value: i, Problem: "is not a list"}, i)
+		}
+		if len(items) != t.Len() {
+			return reflect.Value{}, fmt.Errorf("there must be exactly %d items, not %d", t.Len(), len(items))
+		}
+		out := reflect.New(t).Elem()
+		for idx, item := range items {
+			v, err := e.loadValue(t.Elem(), item)
 			if err != nil {
-				return fmt.Errorf("%s is not a valid 'required' tag value", requiredVal)
+				return reflect.Value{}, &itemError{index: idx, err: err}
 			}
-			if required {
-				return fmt.Errorf("%s is required", argKey)
-			} else {
-				continue
+			out.Index(idx).Set(v)
+		}
+		return out, nil
+	case reflect.Struct:
+		if vt, ok := optionalValueType(t); ok {
+			out := reflect.New(t)
+			var v reflect.Value
+			if i != nil {
+				var err error
+				if v, err = e.loadValue(vt, i); err != nil {
+					return reflect.Value{}, err
+				}
 			}
+			out.Interface().(optional).setLoaded(v)
+			return out.Elem(), nil
 		}
-		loaderFunc, ok := e.loaderFuncs[field.Type]
+		m, ok := i.(map[string]interface{})
 		if !ok {
-			return fmt.Errorf("no loader function found for type %v", field.Type)
+			return reflect.Value{}, e.valueError(&ValueError{Value: i, Problem: "is not an input object"}, i)
+		}
+		out := reflect.New(t).Elem()
+		if err := e.loadStruct(m, out); err != nil {
+			if _, isArgErr := err.(*ArgError); !isArgErr {
+				err = &validationError{err}
+			}
+			return reflect.Value{}, err
 		}
+		return out, nil
+	}
+	return reflect.Value{}, fmt.Errorf("no loader function found for type %v", t)
+}
 
-		toSet, err := loaderFunc(interfaceVal)
-		if err != nil {
-			return fmt.Errorf("cannot populate %s: %v", field.Name, err)
+// validate calls Validate on v if it (or a pointer to it) implements Validator.  If it doesn't,
+// any untagged embedded structs are validated instead, since their Validate methods are not
+// promoted when more than one of them defines one.
+func validate(v reflect.Value) error {
+	if v.CanAddr() {
+		if validator, ok := v.Addr().Interface().(Validator); ok {
+			return validator.Validate()
+		}
+	}
+	if validator, ok := v.Interface().(Validator); ok {
+		return validator.Validate()
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if _, tagged := field.Tag.Lookup(argTag); tagged || !field.Anonymous {
+			continue
+		}
+		if field.Type.Kind() != reflect.Struct || !v.Field(i).CanInterface() {
+			continue
+		}
+		if err := validate(v.Field(i)); err != nil {
+			return err
 		}
-		structVal.Field(i).Set(toSet)
 	}
 	return nil
 }
@@ -240,7 +1019,7 @@ func (e *ArgLoader) LoadArgs(p graphql.ResolveParams, c interface{}) error {
 func LoadBool(i interface{}) (bool, error) {
 	b, ok := i.(bool)
 	if !ok {
-		return false, fmt.Errorf("%v is not a bool", i)
+		return false, &ValueError{Value: i, Problem: "is not a bool"}
 	}
 	return b, nil
 }
@@ -248,7 +1027,7 @@ func LoadBool(i interface{}) (bool, error) {
 func LoadString(i interface{}) (string, error) {
 	b, ok := i.(string)
 	if !ok {
-		return "", fmt.Errorf("%v is not a string", i)
+		return "", &ValueError{Value: i, Problem: "is not a string"}
 	}
 	return b, nil
 }
@@ -256,7 +1035,7 @@ func LoadString(i interface{}) (string, error) {
 func LoadInt(i interface{}) (int, error) {
 	b, ok := i.(int)
 	if !ok {
-		return 0, fmt.Errorf("%v is not an int", i)
+		return 0, &ValueError{Value: i, Problem: "is not an int"}
 	}
 	return b, nil
 }
@@ -264,40 +1043,50 @@ func LoadInt(i interface{}) (int, error) {
 func LoadFloat(i interface{}) (float64, error) {
 	b, ok := i.(float64)
 	if !ok {
-		return 0, fmt.Errorf("%v is not a float", i)
+		return 0, &ValueError{Value: i, Problem: "is not a float"}
 	}
 	return b, nil
 }
 
 func LoadTime(i interface{}) (time.Time, error) {
+	// with UseDateTime, the DateTime scalar will already have parsed the value for us.
+	if t, ok := i.(time.Time); ok {
+		return t, nil
+	}
 	s, ok := i.(string)
 	if !ok {
-		return time.Time{}, fmt.Errorf("%v is not a RFC3339 timestamp", i)
+		return time.Time{}, &ValueError{Value: i, Problem: "is not a RFC3339 timestamp"}
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, &ValueError{Value: s, Problem: "is not a RFC3339 timestamp"}
 	}
-	return time.Parse(time.RFC3339, s)
+	return t, nil
 }
 
 func ArgsConfig(i interface{}) graphql.FieldConfigArgument {
-	return defaultLoader.ArgsConfig(i)
+	return Default().ArgsConfig(i)
 }
 
 // LoadArgs loads values from the provided interface map into the provided struct.
 func LoadArgs(p graphql.ResolveParams, i interface{}) error {
-	return defaultLoader.LoadArgs(p, i)
+	return Default().LoadArgs(p, i)
 }
 
 // Register takes a func (interface{}) (<anytype>, error) and registers it on the default loader
 // as the loader func for <anytype>.
 func Register(f interface{}, gqlType graphql.Output) error {
-	return defaultLoader.Register(f, gqlType)
+	return Default().Register(f, gqlType)
 }
 
-func init() {
-	// we can only fail here if one of the hardcoded default loader fund has the wrong function
-	// signature.  If that does fail, fail hard.
-	var err error
-	defaultLoader, err = New()
-	if err != nil {
-		panic(fmt.Sprintf("could not init default loader: %v", err))
-	}
+// RegisterAll registers many loader funcs on the default loader, each for the graphql type it's
+// keyed by.
+func RegisterAll(loaders map[graphql.Output]interface{}) error {
+	return Default().RegisterAll(loaders)
+}
+
+// MustRegisterAll registers many loader funcs on the default loader, panicking if one can't be
+// registered.
+func MustRegisterAll(loaders map[graphql.Output]interface{}) {
+	Default().MustRegisterAll(loaders)
 }
@@ -0,0 +1,66 @@
+package graphqlhelpers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+type benchScalarArgs struct {
+	ID     string  `arg:"id" required:"true"`
+	Name   string  `arg:"name"`
+	Limit  int     `arg:"limit"`
+	Score  float64 `arg:"score"`
+	Active bool    `arg:"active"`
+}
+
+var benchScalarParams = graphql.ResolveParams{Args: map[string]interface{}{
+	"id": "u1", "name": "Ada", "limit": 20, "score": 0.5, "active": true,
+}}
+
+// BenchmarkLoadArgsScalars loads built-in scalar args, which load plans set directly.
+func BenchmarkLoadArgsScalars(b *testing.B) {
+	benchmarkLoadScalars(b, newBenchLoader(b))
+}
+
+// BenchmarkLoadArgsScalarsReflect loads the same args with the plan's direct setters removed, so
+// every field goes through loadValue, for comparison with BenchmarkLoadArgsScalars.
+func BenchmarkLoadArgsScalarsReflect(b *testing.B) {
+	e := newBenchLoader(b)
+	t := reflect.TypeOf(benchScalarArgs{})
+	p, err := e.plan(t)
+	if err != nil {
+		b.Fatal(err)
+	}
+	slow := *p
+	slow.fields = append([]fieldPlan(nil), p.fields...)
+	for i := range slow.fields {
+		slow.fields[i].set = nil
+	}
+	e.plans.Store(t, &slow)
+	benchmarkLoadScalars(b, e)
+}
+
+func newBenchLoader(b *testing.B) *ArgLoader {
+	e, err := New()
+	if err != nil {
+		b.Fatal(err)
+	}
+	return e
+}
+
+func benchmarkLoadScalars(b *testing.B, e *ArgLoader) {
+	var args benchScalarArgs
+	if err := e.LoadArgs(benchScalarParams, &args); err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		args = benchScalarArgs{}
+		if err := e.LoadArgs(benchScalarParams, &args); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package graphqlhelpers
+
+import (
+	"context"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// AuditEntry describes one successful mutation.
+type AuditEntry struct {
+	// Operation is the name of the graphql operation, or empty for anonymous operations.
+	Operation string
+	// Field is the mutation field, like "Mutation.deleteUser".
+	Field string
+	// Caller identifies who made the request, as returned by the caller func given to Audit.
+	Caller string
+	// Args are the loaded args, with redacted values masked as in RedactedArgs.  Nil if the
+	// resolver didn't load any.
+	Args map[string]interface{}
+	Time time.Time
+}
+
+// AuditSink records audit entries, for example by writing them to a database or log.
+type AuditSink func(ctx context.Context, entry AuditEntry)
+
+type auditKey struct{}
+
+// auditCall collects the args loaded by one mutation resolver, dumped as soon as they're loaded,
+// since pooled args structs are reused once the resolver returns.
+type auditCall struct {
+	args map[string]interface{}
+}
+
+// Audit sends an AuditEntry to sink after every successful mutation resolved by a resolver the
+// loader builds from now on.  caller gets the caller's identity from the request context, and may
+// be nil.
+func (e *ArgLoader) Audit(sink AuditSink, caller func(ctx context.Context) string) {
+	e.OnLoad(func(p graphql.ResolveParams, target interface{}) func(error) {
+		call, ok := contextValue(p.Context, auditKey{}).(*auditCall)
+		if !ok {
+			return nil
+		}
+		return func(err error) {
+			if err == nil {
+				// the args loaded fine, so they can be dumped too.
+				call.args, _ = e.RedactedArgs(target)
+			}
+		}
+	})
+	e.Use(func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (interface{}, error) {
+			if !isMutationField(p.Info) {
+				return next(p)
+			}
+			call := &auditCall{}
+			ctx := p.Context
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			p.Context = context.WithValue(ctx, auditKey{}, call)
+			result, err := next(p)
+			if err != nil {
+				return result, err
+			}
+			entry := AuditEntry{
+				Operation: operationName(p.Info),
+				Field:     FieldPath(p.Info),
+				Time:      time.Now(),
+			}
+			if caller != nil {
+				entry.Caller = caller(ctx)
+			}
+			entry.Args = call.args
+			sink(ctx, entry)
+			return result, nil
+		}
+	})
+}
+
+// Audit sends audit entries for successful mutations on the default loader to sink.
+func Audit(sink AuditSink, caller func(ctx context.Context) string) {
+	Default().Audit(sink, caller)
+}
+
+// isMutationField reports whether info describes a root field of a mutation operation.
+func isMutationField(info graphql.ResolveInfo) bool {
+	op, ok := info.Operation.(*ast.OperationDefinition)
+	if !ok || op.Operation != ast.OperationTypeMutation {
+		return false
+	}
+	mutation := info.Schema.MutationType()
+	return mutation != nil && info.ParentType != nil && info.ParentType.Name() == mutation.Name()
+}
+
+// operationName returns the name of the operation being executed, or "" if it's anonymous.
+func operationName(info graphql.ResolveInfo) string {
+	if op, ok := info.Operation.(*ast.OperationDefinition); ok && op.Name != nil {
+		return op.Name.Value
+	}
+	return ""
+}
+
+func contextValue(ctx context.Context, key interface{}) interface{} {
+	if ctx == nil {
+		return nil
+	}
+	return ctx.Value(key)
+}
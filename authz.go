@@ -0,0 +1,143 @@
+package graphqlhelpers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	roleTag = "role"
+	permTag = "perm"
+)
+
+// AccessChecker decides whether the caller of a request, identified from its context, has a role or
+// permission named in a role or perm tag.
+type AccessChecker interface {
+	HasRole(ctx context.Context, role string) bool
+	HasPermission(ctx context.Context, perm string) bool
+}
+
+// AccessError is returned by LoadArgs when the caller lacks a role or permission required by the
+// args struct or one of the args they passed.
+type AccessError struct {
+	// Arg is the arg whose tag wasn't satisfied, or empty if the tag was on the struct itself.
+	Arg string
+	// Requirement is the unsatisfied tag, like `role:"admin"`.
+	Requirement string
+}
+
+func (e *AccessError) Error() string {
+	if e.Arg == "" {
+		return fmt.Sprintf("not authorized: requires %s", e.Requirement)
+	}
+	return fmt.Sprintf("not authorized to set %s: requires %s", e.Arg, e.Requirement)
+}
+
+// SetAccessChecker sets the checker enforcing role and perm tags.  A role tag lists roles separated
+// by commas, any one of which grants access; a perm tag lists permissions, all of which are needed.
+//
+// On an arg field, the tags restrict who may pass that arg.  To restrict the whole struct (and so
+// the field whose args it is), put them on a blank field:
+//
+//	type DeleteUserArgs struct {
+//		_     struct{} `role:"admin"`
+//		ID    string   `arg:"id" required:"true"`
+//		Purge bool     `arg:"purge" perm:"users:purge"`
+//	}
+//
+// If the loader has no checker, loading args with either tag always fails.
+func (e *ArgLoader) SetAccessChecker(c AccessChecker) {
+	e.accessChecker = c
+}
+
+// SetAccessChecker sets the checker enforcing role and perm tags on the default loader.
+func SetAccessChecker(c AccessChecker) {
+	Default().SetAccessChecker(c)
+}
+
+// authorize checks the role and perm tags of struct type t, and of the args in args, recursing into
+// the input objects that were passed.
+func (e *ArgLoader) authorize(ctx context.Context, t reflect.Type, args map[string]interface{}) error {
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.Name == "_" {
+			if err := e.checkAccess(ctx, field, ""); err != nil {
+				return err
+			}
+		}
+	}
+	for _, f := range e.planArgFields(t) {
+		val, _, passed := lookupArg(args, f)
+		if !passed || f.rest {
+			continue
+		}
+		if err := e.checkAccess(ctx, f.field, f.name); err != nil {
+			return err
+		}
+		if err := e.authorizeValue(ctx, f.field.Type, val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *ArgLoader) authorizeValue(ctx context.Context, t reflect.Type, val interface{}) error {
+	if _, registered := e.loaderFuncs[t]; registered {
+		return nil
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return e.authorizeValue(ctx, t.Elem(), val)
+	case reflect.Slice, reflect.Array:
+		items, _ := val.([]interface{})
+		for _, item := range items {
+			if err := e.authorizeValue(ctx, t.Elem(), item); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		if vt, ok := optionalValueType(t); ok {
+			return e.authorizeValue(ctx, vt, val)
+		}
+		if m, ok := val.(map[string]interface{}); ok {
+			return e.authorize(ctx, t, m)
+		}
+	}
+	return nil
+}
+
+// checkAccess checks the role and perm tags of field.
+func (e *ArgLoader) checkAccess(ctx context.Context, field reflect.StructField, arg string) error {
+	roles, hasRole := field.Tag.Lookup(roleTag)
+	perms, hasPerm := field.Tag.Lookup(permTag)
+	if !hasRole && !hasPerm {
+		return nil
+	}
+	if e.accessChecker == nil {
+		return fmt.Errorf("cannot enforce the role and perm tags on %s: no AccessChecker has been set", field.Name)
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if hasRole {
+		ok := false
+		for _, role := range strings.Split(roles, ",") {
+			if e.accessChecker.HasRole(ctx, strings.TrimSpace(role)) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return &AccessError{Arg: arg, Requirement: fmt.Sprintf("%s:%q", roleTag, roles)}
+		}
+	}
+	if hasPerm {
+		for _, perm := range strings.Split(perms, ",") {
+			if !e.accessChecker.HasPermission(ctx, strings.TrimSpace(perm)) {
+				return &AccessError{Arg: arg, Requirement: fmt.Sprintf("%s:%q", permTag, strings.TrimSpace(perm))}
+			}
+		}
+	}
+	return nil
+}
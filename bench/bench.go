@@ -0,0 +1,247 @@
+// Package bench benchmarks ArgsConfig and LoadArgs on representative args structs, and compares
+// the results with a stored baseline, so that changes meant to speed them up can be measured and
+// later changes can be kept from slowing them down again.
+//
+// Run the benchmarks with go test -bench . in this directory, with the bench command, which compares
+// them with baseline.json, or call them from a _test.go file of your own:
+//
+//	func BenchmarkLoadArgs(b *testing.B) { bench.LoadArgsNested(b) }
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+
+	graphqlhelpers "github.com/btubbs/graphql-go-helpers"
+)
+
+// ScalarArgs holds only built-in scalars.
+type ScalarArgs struct {
+	ID     string  `arg:"id" required:"true"`
+	Name   string  `arg:"name"`
+	Limit  int     `arg:"limit"`
+	Score  float64 `arg:"score"`
+	Active bool    `arg:"active"`
+}
+
+// Address is an input object nested in NestedArgs.
+type Address struct {
+	Street  string `arg:"street" required:"true"`
+	City    string `arg:"city" required:"true"`
+	Country string `arg:"country" transform:"upper"`
+}
+
+// NestedArgs holds an input object, a pointer and a time.
+type NestedArgs struct {
+	ID      string     `arg:"id" required:"true"`
+	Address Address    `arg:"address"`
+	Billing *Address   `arg:"billing"`
+	Since   time.Time  `arg:"since"`
+	Until   *time.Time `arg:"until"`
+}
+
+// ListArgs holds lists of scalars and of input objects.
+type ListArgs struct {
+	IDs       []string  `arg:"ids" elems:"nonnull" minitems:"1"`
+	Limits    []int     `arg:"limits"`
+	Addresses []Address `arg:"addresses"`
+}
+
+var (
+	scalarArgs = map[string]interface{}{
+		"id": "u1", "name": "Ada", "limit": 20, "score": 0.5, "active": true,
+	}
+	address = map[string]interface{}{
+		"street": "1 Main St", "city": "Springfield", "country": "us",
+	}
+	nestedArgs = map[string]interface{}{
+		"id": "u1", "address": address, "billing": address,
+		"since": time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), "until": "2021-01-02T03:04:05Z",
+	}
+	listArgs = map[string]interface{}{
+		"ids":       []interface{}{"a", "b", "c", "d", "e", "f", "g", "h"},
+		"limits":    []interface{}{1, 2, 3, 4},
+		"addresses": []interface{}{address, address, address},
+	}
+)
+
+// Benchmark is a named benchmark.
+type Benchmark struct {
+	Name string
+	F    func(b *testing.B)
+}
+
+// Benchmarks lists the package's benchmarks, in the order Run runs them.
+var Benchmarks = []Benchmark{
+	{Name: "ArgsConfig/scalars", F: ArgsConfigScalars},
+	{Name: "ArgsConfig/nested", F: ArgsConfigNested},
+	{Name: "LoadArgs/scalars", F: LoadArgsScalars},
+	{Name: "LoadArgs/nested", F: LoadArgsNested},
+	{Name: "LoadArgs/lists", F: LoadArgsLists},
+}
+
+// ArgsConfigScalars benchmarks ArgsConfig on ScalarArgs.
+func ArgsConfigScalars(b *testing.B) {
+	benchArgsConfig(b, ScalarArgs{})
+}
+
+// ArgsConfigNested benchmarks ArgsConfig on NestedArgs, once its input objects have been
+// generated.
+func ArgsConfigNested(b *testing.B) {
+	benchArgsConfig(b, NestedArgs{})
+}
+
+// LoadArgsScalars benchmarks LoadArgs into ScalarArgs.
+func LoadArgsScalars(b *testing.B) {
+	benchLoadArgs(b, scalarArgs, func() interface{} { return &ScalarArgs{} })
+}
+
+// LoadArgsNested benchmarks LoadArgs into NestedArgs.
+func LoadArgsNested(b *testing.B) {
+	benchLoadArgs(b, nestedArgs, func() interface{} { return &NestedArgs{} })
+}
+
+// LoadArgsLists benchmarks LoadArgs into ListArgs.
+func LoadArgsLists(b *testing.B) {
+	benchLoadArgs(b, listArgs, func() interface{} { return &ListArgs{} })
+}
+
+func newLoader(b *testing.B) *graphqlhelpers.ArgLoader {
+	l, err := graphqlhelpers.New()
+	if err != nil {
+		b.Fatal(err)
+	}
+	return l
+}
+
+func benchArgsConfig(b *testing.B, args interface{}) {
+	l := newLoader(b)
+	if _, err := l.SafeArgsConfig(args); err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.ArgsConfig(args)
+	}
+}
+
+func benchLoadArgs(b *testing.B, args map[string]interface{}, target func() interface{}) {
+	l := newLoader(b)
+	p := graphql.ResolveParams{Args: args}
+	if err := l.LoadArgs(p, target()); err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := l.LoadArgs(p, target()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Result is the outcome of one benchmark.
+type Result struct {
+	Name        string  `json:"name"`
+	NsPerOp     float64 `json:"nsPerOp"`
+	BytesPerOp  int64   `json:"bytesPerOp"`
+	AllocsPerOp int64   `json:"allocsPerOp"`
+}
+
+// Run runs the benchmarks whose names match filter, a regular expression, or all of them if it's
+// empty.
+func Run(filter string) ([]Result, error) {
+	re, err := regexp.Compile(filter)
+	if err != nil {
+		return nil, fmt.Errorf("bad filter: %v", err)
+	}
+	var out []Result
+	for _, bm := range Benchmarks {
+		if !re.MatchString(bm.Name) {
+			continue
+		}
+		r := testing.Benchmark(bm.F)
+		if r.N == 0 {
+			return nil, fmt.Errorf("benchmark %s failed", bm.Name)
+		}
+		out = append(out, Result{
+			Name:        bm.Name,
+			NsPerOp:     float64(r.T.Nanoseconds()) / float64(r.N),
+			BytesPerOp:  r.AllocedBytesPerOp(),
+			AllocsPerOp: r.AllocsPerOp(),
+		})
+	}
+	return out, nil
+}
+
+// SaveBaseline writes results to the JSON file at path, to compare later runs against.
+func SaveBaseline(path string, results []Result) error {
+	b, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(b, '\n'), 0644)
+}
+
+// LoadBaseline reads results written by SaveBaseline.
+func LoadBaseline(path string) ([]Result, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var results []Result
+	if err := json.Unmarshal(b, &results); err != nil {
+		return nil, fmt.Errorf("cannot read baseline %s: %v", path, err)
+	}
+	return results, nil
+}
+
+// Regression is a benchmark measure that got worse than its baseline allows.
+type Regression struct {
+	Name string
+	// Measure is "ns/op", "B/op" or "allocs/op".
+	Measure  string
+	Baseline float64
+	Current  float64
+}
+
+func (r Regression) String() string {
+	return fmt.Sprintf("%s: %s went from %.0f to %.0f", r.Name, r.Measure, r.Baseline, r.Current)
+}
+
+// Compare returns the regressions of current from baseline.  A benchmark's time and bytes per op
+// may grow by the fraction tolerance, so 0.1 allows 10%, since they vary from run to run;
+// allocations per op don't vary, so any more of them is a regression.  Benchmarks missing from
+// either side are skipped.
+func Compare(baseline, current []Result, tolerance float64) []Regression {
+	base := map[string]Result{}
+	for _, r := range baseline {
+		base[r.Name] = r
+	}
+	var out []Regression
+	for _, cur := range current {
+		old, ok := base[cur.Name]
+		if !ok {
+			continue
+		}
+		if cur.NsPerOp > old.NsPerOp*(1+tolerance) {
+			out = append(out, Regression{Name: cur.Name, Measure: "ns/op", Baseline: old.NsPerOp, Current: cur.NsPerOp})
+		}
+		if float64(cur.BytesPerOp) > float64(old.BytesPerOp)*(1+tolerance) {
+			out = append(out, Regression{Name: cur.Name, Measure: "B/op", Baseline: float64(old.BytesPerOp), Current: float64(cur.BytesPerOp)})
+		}
+		if cur.AllocsPerOp > old.AllocsPerOp {
+			out = append(out, Regression{Name: cur.Name, Measure: "allocs/op", Baseline: float64(old.AllocsPerOp), Current: float64(cur.AllocsPerOp)})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
@@ -0,0 +1,13 @@
+package bench
+
+import "testing"
+
+func BenchmarkArgsConfigScalars(b *testing.B) { ArgsConfigScalars(b) }
+
+func BenchmarkArgsConfigNested(b *testing.B) { ArgsConfigNested(b) }
+
+func BenchmarkLoadArgsScalars(b *testing.B) { LoadArgsScalars(b) }
+
+func BenchmarkLoadArgsNested(b *testing.B) { LoadArgsNested(b) }
+
+func BenchmarkLoadArgsLists(b *testing.B) { LoadArgsLists(b) }
@@ -0,0 +1,135 @@
+package graphqlhelpers
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/graphql-go/graphql"
+)
+
+// ErrCircuitOpen is the error a field fails with while its circuit breaker is refusing calls.
+var ErrCircuitOpen = errors.New("temporarily unavailable")
+
+// CircuitBreaker tracks the health of the dependencies behind resolvers, which it knows by key,
+// and refuses calls to those that keep failing, so they get time to recover.
+type CircuitBreaker interface {
+	// Allow reports whether a call for key may go ahead.
+	Allow(key string) bool
+	// Record reports the outcome of a call that Allow let go ahead, with a nil err for success.
+	Record(key string, err error)
+}
+
+// CircuitBreakerPolicy configures the breaker returned by NewCircuitBreaker.
+type CircuitBreakerPolicy struct {
+	// Failures is how many calls in a row must fail to open the circuit.  It defaults to 5.
+	Failures int
+	// Cooldown is how long an open circuit refuses calls before letting one through to see if the
+	// dependency has recovered.  It defaults to 30s.
+	Cooldown time.Duration
+	// IsFailure reports whether err counts against the dependency.  It defaults to counting the
+	// errors IsRetryable is true for, except context.Canceled, since those come from callers going
+	// away rather than the dependency failing.
+	IsFailure func(err error) bool
+}
+
+// NewCircuitBreaker returns an in-memory CircuitBreaker, with a separate circuit for each key.  A
+// circuit opens after policy.Failures failures in a row, and stays open for policy.Cooldown.  Then
+// it lets one call through: if that succeeds the circuit closes again, and otherwise it opens for
+// another cooldown.
+func NewCircuitBreaker(policy CircuitBreakerPolicy) CircuitBreaker {
+	if policy.Failures <= 0 {
+		policy.Failures = 5
+	}
+	if policy.Cooldown <= 0 {
+		policy.Cooldown = 30 * time.Second
+	}
+	if policy.IsFailure == nil {
+		policy.IsFailure = func(err error) bool {
+			return IsRetryable(err) && !errors.Is(err, context.Canceled)
+		}
+	}
+	return &memoryBreaker{policy: policy, circuits: map[string]*circuit{}}
+}
+
+type memoryBreaker struct {
+	policy   CircuitBreakerPolicy
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+// circuit is the state of one key.  It's open while openUntil is in the future; once that has
+// passed, trial is set while the one call let through is running.
+type circuit struct {
+	failures  int
+	openUntil time.Time
+	trial     bool
+}
+
+func (b *memoryBreaker) Allow(key string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok := b.circuits[key]
+	if !ok || c.openUntil.IsZero() {
+		return true
+	}
+	if time.Now().Before(c.openUntil) || c.trial {
+		return false
+	}
+	c.trial = true
+	return true
+}
+
+func (b *memoryBreaker) Record(key string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok := b.circuits[key]
+	if !ok {
+		c = &circuit{}
+		b.circuits[key] = c
+	}
+	trial := c.trial
+	c.trial = false
+	if err == nil || !b.policy.IsFailure(err) {
+		if err == nil || trial {
+			*c = circuit{}
+		}
+		return
+	}
+	c.failures++
+	if trial || c.failures >= b.policy.Failures {
+		c.openUntil = time.Now().Add(b.policy.Cooldown)
+	}
+}
+
+// Breaker returns middleware guarding the resolvers it wraps with b, keyed by the field's schema
+// coordinate, like "Query.weather".  While b refuses calls for a field, it fails straight away with
+// ErrCircuitOpen instead of calling the resolver, so the rest of the query still resolves.
+func Breaker(b CircuitBreaker) Middleware {
+	return func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (interface{}, error) {
+			key := FieldPath(p.Info)
+			if !b.Allow(key) {
+				return nil, ErrCircuitOpen
+			}
+			defer func() {
+				// a panicking call counts as a failure, so a trial call can't leave the circuit stuck.
+				if r := recover(); r != nil {
+					b.Record(key, ErrInternal)
+					panic(r)
+				}
+			}()
+			result, err := next(p)
+			b.Record(key, err)
+			return result, err
+		}
+	}
+}
+
+// WithCircuitBreaker guards the field's resolver with b, with Breaker middleware inside any
+// middleware installed on the loader with Use.  Fields sharing a dependency can share a breaker,
+// but each field still has its own circuit.
+func WithCircuitBreaker(b CircuitBreaker) FieldOption {
+	return WithMiddleware(Breaker(b))
+}
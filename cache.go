@@ -0,0 +1,131 @@
+package graphqlhelpers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+const cacheTag = "cache"
+
+// ResultCache stores the results of fields with a cache tag or the WithCache option.
+type ResultCache interface {
+	// Get returns the value stored for key, if it hasn't expired.
+	Get(ctx context.Context, key string) (interface{}, bool)
+	// Set stores value for key, for ttl.
+	Set(ctx context.Context, key string, value interface{}, ttl time.Duration)
+}
+
+// WithCache caches the field's results for ttl, keyed by its loaded args, as with a cache tag.
+func WithCache(ttl time.Duration) FieldOption {
+	return func(o *fieldOptions) {
+		o.cacheTTL = ttl
+	}
+}
+
+// SetResultCache sets the backend that results of cached fields are stored in.  By default each
+// loader has its own in-memory cache, from NewMemoryCache; set a shared store, like one backed by
+// Redis, to share results between processes.
+//
+// A field built by FieldFromFunc is cached when it has the WithCache option, or when its args
+// struct has a cache tag holding a duration on a blank field:
+//
+//	type ExchangeRateArgs struct {
+//		_    struct{} `cache:"60s"`
+//		From string   `arg:"from" required:"true"`
+//		To   string   `arg:"to" required:"true"`
+//	}
+//
+// Args are still loaded (and authorized) for every call, then the result for args with the same
+// KeyedHashArgs, redacted args included, is reused until it expires.  Hashes are keyed with the key
+// from SetHashKey, which processes sharing a store must agree on.  Errors aren't cached.  Results are
+// shared by every caller, so don't cache fields whose results depend on who is asking.
+func (e *ArgLoader) SetResultCache(c ResultCache) {
+	e.resultCache = c
+}
+
+// SetResultCache sets the backend that the default loader's cached fields are stored in.
+func SetResultCache(c ResultCache) {
+	Default().SetResultCache(c)
+}
+
+// cacheTTL returns the duration in the cache tag of args struct type t, or 0 if it has none.
+func cacheTTL(t reflect.Type) (time.Duration, error) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		raw, ok := field.Tag.Lookup(cacheTag)
+		if !ok || field.Name != "_" {
+			continue
+		}
+		ttl, err := time.ParseDuration(raw)
+		if err != nil {
+			return 0, fmt.Errorf("cannot read %s tag on %v: %v", cacheTag, t, err)
+		}
+		return ttl, nil
+	}
+	return 0, nil
+}
+
+// cacheKey returns the key that the result of the field at coordinate, like "Query.rate", for args
+// is cached under.  args may be nil for funcs without an args param.  Keys name the field rather
+// than its resolver func, since closures made by one func literal share its name.
+func (e *ArgLoader) cacheKey(coordinate string, args interface{}) (string, error) {
+	if args == nil {
+		return coordinate, nil
+	}
+	key, err := e.resultHashKey()
+	if err != nil {
+		return "", err
+	}
+	hash, err := e.KeyedHashArgs(key, args)
+	if err != nil {
+		return "", err
+	}
+	return coordinate + ":" + hash, nil
+}
+
+// memoryCache is a ResultCache held in memory.
+type memoryCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryCacheEntry
+	// the number of entries after the last sweep of expired entries.
+	swept int
+}
+
+type memoryCacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// NewMemoryCache returns a ResultCache that holds results in memory, for one process.
+func NewMemoryCache() ResultCache {
+	return &memoryCache{entries: map[string]memoryCacheEntry{}}
+}
+
+func (m *memoryCache) Get(ctx context.Context, key string) (interface{}, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (m *memoryCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	m.entries[key] = memoryCacheEntry{value: value, expires: now.Add(ttl)}
+	if len(m.entries) > 2*m.swept+64 {
+		// drop expired entries whenever the cache has doubled in size, so it doesn't grow forever.
+		for k, entry := range m.entries {
+			if now.After(entry.expires) {
+				delete(m.entries, k)
+			}
+		}
+		m.swept = len(m.entries)
+	}
+}
@@ -0,0 +1,68 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Call loads p's args into the args struct accepted by fn and calls it, so small resolvers can be
+// written as plain funcs of their args:
+//
+//	Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+//		return graphqlhelpers.Call(p, func(args UserArgs) (*User, error) {
+//			return store.User(args.ID)
+//		})
+//	},
+//
+// fn has the form func(Args) (Result, error), where Args is an args struct or a pointer to one, and
+// may also take a context.Context first, which is p's context.  An error loading the args is
+// returned without calling fn.  If fn doesn't have a supported signature, Call returns an error.
+func (e *ArgLoader) Call(p graphql.ResolveParams, fn interface{}) (interface{}, error) {
+	t := reflect.TypeOf(fn)
+	if t == nil || t.Kind() != reflect.Func {
+		return nil, fmt.Errorf("%v is not a func", fn)
+	}
+	v := reflect.ValueOf(fn)
+	var in []reflect.Value
+	argsIndex := 0
+	if t.NumIn() == 2 && t.In(0) == contextType {
+		in = append(in, reflect.ValueOf(nodeContext(p)))
+		argsIndex = 1
+	}
+	if t.NumIn() != argsIndex+1 || t.NumOut() != 2 || !t.Out(1).Implements(errorType) {
+		return nil, fmt.Errorf(
+			"func should accept an args struct and return a result and an error. %s is %v", funcName(v), t)
+	}
+	argsType := t.In(argsIndex)
+	structType := argsType
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s's args param should be a struct, not %v", funcName(v), argsType)
+	}
+	args := reflect.New(structType)
+	if err := e.LoadArgs(p, args.Interface()); err != nil {
+		return nil, err
+	}
+	if argsType.Kind() != reflect.Ptr {
+		args = args.Elem()
+	}
+	out := v.Call(append(in, args))
+	if !out[1].IsNil() {
+		return nil, out[1].Interface().(error)
+	}
+	return out[0].Interface(), nil
+}
+
+func funcName(v reflect.Value) string {
+	return runtime.FuncForPC(v.Pointer()).Name()
+}
+
+// Call loads p's args into the args struct accepted by fn and calls it, using the default loader.
+func Call(p graphql.ResolveParams, fn interface{}) (interface{}, error) {
+	return Default().Call(p, fn)
+}
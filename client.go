@@ -0,0 +1,96 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Request is a graphql request built from an args struct, in the shape graphql servers expect as a
+// JSON POST body.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// BuildRequest builds a request calling a single field, with its arguments passed as variables.
+// opType is "query", "mutation" or "subscription", opName names the operation, and selection is the
+// selection set for the field's result (without braces), or empty for scalar results.  Only the
+// args that DumpArgs includes are declared and passed, so omitted optional args stay omitted.
+//
+// For example, BuildRequest("query", "Hello", "hello", helloArgs{Name: "Joe"}, "") produces
+//
+//	query Hello($name: String) { hello(name: $name) }
+func (e *ArgLoader) BuildRequest(opType, opName, field string, args interface{}, selection string) (Request, error) {
+	conf, err := e.SafeArgsConfig(args)
+	if err != nil {
+		return Request{}, err
+	}
+	vars, err := e.DumpArgs(args)
+	if err != nil {
+		return Request{}, err
+	}
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		if _, ok := conf[name]; !ok {
+			return Request{}, fmt.Errorf("cannot declare a variable for %s: it has no argument config", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var decls, uses []string
+	for _, name := range names {
+		decls = append(decls, fmt.Sprintf("$%s: %s", name, conf[name].Type.String()))
+		uses = append(uses, fmt.Sprintf("%s: $%s", name, name))
+	}
+
+	var b strings.Builder
+	b.WriteString(opType)
+	if opName != "" {
+		b.WriteString(" " + opName)
+	}
+	if len(decls) > 0 {
+		b.WriteString("(" + strings.Join(decls, ", ") + ")")
+	}
+	b.WriteString(" { " + field)
+	if len(uses) > 0 {
+		b.WriteString("(" + strings.Join(uses, ", ") + ")")
+	}
+	if selection != "" {
+		b.WriteString(" { " + selection + " }")
+	}
+	b.WriteString(" }")
+
+	return Request{Query: b.String(), OperationName: opName, Variables: vars}, nil
+}
+
+// VariableDefinitions returns the variable definitions (like "$name: String, $limit: Int") for all
+// of the args in an args struct, for callers that write their own query text.
+func (e *ArgLoader) VariableDefinitions(args interface{}) (string, error) {
+	conf, err := e.SafeArgsConfig(args)
+	if err != nil {
+		return "", err
+	}
+	names := make([]string, 0, len(conf))
+	for name := range conf {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	decls := make([]string, len(names))
+	for idx, name := range names {
+		decls[idx] = fmt.Sprintf("$%s: %s", name, conf[name].Type.String())
+	}
+	return strings.Join(decls, ", "), nil
+}
+
+// BuildRequest builds a request calling a single field using the default loader.
+func BuildRequest(opType, opName, field string, args interface{}, selection string) (Request, error) {
+	return Default().BuildRequest(opType, opName, field, args, selection)
+}
+
+// VariableDefinitions returns the variable definitions for an args struct using the default loader.
+func VariableDefinitions(args interface{}) (string, error) {
+	return Default().VariableDefinitions(args)
+}
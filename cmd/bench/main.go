@@ -0,0 +1,56 @@
+// Command bench runs the graphqlhelpers benchmarks and compares them with a stored baseline,
+// exiting with status 1 if any of them regressed, so it can gate changes in CI.  The baseline
+// defaults to bench/baseline.json, committed with the code, so run it from the repository root:
+//
+//	go run ./cmd/bench -update   # record a new baseline
+//	go run ./cmd/bench           # compare with it
+//	go run ./cmd/bench -baseline ""   # just print the results
+//
+// The committed baseline was recorded on one machine; record one on the machine that runs the
+// comparison, such as the CI runner, for times and bytes to be comparable.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/btubbs/graphql-go-helpers/bench"
+)
+
+func main() {
+	baseline := flag.String("baseline", "bench/baseline.json", "JSON file of baseline results")
+	update := flag.Bool("update", false, "write the results to the baseline file instead of comparing")
+	tolerance := flag.Float64("tolerance", 0.2, "fraction by which time and bytes per op may grow")
+	filter := flag.String("run", "", "regular expression selecting the benchmarks to run")
+	flag.Parse()
+
+	results, err := bench.Run(*filter)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, r := range results {
+		fmt.Printf("%-24s %12.0f ns/op %8d B/op %6d allocs/op\n", r.Name, r.NsPerOp, r.BytesPerOp, r.AllocsPerOp)
+	}
+	if *baseline == "" {
+		return
+	}
+	if *update {
+		if err := bench.SaveBaseline(*baseline, results); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	base, err := bench.LoadBaseline(*baseline)
+	if err != nil {
+		log.Fatal(err)
+	}
+	regressions := bench.Compare(base, results, *tolerance)
+	for _, r := range regressions {
+		fmt.Println("regression:", r)
+	}
+	if len(regressions) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,36 @@
+// Command docgen generates a func registering the doc comments of a package's arg and output
+// struct fields as graphqlhelpers descriptions.
+//
+// Usage, typically from a go:generate directive in the package:
+//
+//	docgen -dir . -out descriptions_gen.go -func RegisterDescriptions
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/btubbs/graphql-go-helpers/docgen"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "directory of the package to read")
+	out := flag.String("out", "", "Go file to write (defaults to stdout)")
+	fn := flag.String("func", "RegisterDescriptions", "name of the generated func")
+	implicit := flag.Bool("implicit", false, "include exported fields without arg or field tags")
+	flag.Parse()
+
+	src, err := docgen.Generate(*dir, docgen.Config{Func: *fn, Implicit: *implicit})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *out == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := ioutil.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,42 @@
+// Command openapigen generates graphqlhelpers args structs from the operations of an OpenAPI 3
+// document.
+//
+// Usage:
+//
+//	openapigen -spec openapi.yaml -pkg petstore -out args_gen.go
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/btubbs/graphql-go-helpers/openapigen"
+)
+
+func main() {
+	spec := flag.String("spec", "", "OpenAPI 3 document to read, in YAML or JSON")
+	out := flag.String("out", "", "Go file to write (defaults to stdout)")
+	pkg := flag.String("pkg", "api", "package of the generated file")
+	flag.Parse()
+
+	if *spec == "" {
+		log.Fatal("-spec is required")
+	}
+	doc, err := ioutil.ReadFile(*spec)
+	if err != nil {
+		log.Fatal(err)
+	}
+	src, err := openapigen.Generate(doc, openapigen.Config{Package: *pkg})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *out == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := ioutil.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
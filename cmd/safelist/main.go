@@ -0,0 +1,105 @@
+// Command safelist extracts the GraphQL documents from client code and writes them as a safelist
+// manifest, a JSON object mapping each document's hash to its normalized text, to be loaded with
+// graphqlhelpers.ReadSafelist.
+//
+// Documents are read from .graphql and .gql files, one per file, and from gql`...` and
+// graphql`...` template literals in .js, .jsx, .ts and .tsx files.  Literals that interpolate
+// other documents (with ${...}) can't be extracted, and are reported instead; move their fragments
+// into the literal, or into .graphql files.
+//
+// Usage:
+//
+//	safelist [-o safelist.json] src/...
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	graphqlhelpers "github.com/btubbs/graphql-go-helpers"
+)
+
+var templateLiteral = regexp.MustCompile("(?:gql|graphql)\\s*`([^`]*)`")
+
+func main() {
+	out := flag.String("o", "", "file to write the manifest to, instead of standard output")
+	flag.Parse()
+	if flag.NArg() == 0 {
+		log.Fatal("usage: safelist [-o safelist.json] path...")
+	}
+
+	manifest := map[string]string{}
+	for _, root := range flag.Args() {
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+			docs, err := extract(path)
+			if err != nil {
+				return err
+			}
+			for _, doc := range docs {
+				normalized, err := graphqlhelpers.NormalizeQuery(doc)
+				if err != nil {
+					return fmt.Errorf("%s: %v", path, err)
+				}
+				hash, _ := graphqlhelpers.QueryHash(normalized)
+				manifest[hash] = normalized
+			}
+			return nil
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	b, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	b = append(b, '\n')
+	if *out == "" {
+		os.Stdout.Write(b)
+		return
+	}
+	if err := ioutil.WriteFile(*out, b, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// extract returns the GraphQL documents in the file at path.
+func extract(path string) ([]string, error) {
+	switch filepath.Ext(path) {
+	case ".graphql", ".gql":
+	case ".js", ".jsx", ".ts", ".tsx":
+	default:
+		return nil, nil
+	}
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch filepath.Ext(path) {
+	case ".graphql", ".gql":
+		if strings.TrimSpace(string(src)) == "" {
+			return nil, nil
+		}
+		return []string{string(src)}, nil
+	}
+	var docs []string
+	for _, m := range templateLiteral.FindAllStringSubmatch(string(src), -1) {
+		if strings.Contains(m[1], "${") {
+			log.Printf("%s: skipping a document with interpolations", path)
+			continue
+		}
+		docs = append(docs, m[1])
+	}
+	return docs, nil
+}
@@ -0,0 +1,44 @@
+// Command schemadiff prints the differences between two SDL files, and exits non-zero if any of
+// them are breaking, so it can guard a checked-in schema snapshot in CI.
+//
+// Usage:
+//
+//	schemadiff [-dangerous] old.graphql new.graphql
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/btubbs/graphql-go-helpers/schemadiff"
+)
+
+func main() {
+	failDangerous := flag.Bool("dangerous", false, "exit non-zero on dangerous changes too")
+	flag.Parse()
+	if flag.NArg() != 2 {
+		log.Fatal("usage: schemadiff [-dangerous] old.graphql new.graphql")
+	}
+	oldSDL, err := ioutil.ReadFile(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	newSDL, err := ioutil.ReadFile(flag.Arg(1))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	changes, err := schemadiff.DiffSDL(string(oldSDL), string(newSDL))
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, c := range changes {
+		fmt.Println(c)
+	}
+	if len(schemadiff.Only(changes, schemadiff.Breaking)) > 0 || (*failDangerous && len(schemadiff.Only(changes, schemadiff.Dangerous)) > 0) {
+		os.Exit(1)
+	}
+}
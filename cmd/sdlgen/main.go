@@ -0,0 +1,66 @@
+// Command sdlgen generates Go arg structs for graphqlhelpers from a GraphQL SDL file.
+//
+// Usage:
+//
+//	sdlgen -in schema.graphql -out args_gen.go -pkg api -scalar UUID=uuid.UUID -import github.com/google/uuid
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/btubbs/graphql-go-helpers/sdlgen"
+)
+
+// listFlag collects a flag that may be given more than once.
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
+func main() {
+	in := flag.String("in", "", "SDL file to read")
+	out := flag.String("out", "", "Go file to write (defaults to stdout)")
+	pkg := flag.String("pkg", "main", "package name of the generated file")
+	var scalars, imports listFlag
+	flag.Var(&scalars, "scalar", "custom scalar mapping, as Name=GoType (repeatable)")
+	flag.Var(&imports, "import", "import path needed by a scalar mapping (repeatable)")
+	flag.Parse()
+
+	if *in == "" {
+		log.Fatal("-in is required")
+	}
+	sdl, err := ioutil.ReadFile(*in)
+	if err != nil {
+		log.Fatal(err)
+	}
+	conf := sdlgen.Config{Package: *pkg, Scalars: map[string]string{}, Imports: imports}
+	for _, s := range scalars {
+		parts := strings.SplitN(s, "=", 2)
+		if len(parts) != 2 {
+			log.Fatalf("invalid -scalar %q. use Name=GoType", s)
+		}
+		conf.Scalars[parts[0]] = parts[1]
+	}
+
+	src, err := sdlgen.Generate(sdl, conf)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *out == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := ioutil.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,72 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/graphql-go/graphql"
+)
+
+// computeTag names the method that resolves an output struct field, for fields that are expensive
+// to work out.  Their resolvers call the method, so it only runs when a client selects the field,
+// and resolvers returning the struct can leave the field itself unset:
+//
+//	type User struct {
+//		ID    string  `field:"id"`
+//		Score float64 `field:"score" compute:"ComputeScore"`
+//	}
+//
+//	func (u *User) ComputeScore(ctx context.Context) (float64, error) {...}
+//
+// The field's Go type sets its graphql type.  The method may take a context.Context, and returns a
+// value of the field's type, optionally followed by an error.
+const computeTag = "compute"
+
+// computeResolver returns the resolver of the field of struct type t with a compute tag, or nil if
+// field has no compute tag.
+func computeResolver(t reflect.Type, field reflect.StructField) (graphql.FieldResolveFn, error) {
+	name, ok := field.Tag.Lookup(computeTag)
+	if !ok {
+		return nil, nil
+	}
+	method, ok := reflect.PtrTo(t).MethodByName(name)
+	if !ok {
+		return nil, fmt.Errorf("%v has no method named %s", t, name)
+	}
+	mt := method.Type
+	takesContext := mt.NumIn() == 2 && mt.In(1) == contextType
+	if mt.NumIn() > 2 || (mt.NumIn() == 2 && !takesContext) {
+		return nil, fmt.Errorf("%s should take no arguments or a context.Context", name)
+	}
+	returnsError := mt.NumOut() == 2 && mt.Out(1) == errorType
+	if mt.NumOut() < 1 || mt.NumOut() > 2 || (mt.NumOut() == 2 && !returnsError) || !mt.Out(0).AssignableTo(field.Type) {
+		return nil, fmt.Errorf("%s should return a %v, optionally followed by an error", name, field.Type)
+	}
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		v := reflect.ValueOf(p.Source)
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			return nil, nil
+		}
+		if v.Kind() != reflect.Ptr {
+			// copy the source so that methods with pointer receivers can be called on it.
+			if !v.IsValid() || v.Type() != t {
+				return nil, fmt.Errorf("cannot resolve %s: expected a %v source, got %v", p.Info.FieldName, t, reflect.TypeOf(p.Source))
+			}
+			ptr := reflect.New(t)
+			ptr.Elem().Set(v)
+			v = ptr
+		}
+		if v.Type().Elem() != t {
+			return nil, fmt.Errorf("cannot resolve %s: expected a %v source, got %v", p.Info.FieldName, t, v.Type())
+		}
+		in := []reflect.Value{v}
+		if takesContext {
+			in = append(in, reflect.ValueOf(nodeContext(p)))
+		}
+		out := method.Func.Call(in)
+		if returnsError && !out[1].IsNil() {
+			return nil, out[1].Interface().(error)
+		}
+		return out[0].Interface(), nil
+	}, nil
+}
@@ -0,0 +1,237 @@
+package graphqlhelpers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"strconv"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+)
+
+const (
+	// lazyTag marks an output struct field holding a func that computes the field's value, like
+	// `field:"bio" lazy:"true"` on a field of type func() (string, error).  The func is only called
+	// if a client selects the field, so resolvers can return the struct without doing the work up
+	// front.  The graphql type of the field is that of the func's result.
+	lazyTag = "lazy"
+
+	// concurrentTag marks an output struct field, like `field:"stats" concurrent:"true"`, whose
+	// resolver doesn't depend on the object's other fields, so it can run alongside the object's
+	// other concurrent fields instead of one after another.  Fields that take args, like fields
+	// replaced with AddFieldConfig, are resolved in turn as usual, since their results differ from
+	// one selection of the field to the next.
+	concurrentTag = "concurrent"
+)
+
+// lazyType returns the type of the value computed by field, a struct field with a lazy tag, or nil
+// if field isn't lazy.
+func lazyType(field reflect.StructField) (reflect.Type, error) {
+	if lazy, _ := strconv.ParseBool(field.Tag.Get(lazyTag)); !lazy {
+		return nil, nil
+	}
+	if _, ok := field.Tag.Lookup(computeTag); ok {
+		return nil, fmt.Errorf("a field cannot have both %s and %s tags", lazyTag, computeTag)
+	}
+	t := field.Type
+	if t.Kind() != reflect.Func || t.NumIn() != 0 || t.NumOut() < 1 || t.NumOut() > 2 ||
+		(t.NumOut() == 2 && t.Out(1) != errorType) {
+		return nil, fmt.Errorf("lazy fields should be a func() T or func() (T, error), not %v", t)
+	}
+	return t.Out(0), nil
+}
+
+// lazyResolver wraps resolve, which resolves a lazy field to its func, to call the func.
+func lazyResolver(resolve graphql.FieldResolveFn) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		result, err := resolve(p)
+		if err != nil || result == nil {
+			return result, err
+		}
+		fn := reflect.ValueOf(result)
+		if fn.IsNil() {
+			return nil, nil
+		}
+		out := fn.Call(nil)
+		if len(out) == 2 && !out[1].IsNil() {
+			return nil, out[1].Interface().(error)
+		}
+		return out[0].Interface(), nil
+	}
+}
+
+// concurrentFields returns the names of the output fields of struct type t with concurrent tags.
+func concurrentFields(t reflect.Type) []string {
+	var names []string
+	for _, f := range outputFields(t) {
+		if concurrent, _ := strconv.ParseBool(f.field.Tag.Get(concurrentTag)); concurrent {
+			names = append(names, f.name)
+		}
+	}
+	return names
+}
+
+// prefetched is an object value whose concurrent fields have already been resolved, by field name.
+// The resolvers of objects with concurrent fields look up their results here, and resolve their
+// other fields from source as usual.
+type prefetched struct {
+	source  interface{}
+	results map[string]prefetchResult
+}
+
+type prefetchResult struct {
+	value interface{}
+	err   error
+}
+
+// concurrentResolver wraps resolve, which resolves values of type t, so that when t is a struct with
+// concurrent fields (or a list of them), the concurrent fields the client selected are resolved
+// together, for every value at once, before graphql-go resolves the rest of the fields in turn.
+// As with an errgroup, the context of the concurrent resolvers is canceled when one of them fails,
+// but each failure is still reported on the field it happened on.
+func (e *ArgLoader) concurrentResolver(t reflect.Type, resolve graphql.FieldResolveFn) graphql.FieldResolveFn {
+	elem, list := t, false
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	if elem.Kind() == reflect.Slice || elem.Kind() == reflect.Array {
+		elem, list = elem.Elem(), true
+		if elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+	}
+	if elem.Kind() != reflect.Struct {
+		return resolve
+	}
+	names := concurrentFields(elem)
+	if len(names) == 0 {
+		return resolve
+	}
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		result, err := resolve(p)
+		if err != nil || isNilValue(result) {
+			return result, err
+		}
+		obj, ok := e.objects[elem]
+		if !ok {
+			return result, nil
+		}
+		selected := SelectedFields(p)
+		defs := obj.Fields()
+		var fields []string
+		for _, name := range names {
+			// results are kept by field name, and resolved without args, so only fields without
+			// args can be prefetched.
+			if def, ok := defs[name]; ok && len(def.Args) == 0 && selected.Has(name) {
+				fields = append(fields, name)
+			}
+		}
+		if len(fields) == 0 {
+			return result, nil
+		}
+		if !list {
+			return prefetch(p, obj, fields, []interface{}{result})[0], nil
+		}
+		v := reflect.ValueOf(result)
+		if v.Kind() == reflect.Ptr {
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return result, nil
+		}
+		items := make([]interface{}, v.Len())
+		for i := range items {
+			items[i] = v.Index(i).Interface()
+		}
+		return prefetch(p, obj, fields, items), nil
+	}
+}
+
+// prefetch resolves the fields of obj named in fields for each of items, concurrently, and returns
+// the items wrapped with their results.  Nil items are returned as they are.
+func prefetch(p graphql.ResolveParams, obj *graphql.Object, fields []string, items []interface{}) []interface{} {
+	ctx, cancel := context.WithCancel(nodeContext(p))
+	defer cancel()
+	defs := obj.Fields()
+	out := make([]interface{}, len(items))
+	results := make([][]prefetchResult, len(items))
+	var wg sync.WaitGroup
+	for i, item := range items {
+		out[i] = item
+		if isNilValue(item) {
+			continue
+		}
+		results[i] = make([]prefetchResult, len(fields))
+		for j, name := range fields {
+			def := defs[name]
+			info := p.Info
+			info.FieldName, info.ParentType, info.ReturnType, info.FieldASTs = name, obj, def.Type, nil
+			fp := graphql.ResolveParams{Source: item, Info: info, Context: ctx}
+			wg.Add(1)
+			go func(result *prefetchResult, resolve graphql.FieldResolveFn) {
+				defer wg.Done()
+				defer func() {
+					if r := recover(); r != nil {
+						DefaultPanicLogger(fp.Info, r, debug.Stack())
+						result.err = ErrInternal
+					}
+					if result.err != nil {
+						cancel()
+					}
+				}()
+				if resolve == nil {
+					resolve = graphql.DefaultResolveFn
+				}
+				result.value, result.err = resolve(fp)
+			}(&results[i][j], def.Resolve)
+		}
+	}
+	wg.Wait()
+	for i, item := range items {
+		if results[i] == nil {
+			continue
+		}
+		pf := &prefetched{source: item, results: map[string]prefetchResult{}}
+		for j, name := range fields {
+			pf.results[name] = results[i][j]
+		}
+		out[i] = pf
+	}
+	return out
+}
+
+// prefetchingFields wraps the resolvers of fields, the fields of an object with concurrent fields,
+// to use the results of prefetched sources.
+func prefetchingFields(fields graphql.Fields) graphql.Fields {
+	out := graphql.Fields{}
+	for name, f := range fields {
+		name, resolve, wrapped := name, f.Resolve, *f
+		if resolve == nil {
+			resolve = graphql.DefaultResolveFn
+		}
+		wrapped.Resolve = func(p graphql.ResolveParams) (interface{}, error) {
+			if pf, ok := p.Source.(*prefetched); ok {
+				if r, ok := pf.results[name]; ok {
+					return r.value, r.err
+				}
+				p.Source = pf.source
+			}
+			return resolve(p)
+		}
+		out[name] = &wrapped
+	}
+	return out
+}
+
+func isNilValue(i interface{}) bool {
+	if i == nil {
+		return true
+	}
+	switch v := reflect.ValueOf(i); v.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface, reflect.Func:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,407 @@
+package graphqlhelpers
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+const (
+	costTag           = "cost"
+	costMultiplierTag = "costMultiplier"
+)
+
+// costSpec is the cost configured for one field, keyed by its schema coordinate.
+type costSpec struct {
+	// the cost of the field itself, if set.  Otherwise composite fields cost 1 and leaf fields 0.
+	cost *int
+	// the args whose values multiply the cost of the field and its selections, like "first".
+	multipliers []string
+	// costs added when an arg is passed.
+	argCosts map[string]int
+}
+
+// SetFieldCost sets the cost of a field, by its schema coordinate like "Query.users", for fields
+// whose args struct or output struct can't carry cost tags, such as fields written by hand.  The
+// field's cost, and the cost of everything selected inside it, is multiplied by the values of the
+// multiplier args.
+func (e *ArgLoader) SetFieldCost(coordinate string, cost int, multipliers ...string) {
+	spec := e.fieldCosts[coordinate]
+	spec.cost = &cost
+	spec.multipliers = multipliers
+	e.fieldCosts[coordinate] = spec
+}
+
+// SetFieldCost sets the cost of a field on the default loader.
+func SetFieldCost(coordinate string, cost int, multipliers ...string) {
+	Default().SetFieldCost(coordinate, cost, multipliers...)
+}
+
+// CostLimit returns a rule for Execute that rejects queries whose QueryCost is over budget.
+//
+// Costs come from cost and costMultiplier tags.  On an output struct field, cost sets the cost of
+// selecting the field.  In the args struct of a SchemaBuilder root field, cost and costMultiplier on
+// a blank field set the cost of the root field and the args multiplying it, and cost on an arg field
+// is added when that arg is passed:
+//
+//	type UsersArgs struct {
+//		_      struct{} `cost:"10" costMultiplier:"first"`
+//		First  int      `arg:"first" required:"true"`
+//		Search string   `arg:"search" cost:"50"`
+//	}
+//
+// Here users(first: 100) { name } costs (10 + 0) × 100, and passing search adds another 50 before
+// multiplying.  A multiplier arg that isn't passed counts as 1.  The args structs of fields built with
+// FieldFromFunc, DecorateField or SubscribeOn count once the fields are added to a SchemaBuilder;
+// use SetFieldCost for fields put into objects by hand.  Untagged fields returning objects or
+// lists of them cost 1; other fields cost 0.  Counting stops as soon as the cost is over budget.
+func (e *ArgLoader) CostLimit(budget int) QueryRule {
+	return func(schema graphql.Schema, doc *ast.Document, operationName string, vars map[string]interface{}) error {
+		c, root, op, err := e.costCounter(schema, doc, operationName, vars)
+		if err != nil {
+			return err
+		}
+		c.budget = budget
+		if _, err := c.selectionSet(root, op.SelectionSet); err == errOverBudget {
+			return fmt.Errorf("query cost exceeds the maximum of %d", budget)
+		} else if err != nil {
+			return err
+		}
+		return nil
+	}
+}
+
+// CostLimit returns a rule rejecting queries over budget, using the costs known to the default
+// loader.
+func CostLimit(budget int) QueryRule {
+	return Default().CostLimit(budget)
+}
+
+// QueryCost computes the total cost of the operation of doc that a request for operationName would
+// execute, as described at CostLimit.  Fragments spread on the members of a union or interface are
+// all counted, so the cost is an upper bound.  Costs too large for an int are counted as the largest
+// int.
+func (e *ArgLoader) QueryCost(schema graphql.Schema, doc *ast.Document, operationName string, vars map[string]interface{}) (int, error) {
+	c, root, op, err := e.costCounter(schema, doc, operationName, vars)
+	if err != nil {
+		return 0, err
+	}
+	return c.selectionSet(root, op.SelectionSet)
+}
+
+// QueryCost computes the cost of an operation with the costs known to the default loader.
+func QueryCost(schema graphql.Schema, doc *ast.Document, operationName string, vars map[string]interface{}) (int, error) {
+	return Default().QueryCost(schema, doc, operationName, vars)
+}
+
+// maxCost is the largest int, which costs too large to count saturate at.
+const maxCost = int(^uint(0) >> 1)
+
+// errOverBudget stops a costCounter once the cost is known to be over its budget.
+var errOverBudget = errors.New("query cost exceeds the budget")
+
+type costCounter struct {
+	loader    *ArgLoader
+	schema    graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+	vars      map[string]interface{}
+	// the fragments being counted, so that fragment cycles are caught.
+	visiting map[string]bool
+	// the cost at which counting stops with errOverBudget.  Costs are never negative and
+	// multipliers never below 1, so once any part of the query is over budget, the whole is.
+	budget int
+}
+
+// costCounter returns a counter of the cost of the operation of doc that a request for
+// operationName would execute, with the operation and its root type.
+func (e *ArgLoader) costCounter(schema graphql.Schema, doc *ast.Document, operationName string, vars map[string]interface{}) (*costCounter, graphql.Type, *ast.OperationDefinition, error) {
+	op, err := operation(doc, operationName)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	root, err := rootType(schema, op)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	c := &costCounter{
+		loader:    e,
+		schema:    schema,
+		fragments: fragments(doc),
+		vars:      vars,
+		visiting:  map[string]bool{},
+		budget:    maxCost,
+	}
+	return c, root, op, nil
+}
+
+// check returns cost, or errOverBudget if it's over the counter's budget.
+func (c *costCounter) check(cost int) (int, error) {
+	if cost > c.budget {
+		return 0, errOverBudget
+	}
+	return cost, nil
+}
+
+// addCost returns a + b, or maxCost if that's too large for an int.
+func addCost(a, b int) int {
+	if b > 0 && a > maxCost-b {
+		return maxCost
+	}
+	return a + b
+}
+
+// mulCost returns a × n, for n of at least 1, or maxCost if that's too large for an int.
+func mulCost(a, n int) int {
+	if a > 0 && a > maxCost/n {
+		return maxCost
+	}
+	return a * n
+}
+
+func (c *costCounter) selectionSet(parent graphql.Type, set *ast.SelectionSet) (int, error) {
+	if set == nil {
+		return 0, nil
+	}
+	total := 0
+	for _, sel := range set.Selections {
+		var cost int
+		var err error
+		switch sel := sel.(type) {
+		case *ast.Field:
+			cost, err = c.field(parent, sel)
+		case *ast.InlineFragment:
+			t := parent
+			if sel.TypeCondition != nil {
+				t = c.schema.Type(sel.TypeCondition.Name.Value)
+			}
+			cost, err = c.selectionSet(t, sel.SelectionSet)
+		case *ast.FragmentSpread:
+			name := sel.Name.Value
+			frag, ok := c.fragments[name]
+			if !ok {
+				return 0, fmt.Errorf("unknown fragment %q", name)
+			}
+			if c.visiting[name] {
+				return 0, fmt.Errorf("fragment %q spreads itself", name)
+			}
+			c.visiting[name] = true
+			cost, err = c.selectionSet(c.schema.Type(frag.TypeCondition.Name.Value), frag.SelectionSet)
+			delete(c.visiting, name)
+		}
+		if err != nil {
+			return 0, err
+		}
+		if total, err = c.check(addCost(total, cost)); err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+func (c *costCounter) field(parent graphql.Type, field *ast.Field) (int, error) {
+	name := field.Name.Value
+	if strings.HasPrefix(name, "__") {
+		return 0, nil
+	}
+	def := fieldDefinition(parent, name)
+	if def == nil {
+		// left for validation to report.
+		return 0, nil
+	}
+	spec := c.loader.fieldCosts[parent.Name()+"."+name]
+
+	named, _ := graphql.GetNamed(def.Type).(graphql.Type)
+	cost := 0
+	if spec.cost != nil {
+		cost = *spec.cost
+	} else if graphql.IsCompositeType(named) {
+		cost = 1
+	}
+	for _, arg := range field.Arguments {
+		cost = addCost(cost, spec.argCosts[arg.Name.Value])
+	}
+	children, err := c.selectionSet(named, field.SelectionSet)
+	if err != nil {
+		return 0, err
+	}
+	cost = addCost(cost, children)
+	for _, multiplier := range spec.multipliers {
+		if cost, err = c.check(mulCost(cost, c.multiplier(def, field, multiplier))); err != nil {
+			return 0, err
+		}
+	}
+	return c.check(cost)
+}
+
+// multiplier returns the value of the named arg of field, or its default, or 1 if it has neither.
+func (c *costCounter) multiplier(def *graphql.FieldDefinition, field *ast.Field, name string) int {
+	var val interface{}
+	for _, arg := range def.Args {
+		if arg.Name() == name {
+			val = arg.DefaultValue
+		}
+	}
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != name {
+			continue
+		}
+		switch v := arg.Value.(type) {
+		case *ast.IntValue:
+			val = v.Value
+		case *ast.Variable:
+			if passed, ok := c.vars[v.Name.Value]; ok {
+				val = passed
+			}
+		}
+	}
+	n := 1
+	switch v := val.(type) {
+	case int:
+		n = v
+	case float64:
+		if v >= float64(maxCost) {
+			return maxCost
+		}
+		n = int(v)
+	case string:
+		i, err := strconv.Atoi(v)
+		if numErr, ok := err.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange && !strings.HasPrefix(v, "-") {
+			return maxCost
+		}
+		if err == nil {
+			n = i
+		}
+	}
+	if n < 1 {
+		// a field still costs something when asked for nothing.
+		return 1
+	}
+	return n
+}
+
+// setOutputCosts records the cost tags on the output fields of struct type t, generated as the
+// object or interface named typeName.
+func (e *ArgLoader) setOutputCosts(typeName string, t reflect.Type) error {
+	for _, f := range outputFields(t) {
+		raw, ok := f.field.Tag.Lookup(costTag)
+		if !ok {
+			continue
+		}
+		cost, err := parseCost(raw)
+		if err != nil {
+			return fmt.Errorf("cannot read %s tag on %s.%s: %v", costTag, t.Name(), f.field.Name, err)
+		}
+		spec := e.fieldCosts[typeName+"."+f.name]
+		spec.cost = &cost
+		e.fieldCosts[typeName+"."+f.name] = spec
+	}
+	return nil
+}
+
+// setArgCosts records the cost and costMultiplier tags of args struct type t, the args of the field
+// at coordinate.
+func (e *ArgLoader) setArgCosts(coordinate string, t reflect.Type) error {
+	spec, tagged, err := e.argCosts(e.fieldCosts[coordinate], t)
+	if err != nil {
+		return err
+	}
+	if tagged {
+		e.fieldCosts[coordinate] = spec
+	}
+	return nil
+}
+
+// noteArgCosts checks the cost tags of args struct type t, the args of field, and if it has any,
+// keeps t so that they're recorded by setFieldArgCosts once field is given a coordinate.
+func (e *ArgLoader) noteArgCosts(field *graphql.Field, t reflect.Type) error {
+	_, tagged, err := e.argCosts(costSpec{}, t)
+	if err != nil {
+		return err
+	}
+	if tagged {
+		e.fieldArgTypes[field] = t
+	}
+	return nil
+}
+
+// setFieldArgCosts records the cost tags of the args struct of field, now at coordinate, if it was
+// built from one.
+func (e *ArgLoader) setFieldArgCosts(coordinate string, field *graphql.Field) error {
+	t, ok := e.fieldArgTypes[field]
+	if !ok {
+		return nil
+	}
+	return e.setArgCosts(coordinate, t)
+}
+
+// argCosts adds the cost and costMultiplier tags of args struct type t to spec, reporting whether
+// t has any.
+func (e *ArgLoader) argCosts(spec costSpec, t reflect.Type) (costSpec, bool, error) {
+	tagged := false
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Name != "_" {
+			continue
+		}
+		if raw, ok := field.Tag.Lookup(costTag); ok {
+			cost, err := parseCost(raw)
+			if err != nil {
+				return spec, false, fmt.Errorf("cannot read %s tag on %v: %v", costTag, t, err)
+			}
+			spec.cost = &cost
+			tagged = true
+		}
+		if raw := field.Tag.Get(costMultiplierTag); raw != "" {
+			spec.multipliers = strings.Split(raw, ",")
+			tagged = true
+		}
+	}
+	for _, f := range e.argFields(t) {
+		raw, ok := f.field.Tag.Lookup(costTag)
+		if !ok {
+			continue
+		}
+		cost, err := parseCost(raw)
+		if err != nil {
+			return spec, false, fmt.Errorf("cannot read %s tag on %s: %v", costTag, f.field.Name, err)
+		}
+		if spec.argCosts == nil {
+			spec.argCosts = map[string]int{}
+		}
+		spec.argCosts[f.name] = cost
+		tagged = true
+	}
+	for _, name := range spec.multipliers {
+		if !hasArg(e.argFields(t), name) {
+			return spec, false, fmt.Errorf("cannot use %s as a %s of %v: there is no such arg", name, costMultiplierTag, t)
+		}
+	}
+	return spec, tagged, nil
+}
+
+// parseCost reads the value of a cost tag.  Costs can't be negative, or one field could cancel out
+// the cost of the rest of a query.
+func parseCost(raw string) (int, error) {
+	cost, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, err
+	}
+	if cost < 0 {
+		return 0, fmt.Errorf("%d is negative", cost)
+	}
+	return cost, nil
+}
+
+func hasArg(fields []argField, name string) bool {
+	for _, f := range fields {
+		if f.name == name {
+			return true
+		}
+	}
+	return false
+}
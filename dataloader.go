@@ -0,0 +1,253 @@
+package graphqlhelpers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// loaderTag names the per-request DataLoader injected into an args struct field.
+const loaderTag = "loader"
+
+// BatchFunc loads the values for keys in one go, returning them in the same order as keys.  Missing
+// values should be nil.
+type BatchFunc func(ctx context.Context, keys []interface{}) ([]interface{}, error)
+
+// DefaultDataLoaderWait is how long a DataLoader collects keys before fetching them in one batch.
+var DefaultDataLoaderWait = time.Millisecond
+
+// DataLoader batches and caches the values loaded by a BatchFunc for the life of a request.  A Load
+// of a key that isn't cached waits DefaultDataLoaderWait (as it was when the loader was made) for
+// other Loads, and then the keys of all of them are fetched with one call to the batch func, with
+// the context of the first.  Keys already in a batch aren't fetched again, and values are cached, so
+// resolvers loading the same key share one fetch.
+//
+// graphql-go resolves fields one at a time, so Loads only end up in the same batch when they're made
+// concurrently, as from the concurrent fields of the items of a list.  The resolver of a list can
+// also call LoadMany (or Prime) with the keys its items' fields will need; each item's Load is then
+// served from the cache.  A batch func may use other loaders, or this one, but not Load the keys it
+// was called with.
+type DataLoader struct {
+	batch BatchFunc
+	wait  time.Duration
+	mu    sync.Mutex
+	cache map[interface{}]interface{}
+	// the batches of the keys not yet fetched, by key.
+	pending map[interface{}]*loaderBatch
+	// the batch collecting keys, if its wait hasn't ended.
+	next *loaderBatch
+}
+
+// loaderBatch is the keys fetched by one call to a batch func, which Loads of its keys wait for.
+type loaderBatch struct {
+	keys []interface{}
+	done chan struct{}
+	// set before done is closed.
+	err error
+}
+
+// NewDataLoader returns an empty DataLoader that fetches with batch.
+func NewDataLoader(batch BatchFunc) *DataLoader {
+	return &DataLoader{
+		batch:   batch,
+		wait:    DefaultDataLoaderWait,
+		cache:   map[interface{}]interface{}{},
+		pending: map[interface{}]*loaderBatch{},
+	}
+}
+
+// Load returns the value for key, fetching it if it isn't cached.
+func (d *DataLoader) Load(ctx context.Context, key interface{}) (interface{}, error) {
+	values, err := d.LoadMany(ctx, []interface{}{key})
+	if err != nil {
+		return nil, err
+	}
+	return values[0], nil
+}
+
+// LoadMany returns the values for keys, fetching the keys that aren't cached in the next batch.
+func (d *DataLoader) LoadMany(ctx context.Context, keys []interface{}) ([]interface{}, error) {
+	for _, key := range keys {
+		if key == nil || !reflect.TypeOf(key).Comparable() {
+			return nil, fmt.Errorf("%v cannot be used as a data loader key", key)
+		}
+	}
+	d.mu.Lock()
+	waiting := map[*loaderBatch]bool{}
+	for _, key := range keys {
+		if _, cached := d.cache[key]; cached {
+			continue
+		}
+		b, ok := d.pending[key]
+		if !ok {
+			if d.next == nil {
+				d.next = &loaderBatch{done: make(chan struct{})}
+				next := d.next
+				time.AfterFunc(d.wait, func() { d.fetch(ctx, next) })
+			}
+			b = d.next
+			b.keys = append(b.keys, key)
+			d.pending[key] = b
+		}
+		waiting[b] = true
+	}
+	d.mu.Unlock()
+
+	var cancelled <-chan struct{}
+	if ctx != nil {
+		cancelled = ctx.Done()
+	}
+	for b := range waiting {
+		select {
+		case <-b.done:
+			if b.err != nil {
+				return nil, b.err
+			}
+		case <-cancelled:
+			return nil, ctx.Err()
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	out := make([]interface{}, len(keys))
+	for i, key := range keys {
+		out[i] = d.cache[key]
+	}
+	return out, nil
+}
+
+// fetch calls the batch func for the keys of b, once its wait has ended.
+func (d *DataLoader) fetch(ctx context.Context, b *loaderBatch) {
+	d.mu.Lock()
+	if d.next == b {
+		d.next = nil
+	}
+	d.mu.Unlock()
+
+	// the lock isn't held while fetching, so batch funcs can use other loaders, or this one.
+	values, err := d.callBatch(ctx, b.keys)
+	if err == nil && len(values) != len(b.keys) {
+		err = fmt.Errorf("batch func returned %d values for %d keys", len(values), len(b.keys))
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i, key := range b.keys {
+		if err == nil {
+			d.cache[key] = values[i]
+		}
+		delete(d.pending, key)
+	}
+	b.err = err
+	close(b.done)
+}
+
+// callBatch calls the batch func, turning a panic into ErrInternal, since batch funcs run on a
+// goroutine of their own rather than a resolver's.
+func (d *DataLoader) callBatch(ctx context.Context, keys []interface{}) (values []interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("panic in data loader batch func: %v\n%s", r, debug.Stack())
+			values, err = nil, ErrInternal
+		}
+	}()
+	return d.batch(ctx, keys)
+}
+
+// Prime caches value for key, without calling the batch func.
+func (d *DataLoader) Prime(key, value interface{}) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.cache[key] = value
+}
+
+// Clear removes key from the cache, so the next Load fetches it again.
+func (d *DataLoader) Clear(key interface{}) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.cache, key)
+}
+
+type dataLoadersKey struct{}
+
+// dataLoaders are the loaders of one request, created on first use.
+type dataLoaders struct {
+	batches map[string]BatchFunc
+	mu      sync.Mutex
+	loaders map[string]*DataLoader
+}
+
+// WithDataLoaders returns a context holding a fresh, empty DataLoader for each of the named batch
+// funcs.  Call it once per request, so that cached values don't outlive the request.
+func WithDataLoaders(ctx context.Context, batches map[string]BatchFunc) context.Context {
+	return context.WithValue(ctx, dataLoadersKey{}, &dataLoaders{
+		batches: batches,
+		loaders: map[string]*DataLoader{},
+	})
+}
+
+// DataLoaderMiddleware wraps an HTTP handler (such as a graphql handler) so that every request's
+// context holds its own DataLoaders, as with WithDataLoaders.
+func DataLoaderMiddleware(batches map[string]BatchFunc, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(WithDataLoaders(r.Context(), batches)))
+	})
+}
+
+// DataLoaderFromContext returns the named DataLoader of the request whose context is ctx.
+func DataLoaderFromContext(ctx context.Context, name string) (*DataLoader, error) {
+	var loaders *dataLoaders
+	if ctx != nil {
+		loaders, _ = ctx.Value(dataLoadersKey{}).(*dataLoaders)
+	}
+	if loaders == nil {
+		return nil, fmt.Errorf("no data loaders in context. use WithDataLoaders or DataLoaderMiddleware")
+	}
+	loaders.mu.Lock()
+	defer loaders.mu.Unlock()
+	if d, ok := loaders.loaders[name]; ok {
+		return d, nil
+	}
+	batch, ok := loaders.batches[name]
+	if !ok {
+		return nil, fmt.Errorf("no data loader named %q", name)
+	}
+	d := NewDataLoader(batch)
+	loaders.loaders[name] = d
+	return d, nil
+}
+
+var dataLoaderType = reflect.TypeOf((*DataLoader)(nil))
+
+// injectDataLoaders sets every *DataLoader field of structVal tagged with a loader name to that
+// loader from ctx.  Untagged embedded structs are searched too.
+func injectDataLoaders(ctx context.Context, structVal reflect.Value) error {
+	t := structVal.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, ok := field.Tag.Lookup(loaderTag)
+		if !ok {
+			_, hasArg := field.Tag.Lookup(argTag)
+			if field.Anonymous && !hasArg && field.Type.Kind() == reflect.Struct {
+				if err := injectDataLoaders(ctx, structVal.Field(i)); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if field.Type != dataLoaderType {
+			return fmt.Errorf("cannot inject data loader %q into %s: the field must be a %v", name, field.Name, dataLoaderType)
+		}
+		d, err := DataLoaderFromContext(ctx, name)
+		if err != nil {
+			return fmt.Errorf("cannot inject data loader into %s: %v", field.Name, err)
+		}
+		structVal.Field(i).Set(reflect.ValueOf(d))
+	}
+	return nil
+}
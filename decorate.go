@@ -0,0 +1,97 @@
+package graphqlhelpers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/graphql-go/graphql"
+)
+
+type decoratedArgsKey struct{}
+
+// DecorateField adds the args of an args struct to a hand-written field, so that legacy fields can
+// adopt the helpers one at a time.  The arg configs generated from args are merged into field.Args,
+// and field.Resolve is wrapped in a resolver that loads them into a new value of args' type before
+// calling it, with the loader's middleware and then mw around it all.  The wrapped resolver can
+// still read p.Args, or get the loaded struct from DecoratedArgs:
+//
+//	graphqlhelpers.DecorateField(legacyField, SearchArgs{})
+//
+//	func resolveSearch(p graphql.ResolveParams) (interface{}, error) {
+//		args := graphqlhelpers.DecoratedArgs(p.Context).(*SearchArgs)
+//		...
+//	}
+//
+// The field is changed in place and returned.  If the args can't be configured, or one of them is
+// already in field.Args, this function will panic.
+func (e *ArgLoader) DecorateField(field *graphql.Field, args interface{}, mw ...Middleware) *graphql.Field {
+	if err := e.SafeDecorateField(field, args, mw...); err != nil {
+		panic(fmt.Sprintf("could not decorate field: %v", err))
+	}
+	return field
+}
+
+// SafeDecorateField is like DecorateField, but returns an error instead of panicking.  The field is
+// left unchanged if there is an error.
+func (e *ArgLoader) SafeDecorateField(field *graphql.Field, args interface{}, mw ...Middleware) error {
+	argsType, err := argsStructType(args)
+	if err != nil {
+		return err
+	}
+	conf, err := e.SafeArgsConfig(args)
+	if err != nil {
+		return err
+	}
+	for name := range conf {
+		if _, ok := field.Args[name]; ok {
+			return fmt.Errorf("the field already has an arg named %s", name)
+		}
+	}
+	if err := e.noteArgCosts(field, argsType); err != nil {
+		return err
+	}
+	if field.Args == nil {
+		field.Args = graphql.FieldConfigArgument{}
+	}
+	for name, arg := range conf {
+		field.Args[name] = arg
+	}
+	resolve := field.Resolve
+	if resolve == nil {
+		resolve = graphql.DefaultResolveFn
+	}
+	field.Resolve = e.wrap(func(p graphql.ResolveParams) (interface{}, error) {
+		loaded := reflect.New(argsType).Interface()
+		if err := e.LoadArgs(p, loaded); err != nil {
+			return nil, err
+		}
+		ctx := p.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		p.Context = context.WithValue(ctx, decoratedArgsKey{}, loaded)
+		return resolve(p)
+	}, mw...)
+	return nil
+}
+
+// DecoratedArgs returns a pointer to the args struct loaded by a resolver wrapped with
+// DecorateField, or nil outside of one.
+func DecoratedArgs(ctx context.Context) interface{} {
+	if ctx == nil {
+		return nil
+	}
+	return ctx.Value(decoratedArgsKey{})
+}
+
+// DecorateField adds the args of an args struct to a hand-written field using the default loader.
+func DecorateField(field *graphql.Field, args interface{}, mw ...Middleware) *graphql.Field {
+	return Default().DecorateField(field, args, mw...)
+}
+
+// SafeDecorateField adds the args of an args struct to a hand-written field using the default
+// loader, returning an error instead of panicking.
+func SafeDecorateField(field *graphql.Field, args interface{}, mw ...Middleware) error {
+	return Default().SafeDecorateField(field, args, mw...)
+}
@@ -0,0 +1,66 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"sync"
+)
+
+var (
+	defaultMu     sync.RWMutex
+	defaultLoader *ArgLoader
+	// defaultSet is true once SetDefault has been called, so a nil loader it set isn't rebuilt.
+	defaultSet bool
+)
+
+// Default returns the loader used by the package-level funcs, like ArgsConfig and LoadArgs.  Unless
+// one was set with SetDefault, it's built by New the first time it's needed, so nothing is
+// registered at init time.
+//
+// Builds with the graphqlhelpers_nodefault tag don't build one, so that codebases wiring their own
+// loader everywhere can't depend on global state by accident: the package-level funcs panic until
+// SetDefault is called.
+func Default() *ArgLoader {
+	defaultMu.RLock()
+	e, set := defaultLoader, defaultSet
+	defaultMu.RUnlock()
+	if set {
+		return mustBeEnabled(e)
+	}
+
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if !defaultSet {
+		defaultLoader, defaultSet = newDefault(), true
+	}
+	return mustBeEnabled(defaultLoader)
+}
+
+func mustBeEnabled(e *ArgLoader) *ArgLoader {
+	if e == nil {
+		panic("the default loader is disabled; call SetDefault, or use an ArgLoader instead of the package-level funcs")
+	}
+	return e
+}
+
+// SetDefault makes e the loader used by the package-level funcs, so code written against them can
+// share a loader configured elsewhere.  SetDefault(nil) disables the package-level funcs, making
+// them panic, which is the runtime equivalent of the graphqlhelpers_nodefault build tag.
+//
+// Call it during setup, before the package-level funcs are used: types registered on the previous
+// default loader aren't carried over.
+func SetDefault(e *ArgLoader) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultLoader, defaultSet = e, true
+}
+
+// mustNewDefault builds the default loader.
+func mustNewDefault() *ArgLoader {
+	// we can only fail here if one of the hardcoded default loader funcs has the wrong function
+	// signature.  If that does fail, fail hard.
+	e, err := New()
+	if err != nil {
+		panic(fmt.Sprintf("could not init default loader: %v", err))
+	}
+	return e
+}
@@ -0,0 +1,8 @@
+//go:build graphqlhelpers_nodefault
+
+package graphqlhelpers
+
+// newDefault builds no default loader, since the graphqlhelpers_nodefault tag disables it.
+func newDefault() *ArgLoader {
+	return nil
+}
@@ -0,0 +1,7 @@
+//go:build !graphqlhelpers_nodefault
+
+package graphqlhelpers
+
+func newDefault() *ArgLoader {
+	return mustNewDefault()
+}
@@ -0,0 +1,116 @@
+package graphqlhelpers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/graphql-go/graphql"
+)
+
+// deprecatedTag marks an arg or output field as deprecated, with the reason as its value, like
+// `deprecated:"use filter instead"`.  Output fields get the reason as their deprecation reason.
+// graphql-go can't deprecate args, so an arg's reason is added to its description instead.
+const deprecatedTag = "deprecated"
+
+// DeprecatedArgUse describes a request that passed a deprecated arg, or an arg under one of its
+// aliases.
+type DeprecatedArgUse struct {
+	// Operation is the name of the request's operation, or empty for anonymous operations.
+	Operation string
+	// Field is the schema coordinate of the field the arg was passed to, like "Query.users".
+	Field string
+	// Arg is the name the arg was passed under, with the names of the args it is nested in, like
+	// "filter.old_name".
+	Arg string
+	// Alias is set if Arg is an alias, and holds the name that replaces it.
+	Alias string
+	// Reason is the arg's deprecated tag, or for aliases a note of the name that replaces it.
+	Reason string
+}
+
+// DeprecationHook is called for each deprecated arg or alias in a request.
+type DeprecationHook func(ctx context.Context, use DeprecatedArgUse)
+
+// OnDeprecatedArg installs a hook called by LoadArgs for every deprecated arg or alias a request
+// passes, so that it can be measured when old args are safe to remove.  It is called before the
+// args are loaded, whether or not they load.
+func (e *ArgLoader) OnDeprecatedArg(hook DeprecationHook) {
+	e.deprecationHooks = append(e.deprecationHooks, hook)
+}
+
+// OnDeprecatedArg installs a deprecated arg hook on the default loader.
+func OnDeprecatedArg(hook DeprecationHook) {
+	Default().OnDeprecatedArg(hook)
+}
+
+// argDescription returns the description of an arg field of t, with the reason from its
+// deprecated tag, if it has one.
+func (e *ArgLoader) argDescription(t reflect.Type, f argField) string {
+	desc := e.description(t, f.index, f.field)
+	reason, ok := f.field.Tag.Lookup(deprecatedTag)
+	if !ok {
+		return desc
+	}
+	if desc != "" {
+		desc += "\n\n"
+	}
+	return desc + "Deprecated: " + reason
+}
+
+// reportDeprecated calls the deprecation hooks for the deprecated args and aliases in args, which
+// are loaded into struct type t, recursing into the input objects that were passed.
+func (e *ArgLoader) reportDeprecated(p graphql.ResolveParams, t reflect.Type, args map[string]interface{}, prefix string) {
+	for _, f := range e.argFields(t) {
+		if f.rest {
+			continue
+		}
+		val, key, passed := lookupArg(args, f)
+		if !passed {
+			continue
+		}
+		use := DeprecatedArgUse{Arg: prefix + key}
+		if reason, ok := f.field.Tag.Lookup(deprecatedTag); ok {
+			use.Reason = reason
+		}
+		if key != f.name {
+			use.Alias = f.name
+			if use.Reason == "" {
+				use.Reason = fmt.Sprintf("use %s instead", f.name)
+			}
+		}
+		if use.Reason != "" {
+			use.Operation = operationName(p.Info)
+			use.Field = FieldPath(p.Info)
+			ctx := p.Context
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			for _, hook := range e.deprecationHooks {
+				hook(ctx, use)
+			}
+		}
+		e.reportDeprecatedValue(p, f.field.Type, val, prefix+key)
+	}
+}
+
+func (e *ArgLoader) reportDeprecatedValue(p graphql.ResolveParams, t reflect.Type, val interface{}, path string) {
+	if _, registered := e.loaderFuncs[t]; registered {
+		return
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		e.reportDeprecatedValue(p, t.Elem(), val, path)
+	case reflect.Slice, reflect.Array:
+		items, _ := val.([]interface{})
+		for i, item := range items {
+			e.reportDeprecatedValue(p, t.Elem(), item, fmt.Sprintf("%s[%d]", path, i))
+		}
+	case reflect.Struct:
+		if vt, ok := optionalValueType(t); ok {
+			e.reportDeprecatedValue(p, vt, val, path)
+		} else if m, ok := val.(map[string]interface{}); ok {
+			e.reportDeprecated(p, t, m, path+".")
+		}
+	}
+}
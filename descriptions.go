@@ -0,0 +1,70 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Describer is implemented by arg and output structs that document their fields outside of desc
+// tags, for documentation too long to fit comfortably in a tag.  Descriptions returns descriptions
+// keyed by Go field name, and is called on the struct's zero value:
+//
+//	func (SearchArgs) Descriptions() map[string]string {
+//		return map[string]string{
+//			"Query": "Full-text search terms. Terms are matched against titles and bodies, " +
+//				"and may be quoted to match a phrase.",
+//		}
+//	}
+//
+// A field's desc tag takes precedence over both Descriptions and RegisterDescriptions.
+type Describer interface {
+	Descriptions() map[string]string
+}
+
+// RegisterDescriptions documents the fields of i's struct type, keyed by Go field name, for types
+// that can't be given a Descriptions method, like ones from another package.  Registered
+// descriptions take precedence over a Descriptions method.
+func (e *ArgLoader) RegisterDescriptions(i interface{}, descriptions map[string]string) error {
+	t := reflect.TypeOf(i)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return fmt.Errorf("%v is not a struct", reflect.TypeOf(i))
+	}
+	for name := range descriptions {
+		if _, ok := t.FieldByName(name); !ok {
+			return fmt.Errorf("%v has no %s field", t, name)
+		}
+	}
+	if e.descriptions[t] == nil {
+		e.descriptions[t] = map[string]string{}
+	}
+	for name, desc := range descriptions {
+		e.descriptions[t][name] = desc
+	}
+	return nil
+}
+
+// RegisterDescriptions documents the fields of i's struct type on the default loader.
+func RegisterDescriptions(i interface{}, descriptions map[string]string) error {
+	return Default().RegisterDescriptions(i, descriptions)
+}
+
+// description returns the description of field, found at index in struct type t.  Fields promoted
+// from embedded structs are described by the struct that declares them.
+func (e *ArgLoader) description(t reflect.Type, index []int, field reflect.StructField) string {
+	if desc, ok := field.Tag.Lookup(descTag); ok {
+		return desc
+	}
+	for _, i := range index[:len(index)-1] {
+		t = t.Field(i).Type
+	}
+	if desc, ok := e.descriptions[t][field.Name]; ok {
+		return desc
+	}
+	if d, ok := reflect.New(t).Interface().(Describer); ok {
+		return d.Descriptions()[field.Name]
+	}
+	return ""
+}
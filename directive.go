@@ -0,0 +1,117 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// directiveTag lists, comma-separated, the directives applied to an arg, like
+// `directive:"uppercase"`.  graphql-go doesn't run schema directives, so these stand in for them:
+// each directive's handler runs on the arg's value as it is loaded, after any sanitize and transform
+// tags.
+const directiveTag = "directive"
+
+// Directive is custom behavior attached to args with a directive tag.
+type Directive struct {
+	// Name is the name used in directive tags, and in SDL as @Name.
+	Name string
+	// Description documents the directive in SDL.
+	Description string
+	// Handle is called with the loaded value of each arg with the directive, and returns the value
+	// to use instead, which must have the arg's Go type, or an error to reject the arg.
+	Handle func(value interface{}) (interface{}, error)
+	// InSDL adds a definition of the directive to the loader's SDL, and applies it to the input
+	// object fields that use it there.
+	InSDL bool
+}
+
+var directiveNameRe = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// RegisterDirective makes d available to directive tags.
+func (e *ArgLoader) RegisterDirective(d Directive) error {
+	if !directiveNameRe.MatchString(d.Name) {
+		return fmt.Errorf("%q is not a valid directive name", d.Name)
+	}
+	if d.Handle == nil {
+		return fmt.Errorf("directive %s has no Handle func", d.Name)
+	}
+	if _, ok := e.directives[d.Name]; ok {
+		return fmt.Errorf("a directive named %s has already been registered", d.Name)
+	}
+	e.directives[d.Name] = &d
+	return nil
+}
+
+// RegisterDirective registers a directive on the default loader.
+func RegisterDirective(d Directive) error {
+	return Default().RegisterDirective(d)
+}
+
+// fieldDirectives returns the directives listed in field's directive tag, checking that they exist.
+func (e *ArgLoader) fieldDirectives(field reflect.StructField) ([]*Directive, error) {
+	tag, ok := field.Tag.Lookup(directiveTag)
+	if !ok {
+		return nil, nil
+	}
+	var out []*Directive
+	for _, name := range strings.Split(tag, ",") {
+		name = strings.TrimSpace(name)
+		d, ok := e.directives[name]
+		if !ok {
+			return nil, fmt.Errorf("%q is not a registered directive", name)
+		}
+		out = append(out, d)
+	}
+	return out, nil
+}
+
+// applyDirectives runs the handlers of directives on v in order.
+func applyDirectives(v reflect.Value, directives []*Directive) (reflect.Value, error) {
+	for _, d := range directives {
+		out, err := d.Handle(v.Interface())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		result := reflect.ValueOf(out)
+		if !result.IsValid() {
+			result = reflect.Zero(v.Type())
+		}
+		if result.Type() != v.Type() {
+			return reflect.Value{}, fmt.Errorf("directive %s returned a value of type %v, not %v", d.Name, result.Type(), v.Type())
+		}
+		v = result
+	}
+	return v, nil
+}
+
+// directiveDefinitions returns the SDL definitions of the directives that are shown in SDL, sorted
+// by name.
+func (e *ArgLoader) directiveDefinitions() string {
+	var names []string
+	for name, d := range e.directives {
+		if d.InSDL {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, name := range names {
+		printDescription(&b, "", e.directives[name].Description)
+		fmt.Fprintf(&b, "directive @%s on ARGUMENT_DEFINITION | INPUT_FIELD_DEFINITION\n\n", name)
+	}
+	return b.String()
+}
+
+// sdlDirectives returns the names of the directives of field that are shown in SDL.
+func sdlDirectives(directives []*Directive) []string {
+	var out []string
+	for _, d := range directives {
+		if d.InSDL {
+			out = append(out, d.Name)
+		}
+	}
+	return out
+}
@@ -0,0 +1,192 @@
+// Package docgen generates field descriptions for graphqlhelpers from the doc comments in Go
+// source, so that schema docs stay in sync with Go docs.
+//
+// Every struct in a package with a documented field that has an arg or field tag gets an entry in
+// a generated func, which registers the doc comments with ArgLoader.RegisterDescriptions:
+//
+//	type SearchArgs struct {
+//		// Full-text search terms, matched against titles and bodies.
+//		Query string `arg:"query"`
+//	}
+//
+// Fields with a desc tag are left out, since the tag would win anyway.
+package docgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Config controls code generation.
+type Config struct {
+	// Func is the name of the generated func.  It defaults to RegisterDescriptions.
+	Func string
+
+	// Implicit includes documented exported fields without an arg or field tag, for loaders that
+	// use ImplicitArgs.
+	Implicit bool
+}
+
+// structDocs holds the documented fields of one struct.
+type structDocs struct {
+	name   string
+	fields map[string]string
+}
+
+// Generate returns formatted Go source registering the field doc comments of the package in dir.
+// Test files and generated files are skipped.
+func Generate(dir string, conf Config) ([]byte, error) {
+	if conf.Func == "" {
+		conf.Func = "RegisterDescriptions"
+	}
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	var pkg string
+	var structs []structDocs
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if isGenerated(file) {
+			continue
+		}
+		if pkg == "" {
+			pkg = file.Name.Name
+		} else if file.Name.Name != pkg {
+			return nil, fmt.Errorf("%s is in package %s, not %s", name, file.Name.Name, pkg)
+		}
+		structs = append(structs, fileStructs(file, conf)...)
+	}
+	if pkg == "" {
+		return nil, fmt.Errorf("no Go files found in %s", dir)
+	}
+	sort.Slice(structs, func(i, j int) bool { return structs[i].name < structs[j].name })
+	return source(pkg, conf, structs)
+}
+
+// isGenerated reports whether file has the standard "Code generated ... DO NOT EDIT." comment.
+func isGenerated(file *ast.File) bool {
+	for _, group := range file.Comments {
+		if group.Pos() > file.Package {
+			return false
+		}
+		for _, c := range group.List {
+			if strings.HasPrefix(c.Text, "// Code generated ") && strings.HasSuffix(c.Text, " DO NOT EDIT.") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fileStructs returns the structs declared at the top level of file that have documented arg or
+// output fields.
+func fileStructs(file *ast.File, conf Config) []structDocs {
+	var out []structDocs
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok || ts.TypeParams != nil {
+				continue
+			}
+			docs := structDocs{name: ts.Name.Name, fields: map[string]string{}}
+			for _, field := range st.Fields.List {
+				if doc := fieldDoc(field, conf); doc != "" {
+					for _, name := range field.Names {
+						docs.fields[name.Name] = doc
+					}
+				}
+			}
+			if len(docs.fields) > 0 {
+				out = append(out, docs)
+			}
+		}
+	}
+	return out
+}
+
+// fieldDoc returns the doc comment of field, or its line comment if it has no doc comment, or ""
+// if the field isn't an arg or output field or is described by a desc tag.
+func fieldDoc(field *ast.Field, conf Config) string {
+	if len(field.Names) == 0 {
+		return ""
+	}
+	var tag reflect.StructTag
+	if field.Tag != nil {
+		raw, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			return ""
+		}
+		tag = reflect.StructTag(raw)
+	}
+	if _, ok := tag.Lookup("desc"); ok {
+		return ""
+	}
+	arg, isArg := tag.Lookup("arg")
+	output, isOutput := tag.Lookup("field")
+	if isArg || isOutput {
+		if (!isArg || arg == "-") && (!isOutput || output == "-") {
+			return ""
+		}
+	} else if !conf.Implicit || !field.Names[0].IsExported() {
+		return ""
+	}
+	doc := field.Doc
+	if doc == nil {
+		doc = field.Comment
+	}
+	return strings.TrimSpace(doc.Text())
+}
+
+func source(pkg string, conf Config, structs []structDocs) ([]byte, error) {
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by docgen. DO NOT EDIT.\n\npackage %s\n\n", pkg)
+	fmt.Fprintf(&out, "import graphqlhelpers %q\n\n", "github.com/btubbs/graphql-go-helpers")
+	fmt.Fprintf(&out, "// %s registers the doc comments of struct fields as their descriptions on l.\n", conf.Func)
+	fmt.Fprintf(&out, "func %s(l *graphqlhelpers.ArgLoader) error {\n", conf.Func)
+	if len(structs) > 0 {
+		out.WriteString("\tfor _, d := range []struct {\n\t\tvalue        interface{}\n\t\tdescriptions map[string]string\n\t}{\n")
+		for _, s := range structs {
+			fmt.Fprintf(&out, "\t\t{%s{}, map[string]string{\n", s.name)
+			names := make([]string, 0, len(s.fields))
+			for name := range s.fields {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				fmt.Fprintf(&out, "\t\t\t%q: %s,\n", name, strconv.Quote(s.fields[name]))
+			}
+			out.WriteString("\t\t}},\n")
+		}
+		out.WriteString("\t} {\n\t\tif err := l.RegisterDescriptions(d.value, d.descriptions); err != nil {\n\t\t\treturn err\n\t\t}\n\t}\n")
+	}
+	out.WriteString("\treturn nil\n}\n")
+	formatted, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid code: %v\n%s", err, out.Bytes())
+	}
+	return formatted, nil
+}
@@ -0,0 +1,145 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+
+	"github.com/graphql-go/graphql"
+)
+
+// DumpArgs is the inverse of LoadArgs.  It takes an args struct (or a pointer to one) and returns the
+// args map that would load into it, in the wire format graphql-go accepts as variables.  Values of
+// registered scalar and enum types are serialized by their graphql type, and other registered types
+// need a dumper registered with RegisterDumper.  Optional fields holding their zero value are
+// omitted, since LoadArgs leaves absent args at their zero value.
+func (e *ArgLoader) DumpArgs(i interface{}) (map[string]interface{}, error) {
+	v := reflect.ValueOf(i)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%v is not a struct", i)
+	}
+	return e.dumpStruct(v)
+}
+
+// RegisterDumper takes a func (<anytype>) (interface{}, error) and registers it on the ArgLoader as
+// the func used by DumpArgs to serialize values of <anytype>.
+func (e *ArgLoader) RegisterDumper(f interface{}) error {
+	t := reflect.TypeOf(f)
+	if t.Kind() != reflect.Func {
+		return fmt.Errorf("%v is not a func", f)
+	}
+	fname := runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name()
+	if t.NumIn() != 1 {
+		return fmt.Errorf("dumper func should accept 1 argument. %v accepts %d arguments", fname, t.NumIn())
+	}
+	errorInterface := reflect.TypeOf((*error)(nil)).Elem()
+	if t.NumOut() != 2 || !t.Out(1).Implements(errorInterface) {
+		return fmt.Errorf("dumper func should return (interface{}, error). %v does not", fname)
+	}
+	callable := reflect.ValueOf(f)
+	return e.registerDumper(t.In(0), func(v reflect.Value) (interface{}, error) {
+		returnvals := callable.Call([]reflect.Value{v})
+		if !returnvals[1].IsNil() {
+			return nil, fmt.Errorf("%v", returnvals[1])
+		}
+		return returnvals[0].Interface(), nil
+	})
+}
+
+func (e *ArgLoader) registerDumper(t reflect.Type, dumpFunc func(reflect.Value) (interface{}, error)) error {
+	if _, alreadyRegistered := e.dumpFuncs[t]; alreadyRegistered {
+		return fmt.Errorf("a dumper func has already been registered for the %v type", t)
+	}
+	e.dumpFuncs[t] = dumpFunc
+	return nil
+}
+
+func (e *ArgLoader) dumpStruct(v reflect.Value) (map[string]interface{}, error) {
+	out := map[string]interface{}{}
+	var rest map[string]interface{}
+	for _, f := range e.argFields(v.Type()) {
+		fv := v.FieldByIndex(f.index)
+		if f.rest {
+			rest, _ = fv.Interface().(map[string]interface{})
+			continue
+		}
+		if !isRequired(f.field) && fv.IsZero() {
+			continue
+		}
+		dumped, err := e.dumpValue(fv)
+		if err != nil {
+			return nil, fmt.Errorf("cannot dump %s: %v", f.field.Name, err)
+		}
+		out[f.name] = dumped
+	}
+	for k, val := range rest {
+		if _, ok := out[k]; !ok {
+			out[k] = val
+		}
+	}
+	return out, nil
+}
+
+func (e *ArgLoader) dumpValue(v reflect.Value) (interface{}, error) {
+	t := v.Type()
+	if dumpFunc, ok := e.dumpFuncs[t]; ok {
+		return dumpFunc(v)
+	}
+	if gqlType, ok := e.gqlTypes[t]; ok {
+		switch gqlType := gqlType.(type) {
+		case *graphql.Scalar:
+			return gqlType.Serialize(v.Interface()), nil
+		case *graphql.Enum:
+			return gqlType.Serialize(v.Interface()), nil
+		}
+		return nil, fmt.Errorf("no dumper func found for type %v", t)
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return e.dumpValue(v.Elem())
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && v.IsNil() {
+			return nil, nil
+		}
+		out := make([]interface{}, v.Len())
+		for idx := range out {
+			item, err := e.dumpValue(v.Index(idx))
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %v", idx, err)
+			}
+			out[idx] = item
+		}
+		return out, nil
+	case reflect.Struct:
+		if _, ok := optionalValueType(t); ok {
+			// an Optional or Null without a value is null, since dumpStruct has left out the omitted
+			// ones.
+			ptr := reflect.New(t)
+			ptr.Elem().Set(v)
+			inner, ok := ptr.Interface().(optional).value()
+			if !ok {
+				return nil, nil
+			}
+			return e.dumpValue(inner)
+		}
+		return e.dumpStruct(v)
+	}
+	return nil, fmt.Errorf("no dumper func found for type %v", t)
+}
+
+// DumpArgs converts an args struct back into an args map using the default loader.
+func DumpArgs(i interface{}) (map[string]interface{}, error) {
+	return Default().DumpArgs(i)
+}
+
+// RegisterDumper takes a func (<anytype>) (interface{}, error) and registers it on the default
+// loader as the dumper func for <anytype>.
+func RegisterDumper(f interface{}) error {
+	return Default().RegisterDumper(f)
+}
@@ -0,0 +1,152 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/graphql-go/graphql"
+)
+
+// EnumValue is one value of an enum registered with RegisterEnum.
+type EnumValue struct {
+	// Value is the Go value the enum value loads into.  Every value of an enum must have the same
+	// Go type.
+	Value interface{}
+	// Description documents the value in introspection.
+	Description string
+	// DeprecationReason marks the value as deprecated, with the reason shown in introspection.
+	DeprecationReason string
+}
+
+// RegisterEnum registers a graphql enum named name, with a value for each entry of values, keyed by
+// the enum value's name.  The Go type of the values becomes an arg and output type like any other
+// registered type, so args struct fields and resolver results of that type use the enum:
+//
+//	type Color string
+//
+//	colorEnum, err := loader.RegisterEnum("Color", "A paint color.", map[string]graphqlhelpers.EnumValue{
+//		"RED":    {Value: Color("red"), Description: "Fire engine red."},
+//		"YELLOW": {Value: Color("yellow")},
+//		"MAUVE":  {Value: Color("mauve"), DeprecationReason: "Discontinued."},
+//	})
+//
+// Args accept either a value's Go value, as graphql-go passes them, or its name.
+func (e *ArgLoader) RegisterEnum(name, description string, values map[string]EnumValue) (*graphql.Enum, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("enum %s has no values", name)
+	}
+	// sort the names so that errors are stable.
+	names := make([]string, 0, len(values))
+	for n := range values {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	var t reflect.Type
+	byName := map[string]reflect.Value{}
+	byValue := map[interface{}]string{}
+	config := graphql.EnumValueConfigMap{}
+	for _, n := range names {
+		v := values[n]
+		if v.Value == nil {
+			return nil, fmt.Errorf("enum %s: %s has no value", name, n)
+		}
+		if t == nil {
+			t = reflect.TypeOf(v.Value)
+		}
+		if reflect.TypeOf(v.Value) != t {
+			return nil, fmt.Errorf("enum %s: %s is a %T, but other values are %v", name, n, v.Value, t)
+		}
+		if !t.Comparable() {
+			return nil, fmt.Errorf("enum %s: %v values cannot be compared", name, t)
+		}
+		if other, ok := byValue[v.Value]; ok {
+			return nil, fmt.Errorf("enum %s: %s and %s have the same value %v", name, other, n, v.Value)
+		}
+		byName[n] = reflect.ValueOf(v.Value)
+		byValue[v.Value] = n
+		config[n] = &graphql.EnumValueConfig{
+			Value:             v.Value,
+			Description:       v.Description,
+			DeprecationReason: v.DeprecationReason,
+		}
+	}
+	enum := graphql.NewEnum(graphql.EnumConfig{Name: name, Description: description, Values: config})
+	if err := enum.Error(); err != nil {
+		return nil, fmt.Errorf("enum %s: %v", name, err)
+	}
+
+	loaderFunc := func(i interface{}) (reflect.Value, error) {
+		if reflect.TypeOf(i) == t {
+			if _, ok := byValue[i]; ok {
+				return reflect.ValueOf(i), nil
+			}
+		}
+		if s, ok := i.(string); ok {
+			if v, ok := byName[s]; ok {
+				return v, nil
+			}
+		}
+		return reflect.Value{}, fmt.Errorf("%v is not a %s value", i, name)
+	}
+	if err := e.register(t, loaderFunc, enum); err != nil {
+		return nil, err
+	}
+	if err := e.registerDumper(t, func(v reflect.Value) (interface{}, error) {
+		n, ok := byValue[v.Interface()]
+		if !ok {
+			return nil, fmt.Errorf("%v is not a %s value", v.Interface(), name)
+		}
+		return n, nil
+	}); err != nil {
+		return nil, err
+	}
+	return enum, nil
+}
+
+// RegisterStringerEnum registers a graphql enum for int constants that implement fmt.Stringer, such
+// as iota enums, with a value for each of values.  Each value is named after its String method,
+// converted to upper snake case, so a Status whose String returns "InReview" becomes IN_REVIEW:
+//
+//	type Status int
+//
+//	const (
+//		Draft Status = iota
+//		InReview
+//		Published
+//	)
+//
+//	statusEnum, err := loader.RegisterStringerEnum("Status", "", Draft, InReview, Published)
+//
+// Use RegisterEnum instead to describe or deprecate values.
+func (e *ArgLoader) RegisterStringerEnum(name, description string, values ...fmt.Stringer) (*graphql.Enum, error) {
+	config := map[string]EnumValue{}
+	for _, v := range values {
+		if v == nil {
+			return nil, fmt.Errorf("enum %s: values cannot be nil", name)
+		}
+		switch reflect.TypeOf(v).Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		default:
+			return nil, fmt.Errorf("enum %s: %v is a %T, not an int type", name, v, v)
+		}
+		n := enumName(v.String())
+		if other, ok := config[n]; ok {
+			return nil, fmt.Errorf("enum %s: %v and %v are both named %s", name, other.Value, v, n)
+		}
+		config[n] = EnumValue{Value: v}
+	}
+	return e.RegisterEnum(name, description, config)
+}
+
+// RegisterEnum registers a graphql enum on the default loader.
+func RegisterEnum(name, description string, values map[string]EnumValue) (*graphql.Enum, error) {
+	return Default().RegisterEnum(name, description, values)
+}
+
+// RegisterStringerEnum registers a graphql enum for int constants on the default loader.
+func RegisterStringerEnum(name, description string, values ...fmt.Stringer) (*graphql.Enum, error) {
+	return Default().RegisterStringerEnum(name, description, values...)
+}
@@ -0,0 +1,79 @@
+package graphqlhelpers
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorValuePolicy is how much of a bad arg value appears in the message of the error it causes,
+// like "abc is not an int".  Those messages usually reach clients, and through them logs and error
+// trackers, which shouldn't always see what was sent: a mistyped password, say.
+type ErrorValuePolicy int
+
+const (
+	// ErrorValuesFull leaves values in messages as they are.  It's the default.
+	ErrorValuesFull ErrorValuePolicy = iota
+	// ErrorValuesTruncated shortens values longer than 32 characters to their first 32, followed by
+	// "...".
+	ErrorValuesTruncated
+	// ErrorValuesOmitted replaces values with "the value", as in "the value is not an int".
+	ErrorValuesOmitted
+)
+
+// maxErrorValueLen is how many characters of a value ErrorValuesTruncated keeps.
+const maxErrorValueLen = 32
+
+// ValueError is the error a loader func returns for a value it can't load, like "abc is not an
+// int".  It keeps the value apart from the rest of the message, so that SetErrorValuePolicy can
+// build the message without it.  This package's loader funcs return ValueErrors; custom ones
+// should too.
+type ValueError struct {
+	Value interface{}
+	// Problem follows the value in the message, like "is not an int".
+	Problem string
+}
+
+func (e *ValueError) Error() string {
+	return fmt.Sprintf("%v %s", e.Value, e.Problem)
+}
+
+// SetErrorValuePolicy sets how much of a bad arg value appears in the errors returned by LoadArgs.
+//
+// The messages of ValueErrors are rebuilt with the value shortened or left out.  Other errors from
+// loader funcs may hold the value anywhere, in any format, so under ErrorValuesOmitted, and under
+// ErrorValuesTruncated when the value is too long, their messages are replaced with "<value> is
+// not valid".  Only messages are sanitized: the original error can still be reached with
+// errors.Unwrap, for logging on the server.
+func (e *ArgLoader) SetErrorValuePolicy(policy ErrorValuePolicy) {
+	e.errorValues = policy
+}
+
+// SetErrorValuePolicy sets how much of a bad arg value appears in the errors returned by the
+// default loader.
+func SetErrorValuePolicy(policy ErrorValuePolicy) {
+	Default().SetErrorValuePolicy(policy)
+}
+
+// valueError applies the loader's ErrorValuePolicy to err, caused by the raw arg value.
+func (e *ArgLoader) valueError(err error, value interface{}) error {
+	if e.errorValues == ErrorValuesFull {
+		return err
+	}
+	problem := "is not valid"
+	var valueErr *ValueError
+	if errors.As(err, &valueErr) {
+		value, problem = valueErr.Value, valueErr.Problem
+	}
+	var shown string
+	switch e.errorValues {
+	case ErrorValuesTruncated:
+		runes := []rune(fmt.Sprintf("%v", value))
+		if len(runes) <= maxErrorValueLen {
+			return err
+		}
+		shown = string(runes[:maxErrorValueLen]) + "..."
+	default:
+		shown = "the value"
+	}
+	return &messageError{msg: shown + " " + problem, err: err}
+}
@@ -0,0 +1,341 @@
+// Package federation lets a graphql-go service built with graphqlhelpers act as an Apollo
+// Federation (v1) subgraph.
+//
+// Entities are output structs with one or more key tags, each holding the fields of a @key
+// directive.  The tags can go on any field, including a blank one:
+//
+//	type User struct {
+//		_    struct{} `key:"id"`
+//		ID   string   `field:"id"`
+//		Name string   `field:"name"`
+//	}
+//
+// Register each entity with the func that resolves its representations, then add the _entities and
+// _service fields to the root query type:
+//
+//	fed := federation.New(loader)
+//	err := fed.Entity(User{}, func(ctx context.Context, rep User) (*User, error) {
+//		return db.FindUser(ctx, rep.ID)
+//	})
+//	err = fed.AddTo(builder)
+package federation
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+
+	graphqlhelpers "github.com/btubbs/graphql-go-helpers"
+)
+
+const keyTag = "key"
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// Any is the _Any scalar that entity representations are passed as.  It accepts any value.
+var Any = graphql.NewScalar(graphql.ScalarConfig{
+	Name:         "_Any",
+	Serialize:    func(v interface{}) interface{} { return v },
+	ParseValue:   func(v interface{}) interface{} { return v },
+	ParseLiteral: anyValue,
+})
+
+var serviceType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "_Service",
+	Fields: graphql.Fields{
+		"sdl": &graphql.Field{Type: graphql.String},
+	},
+})
+
+// Federation collects the entities of a subgraph.
+type Federation struct {
+	loader   *graphqlhelpers.ArgLoader
+	entities map[string]*entity
+	order    []string
+	fields   graphql.Fields
+}
+
+type entity struct {
+	model   reflect.Type
+	keys    []string
+	resolve reflect.Value
+	repType reflect.Type
+	repPtr  bool
+}
+
+// New returns a Federation whose entity objects are generated by loader.
+func New(loader *graphqlhelpers.ArgLoader) *Federation {
+	return &Federation{loader: loader, entities: map[string]*entity{}}
+}
+
+// Entity registers model's struct type as an entity.  resolve must be a func of the form
+//
+//	func(ctx context.Context, rep Rep) (Result, error)
+//
+// where Rep is a struct (or pointer to one) that representations are loaded into with LoadResult,
+// usually the entity struct itself, and Result is the entity struct or a pointer to it.  A nil
+// pointer means the entity wasn't found, and makes its item of _entities null.  An error resolving
+// any representation fails the whole _entities field, with no entities, since graphql-go can't
+// return errors for single items of a list.
+func (f *Federation) Entity(model interface{}, resolve interface{}) error {
+	if f.fields != nil {
+		return fmt.Errorf("entities must be registered before the federation fields are built")
+	}
+	t := reflect.TypeOf(model)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return fmt.Errorf("cannot register %v as an entity: entities must be structs", reflect.TypeOf(model))
+	}
+	keys := entityKeys(t)
+	if len(keys) == 0 {
+		return fmt.Errorf("cannot register %v as an entity: it has no %q tags", t, keyTag)
+	}
+	e := &entity{model: t, keys: keys, resolve: reflect.ValueOf(resolve)}
+	if err := e.setResolveType(); err != nil {
+		return fmt.Errorf("cannot register %v as an entity: %v", t, err)
+	}
+	out, err := f.loader.OutputType(reflect.New(t).Elem().Interface())
+	if err != nil {
+		return fmt.Errorf("cannot register %v as an entity: %v", t, err)
+	}
+	obj, ok := out.(*graphql.Object)
+	if !ok {
+		return fmt.Errorf("cannot register %v as an entity: its output type %v is not an object", t, out)
+	}
+	if _, dup := f.entities[obj.Name()]; dup {
+		return fmt.Errorf("%s has already been registered as an entity", obj.Name())
+	}
+	f.entities[obj.Name()] = e
+	f.order = append(f.order, obj.Name())
+	return nil
+}
+
+// entityKeys returns the values of the key tags on t's fields.
+func entityKeys(t reflect.Type) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		if key := t.Field(i).Tag.Get(keyTag); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func (e *entity) setResolveType() error {
+	if !e.resolve.IsValid() {
+		return fmt.Errorf("resolve func is nil")
+	}
+	t := e.resolve.Type()
+	if t.Kind() != reflect.Func || t.NumIn() != 2 || t.In(0) != contextType {
+		return fmt.Errorf("resolve func should accept a context.Context and a representation struct, not %v", t)
+	}
+	if t.NumOut() != 2 || !t.Out(1).Implements(errorType) {
+		return fmt.Errorf("resolve func should return a result and an error, not %v", t)
+	}
+	e.repType = t.In(1)
+	if e.repType.Kind() == reflect.Ptr {
+		e.repType = e.repType.Elem()
+		e.repPtr = true
+	}
+	if e.repType.Kind() != reflect.Struct {
+		return fmt.Errorf("resolve func's representation param should be a struct, not %v", t.In(1))
+	}
+	return nil
+}
+
+// Fields returns the _entities and _service fields for the root query type.  Call it after every
+// entity has been registered.
+func (f *Federation) Fields() (graphql.Fields, error) {
+	if f.fields != nil {
+		return f.fields, nil
+	}
+	if len(f.order) == 0 {
+		return nil, fmt.Errorf("no entities have been registered")
+	}
+	members := make([]interface{}, len(f.order))
+	for i, name := range f.order {
+		members[i] = reflect.New(f.entities[name].model).Elem().Interface()
+	}
+	union, err := f.loader.Union("_Entity", members...)
+	if err != nil {
+		return nil, err
+	}
+	f.fields = graphql.Fields{
+		"_entities": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.NewList(union)),
+			Args: graphql.FieldConfigArgument{
+				"representations": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(Any))),
+				},
+			},
+			Resolve: f.resolveEntities,
+		},
+		"_service": &graphql.Field{
+			Type: graphql.NewNonNull(serviceType),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return map[string]interface{}{"sdl": f.SDL(p.Info.Schema)}, nil
+			},
+		},
+	}
+	return f.fields, nil
+}
+
+// AddTo adds the _entities and _service fields to the root query type of b.
+func (f *Federation) AddTo(b *graphqlhelpers.SchemaBuilder) error {
+	fields, err := f.Fields()
+	if err != nil {
+		return err
+	}
+	for name, field := range fields {
+		b.AddField("query", name, field)
+	}
+	return nil
+}
+
+func (f *Federation) resolveEntities(p graphql.ResolveParams) (interface{}, error) {
+	ctx := p.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	reps, _ := p.Args["representations"].([]interface{})
+	out := make([]interface{}, len(reps))
+	for i, raw := range reps {
+		rep, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("representation %d is not an object", i)
+		}
+		typeName, _ := rep["__typename"].(string)
+		e, ok := f.entities[typeName]
+		if !ok {
+			return nil, fmt.Errorf("representation %d: %q is not an entity type", i, typeName)
+		}
+		repVal := reflect.New(e.repType)
+		if err := f.loader.LoadResult(rep, repVal.Interface()); err != nil {
+			return nil, fmt.Errorf("representation %d: %v", i, err)
+		}
+		if !e.repPtr {
+			repVal = repVal.Elem()
+		}
+		results := e.resolve.Call([]reflect.Value{reflect.ValueOf(ctx), repVal})
+		if !results[1].IsNil() {
+			return nil, results[1].Interface().(error)
+		}
+		if results[0].Kind() == reflect.Ptr && results[0].IsNil() {
+			// a typed nil isn't null to graphql-go, which would resolve its fields.
+			continue
+		}
+		out[i] = results[0].Interface()
+	}
+	return out, nil
+}
+
+// SDL returns the subgraph SDL served by _service: the schema without the federation types and
+// fields, with @key directives on the entity types.
+func (f *Federation) SDL(schema graphql.Schema) string {
+	var out []string
+	for _, def := range strings.Split(strings.TrimSuffix(graphqlhelpers.PrintSchema(schema), "\n"), "\n\n") {
+		lines := strings.Split(def, "\n")
+		h := headerIndex(lines)
+		name := typeName(lines[h])
+		switch name {
+		case "_Any", "_Entity", "_Service":
+			continue
+		}
+		if e, ok := f.entities[name]; ok {
+			lines[h] = strings.TrimSuffix(lines[h], " {") + keyDirectives(e.keys) + " {"
+		}
+		out = append(out, strings.Join(filterLines(lines), "\n"))
+	}
+	return strings.Join(out, "\n\n") + "\n"
+}
+
+// headerIndex returns the index of the line after a definition's description, like "type User {".
+func headerIndex(lines []string) int {
+	i := 0
+	switch {
+	case strings.HasPrefix(lines[0], `"""`):
+		for i = 1; i < len(lines) && lines[i] != `"""`; i++ {
+		}
+		i++
+	case strings.HasPrefix(lines[0], `"`):
+		i = 1
+	}
+	if i >= len(lines) {
+		return len(lines) - 1
+	}
+	return i
+}
+
+// filterLines drops the federation fields from the lines of a type definition.
+func filterLines(lines []string) []string {
+	var out []string
+	for _, line := range lines {
+		if strings.HasPrefix(line, "  _entities(") || strings.HasPrefix(line, "  _service:") {
+			continue
+		}
+		out = append(out, line)
+	}
+	return out
+}
+
+// typeName returns the name of the type defined by an SDL header line like "type User {".
+func typeName(header string) string {
+	fields := strings.Fields(header)
+	if len(fields) < 2 {
+		return ""
+	}
+	return fields[1]
+}
+
+func keyDirectives(keys []string) string {
+	var b strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&b, " @key(fields: %s)", strconv.Quote(key))
+	}
+	return b.String()
+}
+
+// anyValue converts an inline _Any literal to the Go value a variable would have decoded to.
+func anyValue(v ast.Value) interface{} {
+	switch v := v.(type) {
+	case *ast.ObjectValue:
+		m := make(map[string]interface{}, len(v.Fields))
+		for _, field := range v.Fields {
+			m[field.Name.Value] = anyValue(field.Value)
+		}
+		return m
+	case *ast.ListValue:
+		items := make([]interface{}, len(v.Values))
+		for i, item := range v.Values {
+			items[i] = anyValue(item)
+		}
+		return items
+	case *ast.IntValue:
+		if i, err := strconv.Atoi(v.Value); err == nil {
+			return i
+		}
+		return v.Value
+	case *ast.FloatValue:
+		if f, err := strconv.ParseFloat(v.Value, 64); err == nil {
+			return f
+		}
+		return v.Value
+	case *ast.StringValue:
+		return v.Value
+	case *ast.BooleanValue:
+		return v.Value
+	case *ast.EnumValue:
+		return v.Value
+	}
+	return nil
+}
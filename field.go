@@ -0,0 +1,247 @@
+package graphqlhelpers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime"
+	"time"
+
+	"github.com/graphql-go/graphql"
+)
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// fieldOptions holds the settings FieldOptions apply to a field built by FieldFromFunc.
+type fieldOptions struct {
+	description       string
+	deprecationReason string
+	middleware        []Middleware
+	cacheTTL          time.Duration
+	pooledArgs        bool
+	retry             *RetryPolicy
+}
+
+// FieldOption customizes a field built by FieldFromFunc.
+type FieldOption func(*fieldOptions)
+
+// WithDescription sets the field's description.
+func WithDescription(desc string) FieldOption {
+	return func(o *fieldOptions) {
+		o.description = desc
+	}
+}
+
+// WithDeprecation marks the field as deprecated for the given reason.
+func WithDeprecation(reason string) FieldOption {
+	return func(o *fieldOptions) {
+		o.deprecationReason = reason
+	}
+}
+
+// WithMiddleware wraps the field's resolver in mw, inside any middleware installed on the loader
+// with Use.
+func WithMiddleware(mw ...Middleware) FieldOption {
+	return func(o *fieldOptions) {
+		o.middleware = append(o.middleware, mw...)
+	}
+}
+
+// FieldFromFunc builds a complete graphql.Field from a resolver func of the form
+//
+//	func(ctx context.Context, args Args) (Result, error)
+//
+// where Args is an args struct (or pointer to one) and may be left out entirely for fields without
+// arguments.  The field's Args come from ArgsConfig(Args), its Type comes from OutputType(Result),
+// and its resolver loads the args and calls fn.  If fn doesn't have a supported signature, or its
+// types can't be configured, this function will panic.
+func (e *ArgLoader) FieldFromFunc(fn interface{}, opts ...FieldOption) *graphql.Field {
+	field, err := e.SafeFieldFromFunc(fn, opts...)
+	if err != nil {
+		panic(fmt.Sprintf("could not build field: %v", err))
+	}
+	return field
+}
+
+// SafeFieldFromFunc is like FieldFromFunc, but returns an error instead of panicking.
+func (e *ArgLoader) SafeFieldFromFunc(fn interface{}, opts ...FieldOption) (*graphql.Field, error) {
+	sig, err := inspectResolverFunc(fn)
+	if err != nil {
+		return nil, err
+	}
+	var o fieldOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	field := &graphql.Field{
+		Description:       o.description,
+		DeprecationReason: o.deprecationReason,
+	}
+	if sig.argsType != nil {
+		field.Args, err = e.SafeArgsConfig(reflect.New(sig.argsType).Interface())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", sig.name, err)
+		}
+	}
+	field.Type, err = e.outputType(sig.resultType)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", sig.name, err)
+	}
+	sig.cacheTTL = o.cacheTTL
+	if sig.argsType != nil && sig.cacheTTL == 0 {
+		if sig.cacheTTL, err = cacheTTL(sig.argsType); err != nil {
+			return nil, fmt.Errorf("%s: %v", sig.name, err)
+		}
+	}
+	if sig.argsType != nil {
+		if err := e.noteArgCosts(field, sig.argsType); err != nil {
+			return nil, fmt.Errorf("%s: %v", sig.name, err)
+		}
+	}
+	sig.pooled = o.pooledArgs
+	sig.retry = o.retry
+	field.Resolve = e.resolver(sig, o.middleware...)
+	return field, nil
+}
+
+// Resolver wraps a resolver func of the form accepted by FieldFromFunc in a graphql.FieldResolveFn,
+// for fields whose config is written by hand.  The loader's middleware and any given middleware are
+// applied as with FieldFromFunc.  If fn doesn't have a supported signature, this function will
+// panic.
+func (e *ArgLoader) Resolver(fn interface{}, mw ...Middleware) graphql.FieldResolveFn {
+	sig, err := inspectResolverFunc(fn)
+	if err != nil {
+		panic(fmt.Sprintf("could not build resolver: %v", err))
+	}
+	return e.resolver(sig, mw...)
+}
+
+// resolverFunc describes a func accepted by FieldFromFunc.
+type resolverFunc struct {
+	name       string
+	fn         reflect.Value
+	argsType   reflect.Type // the struct type of the args param, or nil if there isn't one.
+	argsPtr    bool         // whether the args param is a pointer.
+	resultType reflect.Type
+	cacheTTL   time.Duration // how long results are cached for, or 0 if they aren't.
+	pooled     bool          // whether args structs are taken from their pool.
+	retry      *RetryPolicy  // how failed calls of fn are retried, or nil if they aren't.
+}
+
+func inspectResolverFunc(fn interface{}) (resolverFunc, error) {
+	t := reflect.TypeOf(fn)
+	if t == nil || t.Kind() != reflect.Func {
+		return resolverFunc{}, fmt.Errorf("%v is not a func", fn)
+	}
+	sig := resolverFunc{
+		name: runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name(),
+		fn:   reflect.ValueOf(fn),
+	}
+	if t.NumIn() < 1 || t.NumIn() > 2 || t.In(0) != contextType {
+		return resolverFunc{}, fmt.Errorf(
+			"resolver func should accept a context.Context and an optional args struct. %s accepts %v", sig.name, t)
+	}
+	if t.NumIn() == 2 {
+		sig.argsType = t.In(1)
+		if sig.argsType.Kind() == reflect.Ptr {
+			sig.argsType = sig.argsType.Elem()
+			sig.argsPtr = true
+		}
+		if sig.argsType.Kind() != reflect.Struct {
+			return resolverFunc{}, fmt.Errorf("%s's args param should be a struct, not %v", sig.name, t.In(1))
+		}
+	}
+	if t.NumOut() != 2 || !t.Out(1).Implements(errorType) {
+		return resolverFunc{}, fmt.Errorf(
+			"resolver func should return a result and an error. %s returns %v", sig.name, t)
+	}
+	sig.resultType = t.Out(0)
+	return sig, nil
+}
+
+// resolver returns the resolver for sig, wrapped in the loader's middleware and then mw.
+func (e *ArgLoader) resolver(sig resolverFunc, mw ...Middleware) graphql.FieldResolveFn {
+	return e.wrap(e.concurrentResolver(sig.resultType, e.valuerResolver(sig.resultType, e.flagResolver(sig.resultType, func(p graphql.ResolveParams) (interface{}, error) {
+		ctx := p.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		in := []reflect.Value{reflect.ValueOf(ctx)}
+		var loaded interface{}
+		if sig.argsType != nil {
+			var args reflect.Value
+			if sig.pooled {
+				ptr := argsPool(sig.argsType).Get()
+				defer releaseArgs(ptr)
+				args = reflect.ValueOf(ptr)
+			} else {
+				args = reflect.New(sig.argsType)
+			}
+			if err := e.LoadArgs(p, args.Interface()); err != nil {
+				return nil, err
+			}
+			loaded = args.Interface()
+			if !sig.argsPtr {
+				args = args.Elem()
+			}
+			in = append(in, args)
+		}
+		var key string
+		if sig.cacheTTL > 0 && e.resultCache != nil {
+			var err error
+			if key, err = e.cacheKey(FieldPath(p.Info), loaded); err != nil {
+				return nil, fmt.Errorf("cannot cache %s: %v", sig.name, err)
+			}
+			if cached, ok := e.resultCache.Get(ctx, key); ok {
+				return cached, nil
+			}
+		}
+		call := func() (interface{}, error) {
+			out := sig.fn.Call(in)
+			if !out[1].IsNil() {
+				return nil, out[1].Interface().(error)
+			}
+			return out[0].Interface(), nil
+		}
+		var result interface{}
+		var err error
+		if sig.retry != nil {
+			result, err = sig.retry.do(ctx, call)
+		} else {
+			result, err = call()
+		}
+		if err != nil {
+			return nil, err
+		}
+		if key != "" {
+			e.resultCache.Set(ctx, key, result, sig.cacheTTL)
+		}
+		return result, nil
+	}))), mw...)
+}
+
+// wrap wraps resolve in the loader's middleware and then mw.  Outside them all, errors are logged for
+// the Response.
+func (e *ArgLoader) wrap(resolve graphql.FieldResolveFn, mw ...Middleware) graphql.FieldResolveFn {
+	return Chain(append(append([]Middleware{recordErrors}, e.middleware...), mw...)...)(resolve)
+}
+
+// FieldFromFunc builds a graphql.Field from a resolver func using the default loader.
+func FieldFromFunc(fn interface{}, opts ...FieldOption) *graphql.Field {
+	return Default().FieldFromFunc(fn, opts...)
+}
+
+// SafeFieldFromFunc builds a graphql.Field from a resolver func using the default loader, returning
+// an error instead of panicking.
+func SafeFieldFromFunc(fn interface{}, opts ...FieldOption) (*graphql.Field, error) {
+	return Default().SafeFieldFromFunc(fn, opts...)
+}
+
+// Resolver wraps a resolver func in a graphql.FieldResolveFn using the default loader.
+func Resolver(fn interface{}, mw ...Middleware) graphql.FieldResolveFn {
+	return Default().Resolver(fn, mw...)
+}
@@ -0,0 +1,349 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/graphql-go/graphql"
+)
+
+// filterTag marks model struct fields that may be filtered on.  Its value is the name of the field
+// in the generated filter input object, and is used as Condition.Field.
+const filterTag = "filter"
+
+// FilterOp is a comparison operator in a Condition.
+type FilterOp string
+
+// The supported filter operators.  Which of them are offered for a field depends on its type:
+// every field gets eq, ne and in; numbers and times also get gt and lt; strings also get contains.
+const (
+	OpEq       FilterOp = "eq"
+	OpNe       FilterOp = "ne"
+	OpIn       FilterOp = "in"
+	OpGt       FilterOp = "gt"
+	OpLt       FilterOp = "lt"
+	OpContains FilterOp = "contains"
+)
+
+// Condition is a single comparison in a Filter.  Value holds a value of the model field's Go type,
+// or a []interface{} of them for OpIn.
+type Condition struct {
+	Field string
+	Op    FilterOp
+	Value interface{}
+}
+
+// Filter is a loaded filter tree.  All of its Conditions must match, all of the And filters must
+// match, and at least one of the Or filters must match (if there are any).
+type Filter struct {
+	Conditions []Condition
+	And        []Filter
+	Or         []Filter
+}
+
+// filterField is a filterable field of a model.
+type filterField struct {
+	name   string
+	goType reflect.Type
+	ops    []FilterOp
+}
+
+// RegisterFilter registers target's type as a filter argument for model.  target must be a value of
+// Filter or of a type defined as Filter (e.g. "type UserFilter graphqlhelpers.Filter").  The
+// generated input object has a field per filter-tagged model field, each accepting the operators
+// that make sense for its type, plus "and" and "or" lists for combining filters.
+func (e *ArgLoader) RegisterFilter(target interface{}, model interface{}) error {
+	targetType := reflect.TypeOf(target)
+	filterType := reflect.TypeOf(Filter{})
+	if targetType.Kind() != reflect.Struct || !targetType.ConvertibleTo(filterType) {
+		return fmt.Errorf("%v cannot be used as a Filter", targetType)
+	}
+	modelType := reflect.TypeOf(model)
+	if modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+	if modelType.Kind() != reflect.Struct {
+		return fmt.Errorf("%v is not a struct", model)
+	}
+
+	fields := map[string]filterField{}
+	opTypes := map[string]*graphql.InputObject{}
+	for i := 0; i < modelType.NumField(); i++ {
+		field := modelType.Field(i)
+		name, ok := field.Tag.Lookup(filterTag)
+		if !ok {
+			continue
+		}
+		if name == "and" || name == "or" {
+			return fmt.Errorf("%s.%s: %q is reserved in filters", modelType.Name(), field.Name, name)
+		}
+		valueType, ok := e.gqlTypes[field.Type]
+		if !ok {
+			return fmt.Errorf("%s.%s: no loader function found for type %v",
+				modelType.Name(), field.Name, field.Type)
+		}
+		ff := filterField{name: name, goType: field.Type, ops: filterOps(field.Type)}
+		fields[name] = ff
+		opTypes[name] = e.filterOpsObject(valueType, ff.ops)
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("%v has no fields with a %q tag", modelType, filterTag)
+	}
+
+	var gqlType *graphql.InputObject
+	gqlType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: modelType.Name() + "Filter",
+		Fields: graphql.InputObjectConfigFieldMapThunk(func() graphql.InputObjectConfigFieldMap {
+			out := graphql.InputObjectConfigFieldMap{
+				"and": &graphql.InputObjectFieldConfig{
+					Type:        graphql.NewList(graphql.NewNonNull(gqlType)),
+					Description: "All of these filters must match",
+				},
+				"or": &graphql.InputObjectFieldConfig{
+					Type:        graphql.NewList(graphql.NewNonNull(gqlType)),
+					Description: "At least one of these filters must match",
+				},
+			}
+			for name, obj := range opTypes {
+				out[name] = &graphql.InputObjectFieldConfig{Type: obj}
+			}
+			return out
+		}),
+	})
+
+	loaderFunc := func(i interface{}) (reflect.Value, error) {
+		f, err := e.loadFilter(fields, i)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(f).Convert(targetType), nil
+	}
+	if err := e.register(targetType, loaderFunc, gqlType); err != nil {
+		return err
+	}
+	return e.registerDumper(targetType, func(v reflect.Value) (interface{}, error) {
+		return e.dumpFilter(fields, v.Convert(filterType).Interface().(Filter))
+	})
+}
+
+// filterOpsObject returns the input object holding the operators for values of valueType, such as
+// IntFilter.  These are shared by every model, so they are cached by name.
+func (e *ArgLoader) filterOpsObject(valueType graphql.Output, ops []FilterOp) *graphql.InputObject {
+	name := valueType.Name() + "Filter"
+	if obj, ok := e.filterOpTypes[name]; ok {
+		return obj
+	}
+	fields := graphql.InputObjectConfigFieldMap{}
+	for _, op := range ops {
+		var opType graphql.Input = valueType
+		if op == OpIn {
+			opType = graphql.NewList(graphql.NewNonNull(valueType))
+		}
+		fields[string(op)] = &graphql.InputObjectFieldConfig{
+			Type:        opType,
+			Description: opDescriptions[op],
+		}
+	}
+	obj := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:   name,
+		Fields: fields,
+	})
+	e.filterOpTypes[name] = obj
+	return obj
+}
+
+var opDescriptions = map[FilterOp]string{
+	OpEq:       "Equal to",
+	OpNe:       "Not equal to",
+	OpIn:       "Equal to any of",
+	OpGt:       "Greater than",
+	OpLt:       "Less than",
+	OpContains: "Contains the substring",
+}
+
+// filterOps returns the operators that apply to values of t.
+func filterOps(t reflect.Type) []FilterOp {
+	ops := []FilterOp{OpEq, OpNe, OpIn}
+	switch t.Kind() {
+	case reflect.String:
+		ops = append(ops, OpContains)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		ops = append(ops, OpGt, OpLt)
+	case reflect.Bool:
+		ops = ops[:2]
+	default:
+		if t == reflect.TypeOf(time.Time{}) {
+			ops = append(ops, OpGt, OpLt)
+		}
+	}
+	return ops
+}
+
+// loadFilter loads a Filter from a raw input object value.
+func (e *ArgLoader) loadFilter(fields map[string]filterField, i interface{}) (Filter, error) {
+	m, ok := i.(map[string]interface{})
+	if !ok {
+		return Filter{}, e.valueError(&ValueError{Value: i, Problem: "is not an input object"}, i)
+	}
+	var f Filter
+	var err error
+	if f.And, err = e.loadFilterList(fields, m["and"]); err != nil {
+		return Filter{}, fmt.Errorf("and: %v", err)
+	}
+	if f.Or, err = e.loadFilterList(fields, m["or"]); err != nil {
+		return Filter{}, fmt.Errorf("or: %v", err)
+	}
+
+	// sort the keys so that the order of conditions is stable.
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		if k != "and" && k != "or" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		ff, ok := fields[k]
+		if !ok {
+			return Filter{}, fmt.Errorf("%s is not a filterable field", k)
+		}
+		opsMap, ok := m[k].(map[string]interface{})
+		if !ok {
+			return Filter{}, fmt.Errorf("%s: %w", k, e.valueError(&ValueError{Value: m[k], Problem: "is not an input object"}, m[k]))
+		}
+		conds, err := e.loadConditions(ff, opsMap)
+		if err != nil {
+			return Filter{}, fmt.Errorf("%s: %v", k, err)
+		}
+		f.Conditions = append(f.Conditions, conds...)
+	}
+	return f, nil
+}
+
+func (e *ArgLoader) loadFilterList(fields map[string]filterField, i interface{}) ([]Filter, error) {
+	if i == nil {
+		return nil, nil
+	}
+	items, ok := i.([]interface{})
+	if !ok {
+		return nil, e.valueError(&ValueError{Value: i, Problem: "is not a list"}, i)
+	}
+	out := make([]Filter, 0, len(items))
+	for idx, item := range items {
+		f, err := e.loadFilter(fields, item)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %v", idx, err)
+		}
+		out = append(out, f)
+	}
+	return out, nil
+}
+
+// loadConditions loads the conditions for one field, in the order the operators are declared.
+func (e *ArgLoader) loadConditions(ff filterField, opsMap map[string]interface{}) ([]Condition, error) {
+	var out []Condition
+	for _, op := range ff.ops {
+		raw, ok := opsMap[string(op)]
+		if !ok {
+			continue
+		}
+		var value interface{}
+		if op == OpIn {
+			items, ok := raw.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s: %w", op, e.valueError(&ValueError{Value: raw, Problem: "is not a list"}, raw))
+			}
+			values := make([]interface{}, 0, len(items))
+			for idx, item := range items {
+				v, err := e.loadValue(ff.goType, item)
+				if err != nil {
+					return nil, fmt.Errorf("%s: item %d: %v", op, idx, err)
+				}
+				values = append(values, v.Interface())
+			}
+			value = values
+		} else {
+			v, err := e.loadValue(ff.goType, raw)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", op, err)
+			}
+			value = v.Interface()
+		}
+		out = append(out, Condition{Field: ff.name, Op: op, Value: value})
+	}
+	for k := range opsMap {
+		if !hasOp(ff.ops, FilterOp(k)) {
+			return nil, fmt.Errorf("%s is not a supported operator", k)
+		}
+	}
+	return out, nil
+}
+
+// dumpFilter converts a Filter back into the input object that would load into it.
+func (e *ArgLoader) dumpFilter(fields map[string]filterField, f Filter) (map[string]interface{}, error) {
+	out := map[string]interface{}{}
+	for _, c := range f.Conditions {
+		if _, ok := fields[c.Field]; !ok {
+			return nil, fmt.Errorf("%s is not a filterable field", c.Field)
+		}
+		ops, ok := out[c.Field].(map[string]interface{})
+		if !ok {
+			ops = map[string]interface{}{}
+			out[c.Field] = ops
+		}
+		if c.Op != OpIn {
+			v, err := e.dumpValue(reflect.ValueOf(c.Value))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s: %v", c.Field, c.Op, err)
+			}
+			ops[string(c.Op)] = v
+			continue
+		}
+		values, ok := c.Value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: %s: %v is not a list", c.Field, c.Op, c.Value)
+		}
+		dumped := make([]interface{}, len(values))
+		for idx, value := range values {
+			v, err := e.dumpValue(reflect.ValueOf(value))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s: item %d: %v", c.Field, c.Op, idx, err)
+			}
+			dumped[idx] = v
+		}
+		ops[string(c.Op)] = dumped
+	}
+	for key, subs := range map[string][]Filter{"and": f.And, "or": f.Or} {
+		if len(subs) == 0 {
+			continue
+		}
+		dumped := make([]interface{}, len(subs))
+		for idx, sub := range subs {
+			v, err := e.dumpFilter(fields, sub)
+			if err != nil {
+				return nil, fmt.Errorf("%s: item %d: %v", key, idx, err)
+			}
+			dumped[idx] = v
+		}
+		out[key] = dumped
+	}
+	return out, nil
+}
+
+func hasOp(ops []FilterOp, op FilterOp) bool {
+	for _, o := range ops {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// RegisterFilter registers target's type as a filter argument for model on the default loader.
+func RegisterFilter(target interface{}, model interface{}) error {
+	return Default().RegisterFilter(target, model)
+}
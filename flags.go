@@ -0,0 +1,238 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/graphql-go/graphql"
+)
+
+// flagEnum is a flag enum registered with RegisterFlagEnum.
+type flagEnum struct {
+	name string
+	t    reflect.Type
+	// the flags, in order of their values.
+	names []string
+	bits  []uint64
+}
+
+// RegisterFlagEnum registers a graphql enum of bit flags, named name, with a value for each entry of
+// flags, keyed by the enum value's name.  Flag values must be non-zero, of one int type.  That type
+// is then used for lists of the enum's values: args accept a list of flags, OR-ed together into one
+// bitmask, and results are resolved as the list of flags set in the bitmask, in order of value.
+//
+//	type Permission uint
+//
+//	const (
+//		Read Permission = 1 << iota
+//		Write
+//		Admin
+//	)
+//
+//	permissionEnum, err := loader.RegisterFlagEnum("Permission", "", map[string]graphqlhelpers.EnumValue{
+//		"READ":  {Value: Read},
+//		"WRITE": {Value: Write},
+//		"ADMIN": {Value: Admin, Description: "Full control."},
+//	})
+//
+// Args and results of type Permission then have the type [Permission!].  Values may combine flags,
+// like an ALL value of Read | Write | Admin, but a result with bits that no flag covers fails.
+func (e *ArgLoader) RegisterFlagEnum(name, description string, flags map[string]EnumValue) (*graphql.Enum, error) {
+	if len(flags) == 0 {
+		return nil, fmt.Errorf("enum %s has no values", name)
+	}
+	f := &flagEnum{name: name}
+	for n := range flags {
+		f.names = append(f.names, n)
+	}
+	sort.Strings(f.names)
+
+	config := graphql.EnumValueConfigMap{}
+	byName := map[string]uint64{}
+	for _, n := range f.names {
+		v := flags[n]
+		if v.Value == nil {
+			return nil, fmt.Errorf("enum %s: %s has no value", name, n)
+		}
+		if f.t == nil {
+			f.t = reflect.TypeOf(v.Value)
+		}
+		if reflect.TypeOf(v.Value) != f.t {
+			return nil, fmt.Errorf("enum %s: %s is a %T, but other values are %v", name, n, v.Value, f.t)
+		}
+		bits, ok := flagBits(reflect.ValueOf(v.Value))
+		if !ok {
+			return nil, fmt.Errorf("enum %s: %s is a %T, not an int type", name, n, v.Value)
+		}
+		if bits == 0 {
+			return nil, fmt.Errorf("enum %s: %s is zero, which can't be told apart from no flags", name, n)
+		}
+		byName[n] = bits
+		config[n] = &graphql.EnumValueConfig{
+			Value:             v.Value,
+			Description:       v.Description,
+			DeprecationReason: v.DeprecationReason,
+		}
+	}
+	sort.SliceStable(f.names, func(i, j int) bool { return byName[f.names[i]] < byName[f.names[j]] })
+	for _, n := range f.names {
+		f.bits = append(f.bits, byName[n])
+	}
+	enum := graphql.NewEnum(graphql.EnumConfig{Name: name, Description: description, Values: config})
+	if err := enum.Error(); err != nil {
+		return nil, fmt.Errorf("enum %s: %v", name, err)
+	}
+	if _, taken := e.typeNames[name]; taken {
+		return nil, fmt.Errorf("cannot register enum %s: the name is already used by %s", name, e.typeDescription(name))
+	}
+	if _, registered := e.outputTypes[f.t]; registered {
+		return nil, fmt.Errorf("an output type has already been registered for the %v type", f.t)
+	}
+
+	listType := graphql.NewList(graphql.NewNonNull(enum))
+	loaderFunc := func(i interface{}) (reflect.Value, error) {
+		items, ok := i.([]interface{})
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("%v is not a list of %s values", i, name)
+		}
+		var mask uint64
+		for _, item := range items {
+			if s, ok := item.(string); ok {
+				bits, ok := byName[s]
+				if !ok {
+					return reflect.Value{}, fmt.Errorf("%v is not a %s value", item, name)
+				}
+				mask |= bits
+				continue
+			}
+			v := reflect.ValueOf(item)
+			if !v.IsValid() || v.Type() != f.t {
+				return reflect.Value{}, fmt.Errorf("%v is not a %s value", item, name)
+			}
+			bits, _ := flagBits(v)
+			mask |= bits
+		}
+		return f.value(mask), nil
+	}
+	if err := e.register(f.t, loaderFunc, listType); err != nil {
+		return nil, err
+	}
+	if err := e.registerDumper(f.t, func(v reflect.Value) (interface{}, error) {
+		set, err := f.split(v)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]interface{}, len(set))
+		for i, idx := range set {
+			names[i] = f.names[idx]
+		}
+		return names, nil
+	}); err != nil {
+		return nil, err
+	}
+	e.typeNames[name] = enum
+	e.outputTypes[f.t] = listType
+	e.flagTypes[f.t] = f
+	return enum, nil
+}
+
+// RegisterFlagEnum registers a graphql enum of bit flags on the default loader.
+func RegisterFlagEnum(name, description string, flags map[string]EnumValue) (*graphql.Enum, error) {
+	return Default().RegisterFlagEnum(name, description, flags)
+}
+
+// flagBits returns the bits of v, an int or uint of some kind.
+func flagBits(v reflect.Value) (uint64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return uint64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint(), true
+	}
+	return 0, false
+}
+
+// value returns mask as a value of the flag type.
+func (f *flagEnum) value(mask uint64) reflect.Value {
+	v := reflect.New(f.t).Elem()
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(mask))
+	default:
+		v.SetUint(mask)
+	}
+	return v
+}
+
+// split returns the indexes of the flags set in v.  A flag that combines others is only included
+// when the flags it combines aren't, so that an ALL flag stands in for the flags it covers.
+func (f *flagEnum) split(v reflect.Value) ([]int, error) {
+	mask, _ := flagBits(v)
+	var set []int
+	var covered uint64
+	// the widest flags are tried first, so a combined flag wins over its parts.
+	order := make([]int, len(f.bits))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return popCount(f.bits[order[i]]) > popCount(f.bits[order[j]]) })
+	for _, idx := range order {
+		bits := f.bits[idx]
+		if mask&bits == bits && covered&bits != bits {
+			set = append(set, idx)
+			covered |= bits
+		}
+	}
+	if mask&^covered != 0 {
+		return nil, fmt.Errorf("%v has bits that are not %s values", v.Interface(), f.name)
+	}
+	sort.Ints(set)
+	return set, nil
+}
+
+func popCount(x uint64) int {
+	n := 0
+	for ; x != 0; x &= x - 1 {
+		n++
+	}
+	return n
+}
+
+// flagResolver converts the bitmasks resolve returns to lists of flags, if t, the Go type it
+// returns, is a flag enum registered with RegisterFlagEnum (or a pointer to one).
+func (e *ArgLoader) flagResolver(t reflect.Type, resolve graphql.FieldResolveFn) graphql.FieldResolveFn {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	f, ok := e.flagTypes[t]
+	if !ok {
+		return resolve
+	}
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		result, err := resolve(p)
+		if err != nil || result == nil {
+			return result, err
+		}
+		v := reflect.ValueOf(result)
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return nil, nil
+			}
+			v = v.Elem()
+		}
+		if v.Type() != f.t {
+			// resolved by something other than what the field was built for; leave it to graphql-go.
+			return result, nil
+		}
+		set, err := f.split(v)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, len(set))
+		for i, idx := range set {
+			values[i] = f.value(f.bits[idx]).Interface()
+		}
+		return values, nil
+	}
+}
@@ -0,0 +1,226 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/graphql-go/graphql"
+)
+
+// GeoPoint is a location, in degrees.  After RegisterGeoTypes, args of type GeoPoint are
+// GeoPointInput input objects, loaded with LoadGeoPoint, and results of type GeoPoint are GeoPoint
+// objects.
+type GeoPoint struct {
+	Lat float64
+	Lng float64
+}
+
+// Validate checks that the latitude is between -90 and 90 and the longitude between -180 and 180.
+func (p GeoPoint) Validate() error {
+	if math.IsNaN(p.Lat) || p.Lat < -90 || p.Lat > 90 {
+		return fmt.Errorf("lat must be between -90 and 90, got %v", p.Lat)
+	}
+	if math.IsNaN(p.Lng) || p.Lng < -180 || p.Lng > 180 {
+		return fmt.Errorf("lng must be between -180 and 180, got %v", p.Lng)
+	}
+	return nil
+}
+
+// BoundingBox is the area between two corners.  A box whose SouthWest longitude is greater than its
+// NorthEast longitude crosses the antimeridian, so a box from 170 to -170 is 20 degrees wide.
+type BoundingBox struct {
+	SouthWest GeoPoint
+	NorthEast GeoPoint
+}
+
+// Validate checks both corners, and that the south-west corner isn't north of the north-east one.
+func (b BoundingBox) Validate() error {
+	if err := b.SouthWest.Validate(); err != nil {
+		return fmt.Errorf("southWest: %v", err)
+	}
+	if err := b.NorthEast.Validate(); err != nil {
+		return fmt.Errorf("northEast: %v", err)
+	}
+	if b.SouthWest.Lat > b.NorthEast.Lat {
+		return fmt.Errorf("southWest lat (%v) must not be greater than northEast lat (%v)",
+			b.SouthWest.Lat, b.NorthEast.Lat)
+	}
+	return nil
+}
+
+// CrossesAntimeridian reports whether the box spans the 180th meridian.
+func (b BoundingBox) CrossesAntimeridian() bool {
+	return b.SouthWest.Lng > b.NorthEast.Lng
+}
+
+// Contains reports whether p falls within the box, edges included.
+func (b BoundingBox) Contains(p GeoPoint) bool {
+	if p.Lat < b.SouthWest.Lat || p.Lat > b.NorthEast.Lat {
+		return false
+	}
+	if b.CrossesAntimeridian() {
+		return p.Lng >= b.SouthWest.Lng || p.Lng <= b.NorthEast.Lng
+	}
+	return p.Lng >= b.SouthWest.Lng && p.Lng <= b.NorthEast.Lng
+}
+
+// GeoPointInput is the graphql input type of GeoPoint args.
+var GeoPointInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "GeoPointInput",
+	Description: "A location, in degrees.",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"lat": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Float), Description: "Latitude, from -90 to 90."},
+		"lng": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Float), Description: "Longitude, from -180 to 180."},
+	},
+})
+
+// BoundingBoxInput is the graphql input type of BoundingBox args.
+var BoundingBoxInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "BoundingBoxInput",
+	Description: "The area between two corners. A box whose south-west longitude is greater than its north-east longitude crosses the antimeridian.",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"southWest": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(GeoPointInput)},
+		"northEast": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(GeoPointInput)},
+	},
+})
+
+// GeoPointType is the graphql object type of GeoPoint results.
+var GeoPointType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "GeoPoint",
+	Description: "A location, in degrees.",
+	Fields: graphql.Fields{
+		"lat": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Float),
+			Description: "Latitude, from -90 to 90.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return geoPointSource(p.Source).Lat, nil
+			},
+		},
+		"lng": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Float),
+			Description: "Longitude, from -180 to 180.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return geoPointSource(p.Source).Lng, nil
+			},
+		},
+	},
+})
+
+// BoundingBoxType is the graphql object type of BoundingBox results.
+var BoundingBoxType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "BoundingBox",
+	Description: "The area between two corners.",
+	Fields: graphql.Fields{
+		"southWest": &graphql.Field{
+			Type: graphql.NewNonNull(GeoPointType),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return boundingBoxSource(p.Source).SouthWest, nil
+			},
+		},
+		"northEast": &graphql.Field{
+			Type: graphql.NewNonNull(GeoPointType),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return boundingBoxSource(p.Source).NorthEast, nil
+			},
+		},
+	},
+})
+
+func geoPointSource(source interface{}) GeoPoint {
+	if p, ok := source.(*GeoPoint); ok && p != nil {
+		return *p
+	}
+	p, _ := source.(GeoPoint)
+	return p
+}
+
+func boundingBoxSource(source interface{}) BoundingBox {
+	if b, ok := source.(*BoundingBox); ok && b != nil {
+		return *b
+	}
+	b, _ := source.(BoundingBox)
+	return b
+}
+
+// LoadGeoPoint loads a GeoPoint from a raw GeoPointInput value, and validates it.
+func LoadGeoPoint(i interface{}) (GeoPoint, error) {
+	m, ok := i.(map[string]interface{})
+	if !ok {
+		return GeoPoint{}, fmt.Errorf("%v is not a GeoPointInput", i)
+	}
+	var p GeoPoint
+	var err error
+	if p.Lat, err = geoCoordinate(m, "lat"); err != nil {
+		return GeoPoint{}, err
+	}
+	if p.Lng, err = geoCoordinate(m, "lng"); err != nil {
+		return GeoPoint{}, err
+	}
+	return p, p.Validate()
+}
+
+func geoCoordinate(m map[string]interface{}, key string) (float64, error) {
+	switch v := m[key].(type) {
+	case float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case nil:
+		return 0, fmt.Errorf("%s is required", key)
+	}
+	return 0, fmt.Errorf("%s: %v is not a float", key, m[key])
+}
+
+// LoadBoundingBox loads a BoundingBox from a raw BoundingBoxInput value, and validates it.
+func LoadBoundingBox(i interface{}) (BoundingBox, error) {
+	m, ok := i.(map[string]interface{})
+	if !ok {
+		return BoundingBox{}, fmt.Errorf("%v is not a BoundingBoxInput", i)
+	}
+	var b BoundingBox
+	var err error
+	if b.SouthWest, err = LoadGeoPoint(m["southWest"]); err != nil {
+		return BoundingBox{}, fmt.Errorf("southWest: %v", err)
+	}
+	if b.NorthEast, err = LoadGeoPoint(m["northEast"]); err != nil {
+		return BoundingBox{}, fmt.Errorf("northEast: %v", err)
+	}
+	return b, b.Validate()
+}
+
+// RegisterGeoTypes registers GeoPoint and BoundingBox on the loader: as args, with the
+// GeoPointInput and BoundingBoxInput input types and their loader funcs, and as results, with the
+// GeoPoint and BoundingBox objects.
+func (e *ArgLoader) RegisterGeoTypes() error {
+	if err := e.Register(LoadGeoPoint, GeoPointInput); err != nil {
+		return err
+	}
+	if err := e.Register(LoadBoundingBox, BoundingBoxInput); err != nil {
+		return err
+	}
+	if err := e.RegisterDumper(dumpGeoPoint); err != nil {
+		return err
+	}
+	if err := e.RegisterDumper(func(b BoundingBox) (interface{}, error) {
+		sw, _ := dumpGeoPoint(b.SouthWest)
+		ne, _ := dumpGeoPoint(b.NorthEast)
+		return map[string]interface{}{"southWest": sw, "northEast": ne}, nil
+	}); err != nil {
+		return err
+	}
+	e.typeNames[GeoPointType.Name()] = GeoPointType
+	e.typeNames[BoundingBoxType.Name()] = BoundingBoxType
+	if err := e.RegisterOutput(GeoPoint{}, GeoPointType); err != nil {
+		return err
+	}
+	return e.RegisterOutput(BoundingBox{}, BoundingBoxType)
+}
+
+// RegisterGeoTypes registers GeoPoint and BoundingBox on the default loader.
+func RegisterGeoTypes() error {
+	return Default().RegisterGeoTypes()
+}
+
+func dumpGeoPoint(p GeoPoint) (interface{}, error) {
+	return map[string]interface{}{"lat": p.Lat, "lng": p.Lng}, nil
+}
@@ -0,0 +1,79 @@
+// Package gormscope converts the pagination, sort, and filter args loaded by graphqlhelpers into
+// GORM scopes, for use with db.Scopes(...).
+package gormscope
+
+import (
+	"github.com/jinzhu/gorm"
+
+	graphqlhelpers "github.com/btubbs/graphql-go-helpers"
+	"github.com/btubbs/graphql-go-helpers/sqlfilter"
+	"github.com/btubbs/graphql-go-helpers/sqlselect"
+)
+
+// Page returns a scope applying the limit and offset from p.
+func Page(p graphqlhelpers.PageArgs) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if p.Limit > 0 {
+			db = db.Limit(p.Limit)
+		}
+		if p.Offset > 0 {
+			db = db.Offset(p.Offset)
+		}
+		return db
+	}
+}
+
+// Order returns a scope sorting by each of orders in turn.  columns maps sort field names to
+// columns, as in sqlfilter.Translator.
+func Order(columns map[string]string, orders ...graphqlhelpers.OrderBy) func(*gorm.DB) *gorm.DB {
+	t := sqlfilter.Translator{Columns: columns}
+	return func(db *gorm.DB) *gorm.DB {
+		for _, o := range orders {
+			col, err := t.Column(o.Field)
+			if err != nil {
+				db.AddError(err)
+				return db
+			}
+			if o.Desc() {
+				col += " DESC"
+			}
+			db = db.Order(col)
+		}
+		return db
+	}
+}
+
+// Where returns a scope restricting results to those matching f.  columns maps filter field names
+// to columns, as in sqlfilter.Translator.  Translation errors are added to the returned DB.
+func Where(f graphqlhelpers.Filter, columns map[string]string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		clause, args, err := sqlfilter.Translator{Columns: columns}.Where(f)
+		if err != nil {
+			db.AddError(err)
+			return db
+		}
+		if clause == "" {
+			return db
+		}
+		return db.Where(clause, args...)
+	}
+}
+
+// Select returns a scope selecting only the columns, and adding only the joins, needed to resolve
+// fields, as mapped by tables.  Mapping errors are added to the returned DB.
+func Select(fields graphqlhelpers.FieldSet, tables sqlselect.Mapper) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		sel, err := tables.Select(fields)
+		if err != nil {
+			db.AddError(err)
+			return db
+		}
+		for _, join := range sel.Joins {
+			db = db.Joins(join)
+		}
+		if len(sel.Columns) == 0 {
+			return db
+		}
+		return db.Select(sel.Columns)
+	}
+}
@@ -0,0 +1,333 @@
+package graphqlhelpers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+const (
+	// DefaultMaxBodySize is the largest request body a handler accepts, unless set with
+	// WithMaxBodySize.
+	DefaultMaxBodySize = 1 << 20
+	// DefaultMaxBatchSize is the most operations a handler runs for one batched request, unless set
+	// with WithMaxBatchSize.
+	DefaultMaxBatchSize = 10
+)
+
+// handler serves GraphQL over HTTP.  See NewHandler.
+type handler struct {
+	schema       graphql.Schema
+	rules        []QueryRule
+	maxBodySize  int64
+	maxBatchSize int
+	formatError  ErrorFormatter
+	tracing      bool
+	context      func(r *http.Request) context.Context
+	rootObject   func(r *http.Request) map[string]interface{}
+	persisted    PersistedQueryStore
+	playground   string
+
+	// starts N+1 detection for an operation, returning the func that reports on it, or nil.
+	nPlusOne func(ctx context.Context) (context.Context, func())
+
+	// decides who may introspect the schema, or nil to let everyone.
+	introspection func(ctx context.Context) bool
+	// the coordinates of the types and fields hidden from introspection.
+	hidden map[string]bool
+}
+
+// HandlerOption configures a handler built by NewHandler.
+type HandlerOption func(*handler)
+
+// WithRules checks every operation against rules, like DepthLimit, CostLimit or a Safelist's Rule,
+// before executing it.
+func WithRules(rules ...QueryRule) HandlerOption {
+	return func(h *handler) {
+		h.rules = append(h.rules, rules...)
+	}
+}
+
+// WithMaxBodySize sets the largest request body the handler accepts, in bytes.
+func WithMaxBodySize(n int64) HandlerOption {
+	return func(h *handler) {
+		h.maxBodySize = n
+	}
+}
+
+// WithMaxBatchSize sets the most operations the handler runs for one batched request.  Zero turns
+// batching off.
+func WithMaxBatchSize(n int) HandlerOption {
+	return func(h *handler) {
+		h.maxBatchSize = n
+	}
+}
+
+// WithErrorFormatter formats the errors in every response with format instead of
+// DefaultErrorFormatter.  format can call DefaultErrorFormatter itself to keep its codes.
+func WithErrorFormatter(format ErrorFormatter) HandlerOption {
+	return func(h *handler) {
+		h.formatError = format
+	}
+}
+
+// WithTracing adds the Apollo tracing extension to every response.  Resolvers are only timed if
+// they're wrapped in ApolloTracing middleware.
+func WithTracing() HandlerOption {
+	return func(h *handler) {
+		h.tracing = true
+	}
+}
+
+// WithContext sets the func building the context that operations run with, from the request.  It
+// defaults to the request's context.  This is the place to set up per-request state, like data
+// loaders:
+//
+//	WithContext(func(r *http.Request) context.Context {
+//		return WithDataLoaders(r.Context(), batches)
+//	})
+func WithContext(fn func(r *http.Request) context.Context) HandlerOption {
+	return func(h *handler) {
+		h.context = fn
+	}
+}
+
+// WithRootObject sets the func building the root object that operations run with, from the request.
+func WithRootObject(fn func(r *http.Request) map[string]interface{}) HandlerOption {
+	return func(h *handler) {
+		h.rootObject = fn
+	}
+}
+
+// WithPersistedQueries serves automatic persisted queries from store, as PersistedQueryMiddleware
+// does.
+func WithPersistedQueries(store PersistedQueryStore) HandlerOption {
+	return func(h *handler) {
+		h.persisted = store
+	}
+}
+
+// NewHandler returns an HTTP handler serving schema.  It accepts
+//
+//   - GET requests with query, variables and operationName URL params, for queries only,
+//   - POST requests with a JSON body holding query, variables and operationName, or a JSON array of
+//     them to run a batch, and
+//   - POST requests with an application/graphql body holding just the query.
+//
+// Responses are JSON, with errors formatted by DefaultErrorFormatter (or WithErrorFormatter), so
+// clients get codes for the errors of this package's features.  The other options hook in the
+// query rules, tracing and persisted queries.
+func NewHandler(schema graphql.Schema, opts ...HandlerOption) http.Handler {
+	h := &handler{
+		schema:       schema,
+		maxBodySize:  DefaultMaxBodySize,
+		maxBatchSize: DefaultMaxBatchSize,
+		formatError:  DefaultErrorFormatter,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if h.persisted != nil {
+		return h.limitBody(PersistedQueryMiddleware(h.persisted, h))
+	}
+	return h
+}
+
+// limitBody reads POST bodies of up to the handler's maxBodySize before next, which would read
+// them without a limit, and rejects larger ones.
+func (h *handler) limitBody(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, h.maxBodySize))
+			if err != nil {
+				writeJSON(w, http.StatusRequestEntityTooLarge, errorResponse(fmt.Sprintf("the request body is larger than %d bytes", h.maxBodySize)))
+				return
+			}
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handlerRequest is one operation sent to a handler.
+type handlerRequest struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+	OperationName string                 `json:"operationName"`
+}
+
+// requestError is a request that can't be run, answered with status.
+type requestError struct {
+	status int
+	msg    string
+}
+
+func (e *requestError) Error() string {
+	return e.msg
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.servesPlayground(r) {
+		h.servePlayground(w)
+		return
+	}
+	var (
+		reqs  []handlerRequest
+		batch bool
+		err   error
+	)
+	switch r.Method {
+	case http.MethodGet:
+		var req handlerRequest
+		req, err = getRequest(r)
+		reqs = []handlerRequest{req}
+	case http.MethodPost:
+		reqs, batch, err = h.postRequests(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		err = &requestError{http.StatusMethodNotAllowed, "GraphQL requests must be GET or POST"}
+	}
+	if err != nil {
+		status := http.StatusBadRequest
+		if reqErr, ok := err.(*requestError); ok {
+			status = reqErr.status
+		}
+		writeJSON(w, status, errorResponse(err.Error()))
+		return
+	}
+
+	ctx := r.Context()
+	if h.context != nil {
+		ctx = h.context(r)
+	}
+	var root map[string]interface{}
+	if h.rootObject != nil {
+		root = h.rootObject(r)
+	}
+	responses := make([]*Response, len(reqs))
+	for i, req := range reqs {
+		responses[i] = h.execute(ctx, root, req)
+	}
+	if batch {
+		writeJSON(w, http.StatusOK, responses)
+		return
+	}
+	writeJSON(w, http.StatusOK, responses[0])
+}
+
+// execute runs one operation.
+func (h *handler) execute(ctx context.Context, root map[string]interface{}, req handlerRequest) *Response {
+	if h.nPlusOne != nil {
+		var done func()
+		ctx, done = h.nPlusOne(ctx)
+		defer done()
+	}
+	ctx, log := withErrorLog(ctx)
+	ctx = WithRequestVariables(ctx, req.Variables)
+	params := graphql.Params{
+		Schema:         h.schema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		RootObject:     root,
+		Context:        ctx,
+	}
+	rules := h.rules
+	if h.introspection != nil && !h.introspection(ctx) {
+		rules = append([]QueryRule{NoIntrospection()}, rules...)
+	}
+	var res *graphql.Result
+	var tracing TracingExtension
+	if h.tracing {
+		res, tracing = executeTraced(params, rules...)
+	} else {
+		res = Execute(params, rules...)
+	}
+	if len(h.hidden) > 0 && introspectionRequested(req.Query) {
+		doc, err := parser.Parse(parser.ParseParams{
+			Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"}),
+		})
+		if err == nil {
+			hideIntrospected(res.Data, doc, req.OperationName, req.Variables, h.hidden)
+		}
+	}
+	resp := newResponse(ctx, res, log, h.formatError)
+	if h.tracing {
+		resp.Extensions = map[string]interface{}{"tracing": tracing}
+	}
+	return resp
+}
+
+// getRequest reads the operation of a GET request, which mustn't be a mutation.
+func getRequest(r *http.Request) (handlerRequest, error) {
+	params := r.URL.Query()
+	req := handlerRequest{Query: params.Get("query"), OperationName: params.Get("operationName")}
+	if req.Query == "" {
+		return req, fmt.Errorf("the query param is required")
+	}
+	if vars := params.Get("variables"); vars != "" {
+		if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+			return req, fmt.Errorf("the variables param is not a JSON object")
+		}
+	}
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		// left for execution to report.
+		return req, nil
+	}
+	if op, err := operation(doc, req.OperationName); err == nil && op.Operation != ast.OperationTypeQuery {
+		return req, &requestError{http.StatusMethodNotAllowed, fmt.Sprintf("a %s can only be sent with POST", op.Operation)}
+	}
+	return req, nil
+}
+
+// postRequests reads the operations of a POST request, and whether they were sent as a batch.
+func (h *handler) postRequests(w http.ResponseWriter, r *http.Request) ([]handlerRequest, bool, error) {
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, h.maxBodySize))
+	if err != nil {
+		return nil, false, &requestError{http.StatusRequestEntityTooLarge, fmt.Sprintf("the request body is larger than %d bytes", h.maxBodySize)}
+	}
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "application/graphql" {
+		return []handlerRequest{{Query: string(body)}}, false, nil
+	}
+
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		if h.maxBatchSize == 0 {
+			return nil, false, fmt.Errorf("batched requests are not supported")
+		}
+		var reqs []handlerRequest
+		if err := json.Unmarshal(body, &reqs); err != nil {
+			return nil, false, fmt.Errorf("the request body is not a JSON array of GraphQL requests")
+		}
+		if len(reqs) == 0 {
+			return nil, false, fmt.Errorf("the batch is empty")
+		}
+		if len(reqs) > h.maxBatchSize {
+			return nil, false, fmt.Errorf("the batch has %d operations, more than the maximum of %d", len(reqs), h.maxBatchSize)
+		}
+		return reqs, true, nil
+	}
+	var req handlerRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, false, fmt.Errorf("the request body is not a JSON GraphQL request")
+	}
+	return []handlerRequest{req}, false, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
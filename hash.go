@@ -0,0 +1,88 @@
+package graphqlhelpers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+)
+
+// HashArgs returns a stable hash of a loaded args struct (or pointer to one): args structs holding
+// equal values always hash the same, across processes.  It's suitable for correlating log lines.
+//
+// The hash is taken over RedactedArgs, so args tagged `redact:"true"` don't affect it, and can't be
+// recovered from it by guessing values.  Cache and dedup keys, which must tell apart args differing
+// only in redacted values, should come from KeyedHashArgs instead.
+func (e *ArgLoader) HashArgs(i interface{}) (string, error) {
+	dumped, err := e.RedactedArgs(i)
+	if err != nil {
+		return "", err
+	}
+	return hashDump(sha256.New(), dumped)
+}
+
+// KeyedHashArgs returns a stable HMAC-SHA256, with key, of a loaded args struct (or pointer to one),
+// for cache and dedup keys: args structs holding equal values always hash the same for the same key,
+// across processes.  Unlike HashArgs, the hash is taken over every arg, redacted ones included, but
+// without the key they can't be recovered from it by guessing values.  Keep the key secret, and
+// share it between the processes whose hashes must match.
+func (e *ArgLoader) KeyedHashArgs(key []byte, i interface{}) (string, error) {
+	dumped, err := e.DumpArgs(i)
+	if err != nil {
+		return "", err
+	}
+	return hashDump(hmac.New(sha256.New, key), dumped)
+}
+
+// hashDump returns the hash of dumped args, taken with h.
+func hashDump(h hash.Hash, dumped map[string]interface{}) (string, error) {
+	// map keys are sorted when marshaled, so equal args give equal bytes.
+	b, err := json.Marshal(dumped)
+	if err != nil {
+		return "", err
+	}
+	h.Write(b)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// SetHashKey sets the secret key that cached results are keyed with, as with KeyedHashArgs.  By
+// default each loader makes a random key of its own, which is enough for its in-memory cache; set
+// the same key on every process sharing a ResultCache, so that they find each other's results.
+func (e *ArgLoader) SetHashKey(key []byte) {
+	e.hashKey = key
+}
+
+// SetHashKey sets the secret key that the default loader's cached results are keyed with.
+func SetHashKey(key []byte) {
+	Default().SetHashKey(key)
+}
+
+// resultHashKey returns the key set with SetHashKey, making a random one if none was set.
+func (e *ArgLoader) resultHashKey() ([]byte, error) {
+	var err error
+	e.hashKeyOnce.Do(func() {
+		if e.hashKey == nil {
+			key := make([]byte, sha256.Size)
+			if _, err = rand.Read(key); err == nil {
+				e.hashKey = key
+			}
+		}
+	})
+	if e.hashKey == nil {
+		return nil, fmt.Errorf("cannot make a hash key: %v", err)
+	}
+	return e.hashKey, nil
+}
+
+// HashArgs returns a stable hash of a loaded args struct, using the default loader.
+func HashArgs(i interface{}) (string, error) {
+	return Default().HashArgs(i)
+}
+
+// KeyedHashArgs returns a stable HMAC of a loaded args struct with key, using the default loader.
+func KeyedHashArgs(key []byte, i interface{}) (string, error) {
+	return Default().KeyedHashArgs(key, i)
+}
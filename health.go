@@ -0,0 +1,102 @@
+//go:build go1.18
+
+package graphqlhelpers
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+)
+
+// HealthCheck is a named check run by the health field.  Check returns an error if the thing it
+// checks, like a database connection, isn't working.
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// Health is the result of the health field.  Status is "pass" if every check passed, and "fail"
+// otherwise.
+type Health struct {
+	Status string              `field:"status"`
+	Checks []HealthCheckResult `field:"checks"`
+}
+
+// HealthCheckResult is the result of one HealthCheck.
+type HealthCheckResult struct {
+	Name   string  `field:"name"`
+	Status string  `field:"status"`
+	Error  *string `field:"error"`
+}
+
+// Version is the result of the version field, read from the build info Go embeds in binaries.
+// Revision, Time and Modified come from the version control system, and are empty for binaries
+// built without it.
+type Version struct {
+	Path      string `field:"path" desc:"the main module's path"`
+	Version   string `field:"version" desc:"the main module's version, or (devel)"`
+	Revision  string `field:"revision"`
+	Time      string `field:"time" desc:"the commit time of the revision, in RFC 3339 format"`
+	Modified  bool   `field:"modified" desc:"whether the working tree had uncommitted changes"`
+	GoVersion string `field:"goVersion"`
+}
+
+// AddHealthAndVersion adds health and version fields to the root query type, so that every service
+// can be checked the same way:
+//
+//	query { health { status checks { name status error } } version { version revision } }
+//
+// checks are run concurrently for every query of health.  The generated objects are named Health,
+// HealthCheckResult and Version.
+func (b *SchemaBuilder) AddHealthAndVersion(checks ...HealthCheck) *SchemaBuilder {
+	b.QueryField("health", func(ctx context.Context) (Health, error) {
+		return runHealthChecks(ctx, checks), nil
+	}, WithDescription("Reports whether the service and its dependencies are working."))
+	b.QueryField("version", func(ctx context.Context) (Version, error) {
+		return buildVersion(), nil
+	}, WithDescription("Reports the build of the service."))
+	return b
+}
+
+func runHealthChecks(ctx context.Context, checks []HealthCheck) Health {
+	health := Health{Status: "pass", Checks: make([]HealthCheckResult, len(checks))}
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check HealthCheck) {
+			defer wg.Done()
+			result := HealthCheckResult{Name: check.Name, Status: "pass"}
+			if err := check.Check(ctx); err != nil {
+				msg := err.Error()
+				result.Status, result.Error = "fail", &msg
+			}
+			health.Checks[i] = result
+		}(i, check)
+	}
+	wg.Wait()
+	for _, result := range health.Checks {
+		if result.Status != "pass" {
+			health.Status = "fail"
+		}
+	}
+	return health
+}
+
+func buildVersion() Version {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return Version{}
+	}
+	v := Version{Path: info.Main.Path, Version: info.Main.Version, GoVersion: info.GoVersion}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			v.Revision = setting.Value
+		case "vcs.time":
+			v.Time = setting.Value
+		case "vcs.modified":
+			v.Modified = setting.Value == "true"
+		}
+	}
+	return v
+}
@@ -0,0 +1,96 @@
+package graphqlhelpers
+
+import (
+	"reflect"
+
+	"github.com/graphql-go/graphql"
+)
+
+// LoadHook is called when LoadArgs starts loading args into target, and returns a func that is
+// called with the result once loading is done.  Hooks let tracing and metrics integrations observe
+// argument loading without wrapping every call.
+type LoadHook func(p graphql.ResolveParams, target interface{}) (done func(err error))
+
+// OnLoad installs a hook called around every LoadArgs call, including the ones made by resolvers
+// built with FieldFromFunc.
+func (e *ArgLoader) OnLoad(hook LoadHook) {
+	e.loadHooks = append(e.loadHooks, hook)
+}
+
+// OnLoad installs a hook called around every LoadArgs call on the default loader.
+func OnLoad(hook LoadHook) {
+	Default().OnLoad(hook)
+}
+
+// startLoad calls the load hooks, returning a func that finishes them in reverse order.
+func (e *ArgLoader) startLoad(p graphql.ResolveParams, target interface{}) func(err error) {
+	if len(e.loadHooks) == 0 {
+		return func(error) {}
+	}
+	dones := make([]func(error), len(e.loadHooks))
+	for i, hook := range e.loadHooks {
+		dones[i] = hook(p, target)
+	}
+	return func(err error) {
+		for i := len(dones) - 1; i >= 0; i-- {
+			if dones[i] != nil {
+				dones[i](err)
+			}
+		}
+	}
+}
+
+// ArgumentConfigHook adjusts the config generated for an arg field, such as by changing its
+// description, default value or type.
+type ArgumentConfigHook func(field reflect.StructField, cfg *graphql.ArgumentConfig)
+
+type argConfigHook struct {
+	hook ArgumentConfigHook
+	// the structs whose fields the hook applies to, or nil for all of them.
+	structs map[reflect.Type]bool
+}
+
+// OnArgumentConfig installs a hook called with each arg config that ArgsConfig generates, after the
+// field's tags have been applied.  Fields of generated input objects are passed to the hook too, and
+// the Type, Description and DefaultValue it sets are copied to the input object field.  If structs
+// are given, the hook only sees the fields of those structs (or pointers to them).
+//
+// Input objects are generated once, so install hooks before configuring any args.
+func (e *ArgLoader) OnArgumentConfig(hook ArgumentConfigHook, structs ...interface{}) {
+	h := argConfigHook{hook: hook}
+	if len(structs) > 0 {
+		h.structs = map[reflect.Type]bool{}
+		for _, s := range structs {
+			t := reflect.TypeOf(s)
+			for t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			h.structs[t] = true
+		}
+	}
+	e.argConfigHooks = append(e.argConfigHooks, h)
+}
+
+// OnArgumentConfig installs an arg config hook on the default loader.
+func OnArgumentConfig(hook ArgumentConfigHook, structs ...interface{}) {
+	Default().OnArgumentConfig(hook, structs...)
+}
+
+// configureArg calls the arg config hooks that apply to field of parent.
+func (e *ArgLoader) configureArg(parent reflect.Type, field reflect.StructField, cfg *graphql.ArgumentConfig) {
+	for _, h := range e.argConfigHooks {
+		if h.structs == nil || h.structs[parent] {
+			h.hook(field, cfg)
+		}
+	}
+}
+
+// configureInputField calls the arg config hooks that apply to field of parent, an input object.
+func (e *ArgLoader) configureInputField(parent reflect.Type, field reflect.StructField, cfg *graphql.InputObjectFieldConfig) {
+	if len(e.argConfigHooks) == 0 {
+		return
+	}
+	arg := &graphql.ArgumentConfig{Type: cfg.Type, Description: cfg.Description, DefaultValue: cfg.DefaultValue}
+	e.configureArg(parent, field, arg)
+	cfg.Type, cfg.Description, cfg.DefaultValue = arg.Type, arg.Description, arg.DefaultValue
+}
@@ -0,0 +1,26 @@
+// Package htmlsanitize registers bluemonday policies as graphqlhelpers sanitizers, so that args
+// holding user-generated HTML can be cleaned with a sanitize tag before they reach resolvers:
+//
+//	err := htmlsanitize.Register(loader)
+//
+//	type PostCommentArgs struct {
+//		Body string `arg:"body" sanitize:"html"`
+//	}
+package htmlsanitize
+
+import (
+	"github.com/microcosm-cc/bluemonday"
+
+	graphqlhelpers "github.com/btubbs/graphql-go-helpers"
+)
+
+// Register registers two sanitizers on loader: "html", with bluemonday's UGCPolicy, which keeps the
+// tags and attributes that are safe in user-generated content, and "text", with its StrictPolicy,
+// which removes every tag.  Other policies can be registered directly, since a *bluemonday.Policy
+// is a graphqlhelpers.Sanitizer.
+func Register(loader *graphqlhelpers.ArgLoader) error {
+	if err := loader.RegisterSanitizer("html", bluemonday.UGCPolicy()); err != nil {
+		return err
+	}
+	return loader.RegisterSanitizer("text", bluemonday.StrictPolicy())
+}
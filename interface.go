@@ -0,0 +1,219 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/graphql-go/graphql"
+)
+
+// interfaceDef is a registered graphql interface and the rule for which generated objects
+// implement it.
+type interfaceDef struct {
+	gqlType *graphql.Interface
+
+	// objects embedding this struct type implement the interface.  Nil for interfaces registered
+	// with RegisterInterfaceFor.
+	embedded reflect.Type
+
+	// objects whose struct type (or a pointer to it) implements this Go interface implement the
+	// graphql interface.  Nil for interfaces registered with RegisterInterface.
+	goIface reflect.Type
+
+	// objects generated for exactly these struct types implement the interface.  Nil unless the
+	// interface is implemented only by explicitly registered types, like Node.
+	types map[reflect.Type]bool
+
+	// fields returns generated fields that replace an implementing object's own, like Node's
+	// global id.  May be nil.
+	fields func(t reflect.Type) graphql.Fields
+
+	// the objects found to implement the interface so far, for the type resolver.
+	objects map[reflect.Type]*graphql.Object
+}
+
+// RegisterInterface declares a graphql interface whose fields are the field-tagged fields of the
+// struct type of fields.  Every generated object whose struct embeds that struct (directly or
+// through another embedded struct) implements the interface, and the interface resolves values to
+// those objects by Go type.
+//
+//	type NodeFields struct {
+//		ID string `field:"id"`
+//	}
+//
+//	type User struct {
+//		NodeFields
+//		Name string `field:"name"`
+//	}
+//
+//	node, err := RegisterInterface("Node", NodeFields{})
+//
+// Only generated objects are attached, so an implementation that no field returns directly must
+// still be generated with OutputType, and added to the schema's Types if the schema isn't built with
+// a SchemaBuilder.
+func (e *ArgLoader) RegisterInterface(name string, fields interface{}) (*graphql.Interface, error) {
+	t := reflect.TypeOf(fields)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot build interface %s from %v: fields must be a struct", name, reflect.TypeOf(fields))
+	}
+	fieldsConf, err := e.objectFields(t)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build interface %s: %v", name, err)
+	}
+	if err := e.setOutputCosts(name, t); err != nil {
+		return nil, err
+	}
+	return e.registerInterface(name, fieldsConf, &interfaceDef{embedded: t})
+}
+
+// RegisterInterfaceFor declares a graphql interface whose fields are the field-tagged fields of the
+// struct type of fields, implemented by every generated object whose struct type (or a pointer to
+// it) implements the Go interface that iface points to.  The graphql interface is also registered
+// as the output type of the Go interface, so resolvers can declare that they return it.
+//
+//	type Node interface{ NodeID() string }
+//
+//	node, err := RegisterInterfaceFor((*Node)(nil), "Node", NodeFields{})
+func (e *ArgLoader) RegisterInterfaceFor(iface interface{}, name string, fields interface{}) (*graphql.Interface, error) {
+	goIface, err := interfaceType(iface)
+	if err != nil {
+		return nil, err
+	}
+	if _, alreadyRegistered := e.outputTypes[goIface]; alreadyRegistered {
+		return nil, fmt.Errorf("an output type has already been registered for the %v type", goIface)
+	}
+	t := reflect.TypeOf(fields)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot build interface %s from %v: fields must be a struct", name, reflect.TypeOf(fields))
+	}
+	fieldsConf, err := e.objectFields(t)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build interface %s: %v", name, err)
+	}
+	if err := e.setOutputCosts(name, t); err != nil {
+		return nil, err
+	}
+	gqlType, err := e.registerInterface(name, fieldsConf, &interfaceDef{goIface: goIface})
+	if err != nil {
+		return nil, err
+	}
+	e.outputTypes[goIface] = gqlType
+	return gqlType, nil
+}
+
+func (e *ArgLoader) registerInterface(name string, fields graphql.Fields, def *interfaceDef) (*graphql.Interface, error) {
+	if _, taken := e.typeNames[name]; taken {
+		return nil, fmt.Errorf("cannot build interface %s: the name is already used by %s", name, e.typeDescription(name))
+	}
+	def.objects = map[reflect.Type]*graphql.Object{}
+	def.gqlType = graphql.NewInterface(graphql.InterfaceConfig{
+		Name:        name,
+		Fields:      fields,
+		ResolveType: typeResolver(def.objects),
+	})
+	e.interfaces = append(e.interfaces, def)
+	e.typeNames[name] = def.gqlType
+	return def.gqlType, nil
+}
+
+// interfacesOf returns the registered interfaces implemented by obj, the object generated for t,
+// and records obj with each of them for type resolution.  It runs when graphql first asks for the
+// object's interfaces, so interfaces registered after the object was generated still apply.
+func (e *ArgLoader) interfacesOf(t reflect.Type, obj *graphql.Object) []*graphql.Interface {
+	var out []*graphql.Interface
+	for _, def := range e.interfaces {
+		if def.implementedBy(t) {
+			def.objects[t] = obj
+			out = append(out, def.gqlType)
+		}
+	}
+	return out
+}
+
+// interfaceFields returns generated, the fields of the object for t, with any fields replaced by
+// the interfaces t implements.
+func (e *ArgLoader) interfaceFields(t reflect.Type, generated graphql.Fields) graphql.Fields {
+	var out graphql.Fields
+	for _, def := range e.interfaces {
+		if def.fields == nil || !def.implementedBy(t) {
+			continue
+		}
+		if out == nil {
+			out = graphql.Fields{}
+			for name, f := range generated {
+				out[name] = f
+			}
+		}
+		for name, f := range def.fields(t) {
+			out[name] = f
+		}
+	}
+	if out == nil {
+		return generated
+	}
+	return out
+}
+
+func (def *interfaceDef) implementedBy(t reflect.Type) bool {
+	if def.types != nil {
+		return def.types[t]
+	}
+	if def.goIface != nil {
+		return implements(t, def.goIface)
+	}
+	return embeds(t, def.embedded)
+}
+
+// embeds reports whether struct type t embeds the struct type embedded, or a pointer to it, either
+// directly or through other embedded structs.
+func embeds(t, embedded reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.Anonymous {
+			continue
+		}
+		ft := field.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft == embedded || (ft.Kind() == reflect.Struct && embeds(ft, embedded)) {
+			return true
+		}
+	}
+	return false
+}
+
+// implementations returns the generated objects that implement a registered interface, so that
+// they can be added to a schema even if no field returns them directly.
+func (e *ArgLoader) implementations() []graphql.Type {
+	var out []graphql.Type
+	for t, obj := range e.objects {
+		for _, def := range e.interfaces {
+			if def.implementedBy(t) {
+				out = append(out, obj)
+				break
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name() < out[j].Name() })
+	return out
+}
+
+// RegisterInterface declares a graphql interface implemented by objects embedding fields' struct
+// type, using the default loader.
+func RegisterInterface(name string, fields interface{}) (*graphql.Interface, error) {
+	return Default().RegisterInterface(name, fields)
+}
+
+// RegisterInterfaceFor declares a graphql interface implemented by objects satisfying a Go
+// interface, using the default loader.
+func RegisterInterfaceFor(iface interface{}, name string, fields interface{}) (*graphql.Interface, error) {
+	return Default().RegisterInterfaceFor(iface, name, fields)
+}
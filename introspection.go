@@ -0,0 +1,353 @@
+package graphqlhelpers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// introspectionTag hides an output struct field from introspection, with `introspection:"-"`.  On
+// a blank field it hides the whole object.
+const introspectionTag = "introspection"
+
+// NoIntrospection returns a rule for Execute that rejects operations selecting __schema or __type,
+// so clients can't read the schema.  __typename is still allowed.
+func NoIntrospection() QueryRule {
+	return func(schema graphql.Schema, doc *ast.Document, operationName string, vars map[string]interface{}) error {
+		op, err := operation(doc, operationName)
+		if err != nil {
+			return err
+		}
+		if introspects(op.SelectionSet, fragments(doc), map[string]bool{}) {
+			return fmt.Errorf("introspection is disabled")
+		}
+		return nil
+	}
+}
+
+// introspects reports whether set selects __schema or __type, directly or through fragments.
+func introspects(set *ast.SelectionSet, frags map[string]*ast.FragmentDefinition, visited map[string]bool) bool {
+	if set == nil {
+		return false
+	}
+	for _, sel := range set.Selections {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			if name := sel.Name.Value; name == "__schema" || name == "__type" {
+				return true
+			}
+			if introspects(sel.SelectionSet, frags, visited) {
+				return true
+			}
+		case *ast.InlineFragment:
+			if introspects(sel.SelectionSet, frags, visited) {
+				return true
+			}
+		case *ast.FragmentSpread:
+			name := sel.Name.Value
+			if frag, ok := frags[name]; ok && !visited[name] {
+				visited[name] = true
+				if introspects(frag.SelectionSet, frags, visited) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// WithoutIntrospection rejects every operation that introspects the schema, as NoIntrospection
+// does.  Use it for production deployments that shouldn't publish their schema.
+func WithoutIntrospection() HandlerOption {
+	return WithIntrospectionFor(func(ctx context.Context) bool { return false })
+}
+
+// WithIntrospectionFor only lets a request introspect the schema if allow returns true for its
+// context (as built by WithContext), for example for signed-in staff.
+func WithIntrospectionFor(allow func(ctx context.Context) bool) HandlerOption {
+	return func(h *handler) {
+		h.introspection = allow
+	}
+}
+
+// WithHidden hides types and fields from introspection, without removing them from the schema.
+// Each coordinate names a type, like "AuditLog", or a field, like "User.internalNotes".  Hidden
+// types are left out of type lists, and __type and the other fields returning one type, like the
+// type of a field or arg, ofType and queryType, return null for them; hidden fields are left out of
+// their type's fields.  Pass ArgLoader.Hidden to hide the types and fields tagged
+// `introspection:"-"`.  A type or field can only be recognized by its name, so while anything is
+// hidden, types and fields whose names aren't selected are left out too, as are the fields of types
+// whose names aren't known.
+//
+// Hiding only keeps clients from discovering a type or field; anyone who knows its name can still
+// query it, so also protect sensitive fields with the visibility tag or role and perm tags.
+func WithHidden(coordinates ...string) HandlerOption {
+	return func(h *handler) {
+		if h.hidden == nil {
+			h.hidden = map[string]bool{}
+		}
+		for _, c := range coordinates {
+			h.hidden[c] = true
+		}
+	}
+}
+
+// HideFromIntrospection adds types and fields, named by coordinates as with WithHidden, to the ones
+// returned by Hidden.
+func (e *ArgLoader) HideFromIntrospection(coordinates ...string) {
+	e.hidden = append(e.hidden, coordinates...)
+}
+
+// HideFromIntrospection adds types and fields to the ones returned by Hidden on the default loader.
+func HideFromIntrospection(coordinates ...string) {
+	Default().HideFromIntrospection(coordinates...)
+}
+
+// Hidden returns the coordinates of the types and fields to hide from introspection: the generated
+// objects and output fields tagged `introspection:"-"` (on a blank field, for an object), and the
+// ones added with HideFromIntrospection.
+func (e *ArgLoader) Hidden() []string {
+	return append([]string{}, e.hidden...)
+}
+
+// Hidden returns the coordinates of the types and fields the default loader hides from
+// introspection.
+func Hidden() []string {
+	return Default().Hidden()
+}
+
+// setHidden records the introspection tags on struct type t, generated as the object named
+// typeName.
+func (e *ArgLoader) setHidden(typeName string, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.Name == "_" && field.Tag.Get(introspectionTag) == "-" {
+			e.hidden = append(e.hidden, typeName)
+		}
+	}
+	for _, f := range outputFields(t) {
+		if f.field.Tag.Get(introspectionTag) == "-" {
+			e.hidden = append(e.hidden, typeName+"."+f.name)
+		}
+	}
+}
+
+// introspectionTypes maps the fields of the introspection types to the introspection types they
+// return, so that introspection results can be walked.
+var introspectionTypes = map[string]string{
+	"__schema":                  "__Schema",
+	"__type":                    "__Type",
+	"__Schema.types":            "__Type",
+	"__Schema.queryType":        "__Type",
+	"__Schema.mutationType":     "__Type",
+	"__Schema.subscriptionType": "__Type",
+	"__Schema.directives":       "__Directive",
+	"__Type.fields":             "__Field",
+	"__Type.interfaces":         "__Type",
+	"__Type.possibleTypes":      "__Type",
+	"__Type.enumValues":         "__EnumValue",
+	"__Type.inputFields":        "__InputValue",
+	"__Type.ofType":             "__Type",
+	"__Field.args":              "__InputValue",
+	"__Field.type":              "__Type",
+	"__InputValue.type":         "__Type",
+	"__Directive.args":          "__InputValue",
+}
+
+// hideIntrospected removes the hidden types and fields from introspection results in data, the
+// result of executing the operation of doc named operationName with vars.
+func hideIntrospected(data interface{}, doc *ast.Document, operationName string, vars map[string]interface{}, hidden map[string]bool) {
+	op, err := operation(doc, operationName)
+	if err != nil {
+		return
+	}
+	h := &introspectionFilter{fragments: fragments(doc), vars: vars, hidden: hidden}
+	root, _ := data.(map[string]interface{})
+	for key, field := range h.fields(op.SelectionSet) {
+		if child, ok := introspectionTypes[field.Name.Value]; ok && root != nil {
+			// __type is named by its arg whether or not its name is selected.
+			hidden := h.hidden[h.typeArg(field)]
+			if h.typeArg(field) == "" {
+				hidden = h.hiddenType(root[key], field)
+			}
+			if child == "__Type" && hidden {
+				root[key] = nil
+				continue
+			}
+			h.walk(root[key], child, field)
+		}
+	}
+}
+
+type introspectionFilter struct {
+	fragments map[string]*ast.FragmentDefinition
+	vars      map[string]interface{}
+	hidden    map[string]bool
+}
+
+// fields returns the fields selected by set, by response key, looking through fragments.  Fields
+// selected more than once are merged.
+func (h *introspectionFilter) fields(set *ast.SelectionSet) map[string]*ast.Field {
+	out := map[string]*ast.Field{}
+	h.collect(set, out, map[string]bool{})
+	return out
+}
+
+func (h *introspectionFilter) collect(set *ast.SelectionSet, out map[string]*ast.Field, visited map[string]bool) {
+	if set == nil {
+		return
+	}
+	for _, sel := range set.Selections {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			key := sel.Name.Value
+			if sel.Alias != nil {
+				key = sel.Alias.Value
+			}
+			if prev, ok := out[key]; ok && prev.SelectionSet != nil && sel.SelectionSet != nil {
+				merged := *prev
+				merged.SelectionSet = &ast.SelectionSet{
+					Selections: append(append([]ast.Selection{}, prev.SelectionSet.Selections...), sel.SelectionSet.Selections...),
+				}
+				out[key] = &merged
+				continue
+			}
+			out[key] = sel
+		case *ast.InlineFragment:
+			h.collect(sel.SelectionSet, out, visited)
+		case *ast.FragmentSpread:
+			name := sel.Name.Value
+			if frag, ok := h.fragments[name]; ok && !visited[name] {
+				visited[name] = true
+				h.collect(frag.SelectionSet, out, visited)
+			}
+		}
+	}
+}
+
+// walk filters value, the result of field, of introspection type typeName (or a list of them).
+func (h *introspectionFilter) walk(value interface{}, typeName string, field *ast.Field) {
+	switch value := value.(type) {
+	case []interface{}:
+		for _, item := range value {
+			h.walk(item, typeName, field)
+		}
+	case map[string]interface{}:
+		selected := h.fields(field.SelectionSet)
+		for key, child := range selected {
+			childType, ok := introspectionTypes[typeName+"."+child.Name.Value]
+			if !ok {
+				continue
+			}
+			switch {
+			case childType == "__Type":
+				if list, isList := value[key].([]interface{}); isList {
+					value[key] = h.visibleTypes(list, child)
+				} else if h.hiddenType(value[key], child) {
+					value[key] = nil
+					continue
+				}
+			case typeName == "__Type" && childType == "__Field":
+				if list, isList := value[key].([]interface{}); isList {
+					name := h.name(value, selected)
+					if name == "" {
+						name = h.typeArg(field)
+					}
+					value[key] = h.visibleFields(list, name, child)
+				}
+			}
+			h.walk(value[key], childType, child)
+		}
+	}
+}
+
+// visibleTypes returns the __Type results of field in list that aren't hidden.
+func (h *introspectionFilter) visibleTypes(list []interface{}, field *ast.Field) []interface{} {
+	out := list[:0]
+	for _, item := range list {
+		if !h.hiddenType(item, field) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// visibleFields returns the __Field results of field in list, fields of the type named typeName,
+// that aren't hidden.  Without the names of the type and the fields, none of them are.
+func (h *introspectionFilter) visibleFields(list []interface{}, typeName string, field *ast.Field) []interface{} {
+	if typeName == "" {
+		return list[:0]
+	}
+	selected := h.fields(field.SelectionSet)
+	out := list[:0]
+	for _, item := range list {
+		m, _ := item.(map[string]interface{})
+		if name := h.name(m, selected); name == "" || h.hidden[typeName+"."+name] {
+			continue
+		}
+		out = append(out, item)
+	}
+	return out
+}
+
+// hiddenType reports whether value, a __Type result of field, is a hidden type, or a type that can't
+// be told from one because its name wasn't selected.  Lists and non-nulls have no name, and aren't
+// hidden themselves; the types they wrap are checked on their own.
+func (h *introspectionFilter) hiddenType(value interface{}, field *ast.Field) bool {
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	selected := h.fields(field.SelectionSet)
+	name, named := h.value(m, selected, "name")
+	if named && name != nil {
+		s, _ := name.(string)
+		return h.hidden[s]
+	}
+	// every named type has a name, so a null one is a wrapper.
+	kind, _ := h.value(m, selected, "kind")
+	return !named && kind != "LIST" && kind != "NON_NULL"
+}
+
+// typeArg returns the name arg of field, a __type field, or "" if it has none.
+func (h *introspectionFilter) typeArg(field *ast.Field) string {
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != "name" {
+			continue
+		}
+		switch v := arg.Value.(type) {
+		case *ast.StringValue:
+			return v.Value
+		case *ast.Variable:
+			name, _ := h.vars[v.Name.Value].(string)
+			return name
+		}
+	}
+	return ""
+}
+
+// name returns the name field of an introspection result, if it was selected.
+func (h *introspectionFilter) name(value map[string]interface{}, selected map[string]*ast.Field) string {
+	name, _ := h.value(value, selected, "name")
+	s, _ := name.(string)
+	return s
+}
+
+// value returns the field of an introspection result named fieldName, and whether it was selected.
+func (h *introspectionFilter) value(value map[string]interface{}, selected map[string]*ast.Field, fieldName string) (interface{}, bool) {
+	for key, field := range selected {
+		if field.Name.Value == fieldName {
+			return value[key], true
+		}
+	}
+	return nil, false
+}
+
+// introspectionRequested reports whether query might introspect the schema, to skip parsing
+// ordinary queries a second time.
+func introspectionRequested(query string) bool {
+	return strings.Contains(query, "__schema") || strings.Contains(query, "__type")
+}
@@ -0,0 +1,148 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+const (
+	// elemsTag sets the nullability of a list arg's elements: `elems:"nonnull"` makes a []string arg
+	// a [String!], and `elems:"nullable"`, the default, leaves it a [String].  Nested lists take a
+	// value per level, outermost first, so `elems:"nonnull,nonnull"` makes a [][]float64 arg a
+	// [[Float!]!].
+	elemsTag = "elems"
+	// minItemsTag sets the fewest items a list arg may have, as in `minitems:"1"`.  A minimum of one
+	// or more also makes the list itself non-null and required, since an omitted list has no items,
+	// so `elems:"nonnull" minitems:"1"` gives a [String!]!.
+	minItemsTag = "minitems"
+)
+
+// listSpec holds the list tags of an arg field.
+type listSpec struct {
+	// whether the elements of each level of nested lists are non-null, outermost first.
+	nonNullElems []bool
+	minItems     int
+}
+
+// listTags reads the list tags of field.
+func listTags(field reflect.StructField) (listSpec, error) {
+	var spec listSpec
+	if elems, ok := field.Tag.Lookup(elemsTag); ok {
+		for _, level := range strings.Split(elems, ",") {
+			switch level {
+			case "nullable":
+				spec.nonNullElems = append(spec.nonNullElems, false)
+			case "nonnull":
+				spec.nonNullElems = append(spec.nonNullElems, true)
+			default:
+				return spec, fmt.Errorf("%s is not a valid 'elems' tag value. use nonnull or nullable for each level of list", elems)
+			}
+		}
+	}
+	if v, ok := field.Tag.Lookup(minItemsTag); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return spec, fmt.Errorf("%s is not a valid 'minitems' tag value", v)
+		}
+		spec.minItems = n
+	}
+	return spec, nil
+}
+
+// fieldInputType returns the graphql type of an arg field of parent, which is the type of its Go type
+// with the field's oneof and list tags applied.  The field's transform and directive tags are
+// checked here too, so that bad tags are reported when args are configured rather than when they
+// are loaded.
+func (e *ArgLoader) fieldInputType(parent reflect.Type, field reflect.StructField) (graphql.Input, error) {
+	gqlType, err := e.inputType(field.Type)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := e.fieldTransforms(field); err != nil {
+		return nil, err
+	}
+	if _, err := e.fieldDirectives(field); err != nil {
+		return nil, err
+	}
+	if gqlType, err = e.oneofType(parent, field, gqlType); err != nil {
+		return nil, err
+	}
+	spec, err := listTags(field)
+	if err != nil || (len(spec.nonNullElems) == 0 && spec.minItems == 0) {
+		return gqlType, err
+	}
+	if _, ok := gqlType.(*graphql.List); !ok {
+		return nil, fmt.Errorf("the elems and minitems tags can only be used on lists, not %s", gqlType)
+	}
+	if gqlType, err = nonNullElems(gqlType, spec.nonNullElems); err != nil {
+		return nil, err
+	}
+	if spec.minItems > 0 {
+		gqlType = graphql.NewNonNull(gqlType)
+	}
+	return gqlType, nil
+}
+
+// nonNullElems makes the elements of each level of nested lists in t non-null, where levels says
+// so.  Elements that are already non-null stay that way.
+func nonNullElems(t graphql.Input, levels []bool) (graphql.Input, error) {
+	if len(levels) == 0 {
+		return t, nil
+	}
+	list, ok := t.(*graphql.List)
+	if !ok {
+		return nil, fmt.Errorf("the elems tag has a value for more levels of list than %s has", t)
+	}
+	elem := list.OfType
+	nonNull := levels[0]
+	if n, ok := elem.(*graphql.NonNull); ok {
+		elem, nonNull = n.OfType, true
+	}
+	elem, err := nonNullElems(elem, levels[1:])
+	if err != nil {
+		return nil, err
+	}
+	if nonNull {
+		elem = graphql.NewNonNull(elem)
+	}
+	return graphql.NewList(elem), nil
+}
+
+// check checks the raw value of a list arg against the list tags.  Values that aren't lists are
+// left for loading to reject.
+func (s listSpec) check(i interface{}) error {
+	items, ok := i.([]interface{})
+	if !ok {
+		return nil
+	}
+	if len(items) < s.minItems {
+		return fmt.Errorf("there must be at least %d items, not %d", s.minItems, len(items))
+	}
+	return checkNulls(items, s.nonNullElems)
+}
+
+// checkNulls fails if items, or the items of the lists nested in it, are null where levels says they
+// mustn't be.
+func checkNulls(items []interface{}, levels []bool) error {
+	if len(levels) == 0 {
+		return nil
+	}
+	for idx, item := range items {
+		if item == nil {
+			if levels[0] {
+				return &itemError{index: idx, err: fmt.Errorf("items cannot be null")}
+			}
+			continue
+		}
+		if nested, ok := item.([]interface{}); ok {
+			if err := checkNulls(nested, levels[1:]); err != nil {
+				return &itemError{index: idx, err: err}
+			}
+		}
+	}
+	return nil
+}
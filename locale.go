@@ -0,0 +1,228 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// CountryCode is an ISO 3166-1 alpha-2 country code, like "US".  After RegisterLocaleTypes, args
+// and results of type CountryCode use the CountryCode scalar.
+type CountryCode string
+
+// LanguageTag is a BCP 47 language tag, like "en-US" or "zh-Hant-TW".  After RegisterLocaleTypes,
+// args and results of type LanguageTag use the LanguageTag scalar.
+type LanguageTag string
+
+// CurrencyCode is an ISO 4217 currency code, like "USD".  After RegisterLocaleTypes, args and
+// results of type CurrencyCode use the CurrencyCode scalar.
+type CurrencyCode string
+
+// countryCodes holds the officially assigned ISO 3166-1 alpha-2 codes.
+var countryCodes = codeSet(`
+	AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ BA BB BD BE BF BG BH BI BJ BL BM BN BO BQ BR BS
+	BT BV BW BY BZ CA CC CD CF CG CH CI CK CL CM CN CO CR CU CV CW CX CY CZ DE DJ DK DM DO DZ EC EE
+	EG EH ER ES ET FI FJ FK FM FO FR GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY HK HM
+	HN HR HT HU ID IE IL IM IN IO IQ IR IS IT JE JM JO JP KE KG KH KI KM KN KP KR KW KY KZ LA LB LC
+	LI LK LR LS LT LU LV LY MA MC MD ME MF MG MH MK ML MM MN MO MP MQ MR MS MT MU MV MW MX MY MZ NA
+	NC NE NF NG NI NL NO NP NR NU NZ OM PA PE PF PG PH PK PL PM PN PR PS PT PW PY QA RE RO RS RU RW
+	SA SB SC SD SE SG SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ TC TD TF TG TH TJ TK TL TM TN TO
+	TR TT TV TW TZ UA UG UM US UY UZ VA VC VE VG VI VN VU WF WS YE YT ZA ZM ZW`)
+
+func codeSet(codes string) map[string]bool {
+	set := map[string]bool{}
+	for _, code := range strings.Fields(codes) {
+		set[code] = true
+	}
+	return set
+}
+
+// CountryCodeScalar is the graphql scalar of CountryCode args and results.
+var CountryCodeScalar = codeScalar("CountryCode", "An ISO 3166-1 alpha-2 country code, like US.")
+
+// LanguageTagScalar is the graphql scalar of LanguageTag args and results.
+var LanguageTagScalar = codeScalar("LanguageTag", "A BCP 47 language tag, like en-US.")
+
+// CurrencyCodeScalar is the graphql scalar of CurrencyCode args and results.
+var CurrencyCodeScalar = codeScalar("CurrencyCode", "An ISO 4217 currency code, like USD.")
+
+// codeScalar returns a string scalar that serializes any string type, and passes string inputs
+// through for the loader func to validate.
+func codeScalar(name, description string) *graphql.Scalar {
+	return graphql.NewScalar(graphql.ScalarConfig{
+		Name:        name,
+		Description: description,
+		Serialize: func(v interface{}) interface{} {
+			switch v := v.(type) {
+			case CountryCode:
+				return string(v)
+			case LanguageTag:
+				return string(v)
+			case CurrencyCode:
+				return string(v)
+			case string:
+				return v
+			}
+			return nil
+		},
+		ParseValue: func(v interface{}) interface{} {
+			if s, ok := v.(string); ok {
+				return s
+			}
+			return nil
+		},
+		ParseLiteral: func(v ast.Value) interface{} {
+			if s, ok := v.(*ast.StringValue); ok {
+				return s.Value
+			}
+			return nil
+		},
+	})
+}
+
+// LoadCountryCode loads a CountryCode, accepting either case and returning it upper-cased.
+func LoadCountryCode(i interface{}) (CountryCode, error) {
+	s, ok := i.(string)
+	if !ok {
+		return "", fmt.Errorf("%v is not a string", i)
+	}
+	code := strings.ToUpper(s)
+	if !countryCodes[code] {
+		return "", fmt.Errorf("%s is not an ISO 3166-1 alpha-2 country code", s)
+	}
+	return CountryCode(code), nil
+}
+
+// LoadCurrencyCode loads a CurrencyCode, accepting either case and returning it upper-cased.
+func LoadCurrencyCode(i interface{}) (CurrencyCode, error) {
+	s, ok := i.(string)
+	if !ok {
+		return "", fmt.Errorf("%v is not a string", i)
+	}
+	code := strings.ToUpper(s)
+	if _, ok := currencyMinorUnits[code]; !ok {
+		return "", fmt.Errorf("%s is not an ISO 4217 currency code", s)
+	}
+	return CurrencyCode(code), nil
+}
+
+// LoadLanguageTag loads a LanguageTag, checking that it is well-formed by the BCP 47 grammar and
+// returning it in the canonical case, so "EN-us" loads as "en-US".  Subtags aren't checked against
+// the IANA registry, and the grandfathered irregular tags, like "i-klingon", are rejected.
+func LoadLanguageTag(i interface{}) (LanguageTag, error) {
+	s, ok := i.(string)
+	if !ok {
+		return "", fmt.Errorf("%v is not a string", i)
+	}
+	tag, err := canonicalLanguageTag(s)
+	if err != nil {
+		return "", fmt.Errorf("%s is not a BCP 47 language tag: %v", s, err)
+	}
+	return LanguageTag(tag), nil
+}
+
+// canonicalLanguageTag parses s by the langtag and privateuse productions of RFC 5646 section 2.1,
+// and returns it with the language lower-cased, the script title-cased and the region upper-cased.
+func canonicalLanguageTag(s string) (string, error) {
+	subtags := strings.Split(strings.ToLower(s), "-")
+	for _, sub := range subtags {
+		if sub == "" || len(sub) > 8 || !isAlphanumeric(sub) {
+			return "", fmt.Errorf("%q is not a valid subtag", sub)
+		}
+	}
+	i := 0
+	if subtags[0] != "x" {
+		// language, with up to three extlangs after a two or three letter language.
+		if !isAlpha(subtags[0]) || len(subtags[0]) == 1 {
+			return "", fmt.Errorf("%q is not a language subtag", subtags[0])
+		}
+		i = 1
+		if len(subtags[0]) <= 3 {
+			for n := 0; n < 3 && i < len(subtags) && len(subtags[i]) == 3 && isAlpha(subtags[i]); n++ {
+				i++
+			}
+		}
+		if i < len(subtags) && len(subtags[i]) == 4 && isAlpha(subtags[i]) {
+			subtags[i] = strings.ToUpper(subtags[i][:1]) + subtags[i][1:]
+			i++
+		}
+		if i < len(subtags) && (len(subtags[i]) == 2 && isAlpha(subtags[i]) || len(subtags[i]) == 3 && isDigits(subtags[i])) {
+			subtags[i] = strings.ToUpper(subtags[i])
+			i++
+		}
+		for i < len(subtags) && (len(subtags[i]) >= 5 || len(subtags[i]) == 4 && isDigits(subtags[i][:1])) {
+			i++
+		}
+		singletons := map[string]bool{}
+		for i < len(subtags) && len(subtags[i]) == 1 && subtags[i] != "x" {
+			if singletons[subtags[i]] {
+				return "", fmt.Errorf("the %s extension is repeated", subtags[i])
+			}
+			singletons[subtags[i]] = true
+			i++
+			start := i
+			for i < len(subtags) && len(subtags[i]) >= 2 {
+				i++
+			}
+			if i == start {
+				return "", fmt.Errorf("the %s extension is empty", subtags[i-1])
+			}
+		}
+	}
+	if i < len(subtags) && subtags[i] == "x" {
+		if i == len(subtags)-1 {
+			return "", fmt.Errorf("the private use section is empty")
+		}
+		i = len(subtags)
+	}
+	if i < len(subtags) {
+		return "", fmt.Errorf("%q is out of place", subtags[i])
+	}
+	return strings.Join(subtags, "-"), nil
+}
+
+func isAlpha(s string) bool {
+	for _, r := range s {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphanumeric(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// RegisterLocaleTypes registers CountryCode, LanguageTag and CurrencyCode on the loader, with their
+// scalars and loader funcs.
+func (e *ArgLoader) RegisterLocaleTypes() error {
+	if err := e.Register(LoadCountryCode, CountryCodeScalar); err != nil {
+		return err
+	}
+	if err := e.Register(LoadLanguageTag, LanguageTagScalar); err != nil {
+		return err
+	}
+	return e.Register(LoadCurrencyCode, CurrencyCodeScalar)
+}
+
+// RegisterLocaleTypes registers CountryCode, LanguageTag and CurrencyCode on the default loader.
+func RegisterLocaleTypes() error {
+	return Default().RegisterLocaleTypes()
+}
@@ -0,0 +1,60 @@
+package graphqlhelpers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Metrics receives measurements from an instrumented ArgLoader.  See the prommetrics package for
+// a Prometheus implementation.
+type Metrics interface {
+	// LoadError counts a LoadArgs failure for the field (like "Query.user") and arg.  arg is empty
+	// if the failure isn't tied to one arg, as with a failed Validate.
+	LoadError(field, arg string)
+
+	// ResolverDuration observes how long a resolver for the field took, and whether it failed.
+	ResolverDuration(field string, d time.Duration, failed bool)
+}
+
+// Instrument reports argument load errors and resolver durations to m.  As with Use, only resolvers
+// built afterwards are timed.
+func (e *ArgLoader) Instrument(m Metrics) {
+	e.OnLoad(MetricsLoadHook(m))
+	e.Use(MetricsMiddleware(m))
+}
+
+// Instrument reports the default loader's argument load errors and resolver durations to m.
+func Instrument(m Metrics) {
+	Default().Instrument(m)
+}
+
+// MetricsLoadHook returns a LoadHook counting load errors with m.
+func MetricsLoadHook(m Metrics) LoadHook {
+	return func(p graphql.ResolveParams, target interface{}) func(error) {
+		return func(err error) {
+			if err == nil {
+				return
+			}
+			var argErr *ArgError
+			arg := ""
+			if errors.As(err, &argErr) {
+				arg = argErr.Arg
+			}
+			m.LoadError(FieldPath(p.Info), arg)
+		}
+	}
+}
+
+// MetricsMiddleware returns resolver middleware observing resolver durations with m.
+func MetricsMiddleware(m Metrics) Middleware {
+	return func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (interface{}, error) {
+			start := time.Now()
+			result, err := next(p)
+			m.ResolverDuration(FieldPath(p.Info), time.Since(start), err != nil)
+			return result, err
+		}
+	}
+}
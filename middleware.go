@@ -0,0 +1,72 @@
+package graphqlhelpers
+
+import (
+	"time"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Middleware wraps a resolver to add behavior around it, like logging or timing.
+type Middleware func(next graphql.FieldResolveFn) graphql.FieldResolveFn
+
+// Chain combines middleware into one.  The first middleware is the outermost, so it sees the call
+// first and the result last.
+func Chain(mw ...Middleware) Middleware {
+	return func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+		for i := len(mw) - 1; i >= 0; i-- {
+			next = mw[i](next)
+		}
+		return next
+	}
+}
+
+// Use installs middleware around every resolver the loader builds from now on, with FieldFromFunc,
+// Resolver, SchemaBuilder and the other builders.  Fields built before calling Use aren't affected,
+// so install middleware first.
+func (e *ArgLoader) Use(mw ...Middleware) {
+	e.middleware = append(e.middleware, mw...)
+}
+
+// Use installs middleware around every resolver the default loader builds from now on.
+func Use(mw ...Middleware) {
+	Default().Use(mw...)
+}
+
+// Recovery returns middleware that recovers panicking resolvers, as Recover does, so one bad
+// resolver doesn't take down the whole request.
+func Recovery() Middleware {
+	return RecoverWith(nil)
+}
+
+// Logging returns middleware that logs every failed resolver call with logf, which may be log.Printf.
+func Logging(logf func(format string, args ...interface{})) Middleware {
+	return func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (interface{}, error) {
+			result, err := next(p)
+			if err != nil {
+				logf("%s: %v", FieldPath(p.Info), err)
+			}
+			return result, err
+		}
+	}
+}
+
+// Timing returns middleware that reports how long every resolver call took to observe.
+func Timing(observe func(info graphql.ResolveInfo, d time.Duration)) Middleware {
+	return func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (interface{}, error) {
+			start := time.Now()
+			result, err := next(p)
+			observe(p.Info, time.Since(start))
+			return result, err
+		}
+	}
+}
+
+// FieldPath returns the schema coordinate of the field being resolved, like "Query.user".
+func FieldPath(info graphql.ResolveInfo) string {
+	if info.ParentType == nil {
+		return info.FieldName
+	}
+	return info.ParentType.Name() + "." + info.FieldName
+}
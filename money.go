@@ -0,0 +1,154 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Money is an amount of a currency, counted in the currency's minor units, so 1234 USD is $12.34
+// and 1234 JPY is ¥1234.  After RegisterMoneyTypes, args of type Money are MoneyInput input objects,
+// loaded with LoadMoney, and results of type Money are Money objects.
+//
+// Amounts are GraphQL Ints, so they're limited to 32 bits: 21,474,836.47 in a currency with two
+// digits of minor units.
+type Money struct {
+	// Amount is in the minor units of Currency, and may be negative.
+	Amount int64
+	// Currency is an ISO 4217 currency code, like "USD".
+	Currency string
+}
+
+// Validate checks that Currency is an active ISO 4217 currency code.
+func (m Money) Validate() error {
+	if _, ok := currencyMinorUnits[m.Currency]; !ok {
+		return fmt.Errorf("%s is not an ISO 4217 currency code", m.Currency)
+	}
+	return nil
+}
+
+// Decimal returns the amount in major units, like "12.34" for 1234 USD.
+func (m Money) Decimal() string {
+	digits := currencyMinorUnits[m.Currency]
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	s := strconv.FormatInt(amount, 10)
+	if digits == 0 {
+		return sign + s
+	}
+	if len(s) <= digits {
+		s = strings.Repeat("0", digits-len(s)+1) + s
+	}
+	return sign + s[:len(s)-digits] + "." + s[len(s)-digits:]
+}
+
+// String returns the amount in major units and the currency, like "12.34 USD".
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+// MoneyInput is the graphql input type of Money args.
+var MoneyInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "MoneyInput",
+	Description: "An amount of a currency.",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"amount": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The amount in the currency's minor units, like cents for USD.",
+		},
+		"currency": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "An ISO 4217 currency code, like USD.",
+		},
+	},
+})
+
+// MoneyType is the graphql object type of Money results.
+var MoneyType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Money",
+	Description: "An amount of a currency.",
+	Fields: graphql.Fields{
+		"amount": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The amount in the currency's minor units, like cents for USD.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return moneySource(p.Source).Amount, nil
+			},
+		},
+		"currency": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "An ISO 4217 currency code, like USD.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return moneySource(p.Source).Currency, nil
+			},
+		},
+		"decimal": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The amount in the currency's major units, like 12.34 for 1234 cents.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return moneySource(p.Source).Decimal(), nil
+			},
+		},
+	},
+})
+
+func moneySource(source interface{}) Money {
+	if m, ok := source.(*Money); ok && m != nil {
+		return *m
+	}
+	m, _ := source.(Money)
+	return m
+}
+
+// LoadMoney loads a Money from a raw MoneyInput value, and validates its currency.
+func LoadMoney(i interface{}) (Money, error) {
+	raw, ok := i.(map[string]interface{})
+	if !ok {
+		return Money{}, fmt.Errorf("%v is not a MoneyInput", i)
+	}
+	var m Money
+	switch amount := raw["amount"].(type) {
+	case int:
+		m.Amount = int64(amount)
+	case int64:
+		m.Amount = amount
+	case nil:
+		return Money{}, fmt.Errorf("amount is required")
+	default:
+		return Money{}, fmt.Errorf("amount: %v is not an int", raw["amount"])
+	}
+	switch currency := raw["currency"].(type) {
+	case string:
+		m.Currency = currency
+	case nil:
+		return Money{}, fmt.Errorf("currency is required")
+	default:
+		return Money{}, fmt.Errorf("currency: %v is not a string", raw["currency"])
+	}
+	return m, m.Validate()
+}
+
+// RegisterMoneyTypes registers Money on the loader: as args, with the MoneyInput input type and
+// LoadMoney, and as results, with the Money object.
+func (e *ArgLoader) RegisterMoneyTypes() error {
+	if err := e.Register(LoadMoney, MoneyInput); err != nil {
+		return err
+	}
+	if err := e.RegisterDumper(func(m Money) (interface{}, error) {
+		return map[string]interface{}{"amount": m.Amount, "currency": m.Currency}, nil
+	}); err != nil {
+		return err
+	}
+	e.typeNames[MoneyType.Name()] = MoneyType
+	return e.RegisterOutput(Money{}, MoneyType)
+}
+
+// RegisterMoneyTypes registers Money on the default loader.
+func RegisterMoneyTypes() error {
+	return Default().RegisterMoneyTypes()
+}
@@ -0,0 +1,96 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/graphql-go/graphql"
+)
+
+// ArgsConfigMulti is like ArgsConfig, but merges the args of several structs, for resolvers that
+// combine reusable groups of args without embedding them in one struct.  It panics if the configs
+// can't be generated or two of the structs define the same arg.
+func (e *ArgLoader) ArgsConfigMulti(structs ...interface{}) graphql.FieldConfigArgument {
+	conf, err := e.SafeArgsConfigMulti(structs...)
+	if err != nil {
+		panic(fmt.Sprintf("could not configure arguments: %v", err))
+	}
+	return conf
+}
+
+// SafeArgsConfigMulti is like ArgsConfigMulti, but returns an error instead of panicking.
+func (e *ArgLoader) SafeArgsConfigMulti(structs ...interface{}) (graphql.FieldConfigArgument, error) {
+	if _, err := e.argOwners(structs); err != nil {
+		return nil, err
+	}
+	out := graphql.FieldConfigArgument{}
+	for _, s := range structs {
+		conf, err := e.SafeArgsConfig(s)
+		if err != nil {
+			return nil, err
+		}
+		for name, arg := range conf {
+			out[name] = arg
+		}
+	}
+	return out, nil
+}
+
+// LoadArgsMulti loads args configured with ArgsConfigMulti into targets, which are pointers to the
+// same structs, in the same order.  Each target is loaded with LoadArgs, so load hooks run once per
+// target, and a rest field only collects the args that none of the targets define.
+func (e *ArgLoader) LoadArgsMulti(p graphql.ResolveParams, targets ...interface{}) error {
+	owners, err := e.argOwners(targets)
+	if err != nil {
+		return err
+	}
+	for i, target := range targets {
+		own := p
+		own.Args = map[string]interface{}{}
+		for name, value := range p.Args {
+			if owner, ok := owners[name]; !ok || owner == i {
+				own.Args[name] = value
+			}
+		}
+		if err := e.LoadArgs(own, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// argOwners maps the name of every arg defined by structs to the index of the struct defining it,
+// returning an error if two of them define the same arg.
+func (e *ArgLoader) argOwners(structs []interface{}) (map[string]int, error) {
+	owners := map[string]int{}
+	types := make([]reflect.Type, len(structs))
+	for i, s := range structs {
+		t, err := argsStructType(s)
+		if err != nil {
+			return nil, err
+		}
+		types[i] = t
+		for _, f := range e.argFields(t) {
+			if f.rest {
+				continue
+			}
+			for _, name := range append([]string{f.name}, f.aliases...) {
+				if other, ok := owners[name]; ok {
+					return nil, fmt.Errorf("arg %s is defined by both %v and %v", name, types[other], t)
+				}
+				owners[name] = i
+			}
+		}
+	}
+	return owners, nil
+}
+
+// ArgsConfigMulti merges the arg configs of several structs using the default loader.
+func ArgsConfigMulti(structs ...interface{}) graphql.FieldConfigArgument {
+	return Default().ArgsConfigMulti(structs...)
+}
+
+// LoadArgsMulti loads args into several structs using the default loader.
+func LoadArgsMulti(p graphql.ResolveParams, targets ...interface{}) error {
+	return Default().LoadArgsMulti(p, targets...)
+}
@@ -0,0 +1,92 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// The Must helpers are for setup code, like init funcs and package-level vars, where there's
+// nothing to do with an error but stop.  They panic with a message naming what failed and the file
+// and line that called them, so a broken struct tag is quick to find.
+
+// MustNew is like New, but panics if the loader can't be built.
+func MustNew() *ArgLoader {
+	e, err := New()
+	if err != nil {
+		mustPanic("could not build arg loader", err)
+	}
+	return e
+}
+
+// MustRegister is like Register, but panics if the loader func can't be registered.
+func (e *ArgLoader) MustRegister(f interface{}, gqlType graphql.Output) {
+	if err := e.Register(f, gqlType); err != nil {
+		mustPanic(fmt.Sprintf("could not register %s as the loader func for %s", loaderFuncName(f), gqlTypeName(gqlType)), err)
+	}
+}
+
+// MustRegister registers a loader func on the default loader, panicking if it can't be registered.
+func MustRegister(f interface{}, gqlType graphql.Output) {
+	Default().MustRegister(f, gqlType)
+}
+
+// MustArgsConfig is ArgsConfig, panicking with a message naming the args struct if its arguments
+// can't be configured.
+func (e *ArgLoader) MustArgsConfig(i interface{}) graphql.FieldConfigArgument {
+	conf, err := e.SafeArgsConfig(i)
+	if err != nil {
+		mustPanic(fmt.Sprintf("could not configure arguments for %T", i), err)
+	}
+	return conf
+}
+
+// MustArgsConfig configures arguments with the default loader, panicking if they can't be
+// configured.
+func MustArgsConfig(i interface{}) graphql.FieldConfigArgument {
+	return Default().MustArgsConfig(i)
+}
+
+// mustPanic panics with msg and err, and where the Must helper that failed was called from.
+func mustPanic(msg string, err error) {
+	// skip mustPanic, the Must helper, and the package-level wrapper if it was called through one.
+	for skip := 2; skip < 5; skip++ {
+		pc, file, line, ok := runtime.Caller(skip)
+		if !ok {
+			break
+		}
+		if fn := runtime.FuncForPC(pc); fn != nil && isMustHelper(fn.Name()) {
+			continue
+		}
+		panic(fmt.Sprintf("%s (called at %s:%d): %v", msg, file, line, err))
+	}
+	panic(fmt.Sprintf("%s: %v", msg, err))
+}
+
+// isMustHelper reports whether the func named name is one of this package's Must helpers.
+func isMustHelper(name string) bool {
+	pkg := reflect.TypeOf(ArgLoader{}).PkgPath()
+	for _, prefix := range []string{pkg + ".Must", pkg + ".(*ArgLoader).Must"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func loaderFuncName(f interface{}) string {
+	if v := reflect.ValueOf(f); v.Kind() == reflect.Func {
+		return runtime.FuncForPC(v.Pointer()).Name()
+	}
+	return fmt.Sprintf("%v", f)
+}
+
+func gqlTypeName(t graphql.Output) string {
+	if t == nil {
+		return "a nil graphql type"
+	}
+	return t.String()
+}
@@ -0,0 +1,69 @@
+package graphqlhelpers
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// NamingStrategy decides the graphql names of things generated from Go structs.
+type NamingStrategy interface {
+	// InputObjectName returns the name of the input object generated for a struct type.
+	InputObjectName(t reflect.Type) string
+
+	// ArgName returns the argument (or input field) name for a struct field whose arg tag doesn't
+	// name it, such as `arg:""`, or that has no arg tag when ImplicitArgs is enabled.
+	ArgName(field reflect.StructField) string
+}
+
+// DefaultNaming names input objects after their structs, optionally with a suffix, and args after
+// the camelCased Go field name.
+type DefaultNaming struct {
+	// InputSuffix is appended to generated input object names that don't already end with it.  Set
+	// it to "Input" to get UserInput from a User struct.
+	InputSuffix string
+}
+
+// InputObjectName implements NamingStrategy.
+func (n DefaultNaming) InputObjectName(t reflect.Type) string {
+	if strings.HasSuffix(t.Name(), n.InputSuffix) {
+		return t.Name()
+	}
+	return t.Name() + n.InputSuffix
+}
+
+// ArgName implements NamingStrategy.
+func (n DefaultNaming) ArgName(field reflect.StructField) string {
+	return CamelCase(field.Name)
+}
+
+// SetNamingStrategy replaces the loader's NamingStrategy.  It only affects configs generated after
+// it is called, so call it before ArgsConfig.
+func (e *ArgLoader) SetNamingStrategy(n NamingStrategy) {
+	e.naming = n
+	e.resetPlans()
+}
+
+// SetNamingStrategy replaces the default loader's NamingStrategy.
+func SetNamingStrategy(n NamingStrategy) {
+	Default().SetNamingStrategy(n)
+}
+
+// CamelCase lower-cases the leading word of a Go identifier, so that UserID becomes userID,
+// HTTPProxy becomes httpProxy and URLs becomes urls.
+func CamelCase(s string) string {
+	runes := []rune(s)
+	for i := 0; i < len(runes) && unicode.IsUpper(runes[i]); i++ {
+		// keep the last capital of an acronym that starts the next word.
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) && !isPluralS(runes, i+1) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// isPluralS reports whether runes[i] is the "s" of a pluralized acronym, like the one in URLs.
+func isPluralS(runes []rune, i int) bool {
+	return runes[i] == 's' && (i+1 == len(runes) || unicode.IsUpper(runes[i+1]))
+}
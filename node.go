@@ -0,0 +1,208 @@
+package graphqlhelpers
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// GlobalID returns the Relay global ID for the object of the given type name and ID: the base64
+// encoding of "TypeName:id".
+func GlobalID(typeName, id string) string {
+	return base64.StdEncoding.EncodeToString([]byte(typeName + ":" + id))
+}
+
+// ParseGlobalID splits a global ID made by GlobalID back into its type name and ID.
+func ParseGlobalID(globalID string) (typeName, id string, err error) {
+	b, err := base64.StdEncoding.DecodeString(globalID)
+	if err != nil {
+		return "", "", fmt.Errorf("%q is not a valid global ID", globalID)
+	}
+	parts := strings.SplitN(string(b), ":", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", "", fmt.Errorf("%q is not a valid global ID", globalID)
+	}
+	return parts[0], parts[1], nil
+}
+
+// NodeFetcher loads the object with the given ID (not global ID) for the node field.  It should
+// return a value of the type it was registered for, or a pointer to one, or nil if there is no such
+// object.
+type NodeFetcher func(ctx context.Context, id string) (interface{}, error)
+
+// Nodes implements Relay's global object identification: a Node interface, node and nodes fields
+// that fetch any registered type by global ID, and global id fields on the registered objects.
+//
+//	nodes, err := loader.NewNodes()
+//	err = nodes.Register(User{}, func(ctx context.Context, id string) (interface{}, error) {
+//		return db.FindUser(ctx, id)
+//	})
+//	schema, err := loader.NewSchemaBuilder().
+//		AddField("query", "node", nodes.Field()).
+//		AddField("query", "nodes", nodes.ListField()).
+//		...
+type Nodes struct {
+	def      *interfaceDef
+	loader   *ArgLoader
+	types    map[reflect.Type]nodeType
+	fetchers map[string]NodeFetcher
+}
+
+type nodeType struct {
+	name string
+	// index of the struct field holding the object's own ID.
+	index []int
+}
+
+// NewNodes registers the Node interface on the loader and returns the Nodes for registering types
+// with it.  It can only be called once per loader, since there is only one Node interface.
+func (e *ArgLoader) NewNodes() (*Nodes, error) {
+	n := &Nodes{
+		loader:   e,
+		types:    map[reflect.Type]nodeType{},
+		fetchers: map[string]NodeFetcher{},
+	}
+	n.def = &interfaceDef{types: map[reflect.Type]bool{}, fields: n.idField}
+	_, err := e.registerInterface("Node", graphql.Fields{
+		"id": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.ID),
+			Description: "The globally unique ID of the object.",
+		},
+	}, n.def)
+	if err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// Register makes the object generated for model's struct type implement Node, and fetchable by
+// global ID with fetch.  The struct must have a field tagged `field:"id"` holding its own ID, which
+// the object exposes as its global ID instead.
+func (n *Nodes) Register(model interface{}, fetch NodeFetcher) error {
+	t := reflect.TypeOf(model)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return fmt.Errorf("cannot register %v as a node: nodes must be structs", reflect.TypeOf(model))
+	}
+	if _, ok := n.types[t]; ok {
+		return fmt.Errorf("%v has already been registered as a node", t)
+	}
+	var index []int
+	for _, f := range outputFields(t) {
+		if f.name == "id" {
+			index = f.index
+		}
+	}
+	if index == nil {
+		return fmt.Errorf("cannot register %v as a node: it has no field tagged `%s:\"id\"`", t, fieldTag)
+	}
+	obj, err := n.loader.object(t)
+	if err != nil {
+		return fmt.Errorf("cannot register %v as a node: %v", t, err)
+	}
+	n.types[t] = nodeType{name: obj.Name(), index: index}
+	n.fetchers[obj.Name()] = fetch
+	n.def.types[t] = true
+	n.def.objects[t] = obj
+	return nil
+}
+
+// idField replaces the id field of a node object with one resolving to its global ID.
+func (n *Nodes) idField(t reflect.Type) graphql.Fields {
+	nt := n.types[t]
+	resolveID := structFieldResolver(t, nt.index)
+	return graphql.Fields{
+		"id": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.ID),
+			Description: "The globally unique ID of the object.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				id, err := resolveID(p)
+				if err != nil {
+					return nil, err
+				}
+				return GlobalID(nt.name, fmt.Sprint(id)), nil
+			},
+		},
+	}
+}
+
+// Interface returns the Node interface.
+func (n *Nodes) Interface() *graphql.Interface {
+	return n.def.gqlType
+}
+
+// Fetch loads the object with the given global ID using the fetcher registered for its type.
+func (n *Nodes) Fetch(ctx context.Context, globalID string) (interface{}, error) {
+	typeName, id, err := ParseGlobalID(globalID)
+	if err != nil {
+		return nil, err
+	}
+	fetch, ok := n.fetchers[typeName]
+	if !ok {
+		return nil, fmt.Errorf("%q is not the ID of a node", globalID)
+	}
+	return fetch(ctx, id)
+}
+
+// Field returns the node(id: ID!): Node field for the root query type.
+func (n *Nodes) Field() *graphql.Field {
+	return &graphql.Field{
+		Type:        n.def.gqlType,
+		Description: "Fetches an object given its ID.",
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Type:        graphql.NewNonNull(graphql.ID),
+				Description: "The ID of the object.",
+			},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			id, _ := p.Args["id"].(string)
+			return n.Fetch(nodeContext(p), id)
+		},
+	}
+}
+
+// ListField returns the nodes(ids: [ID!]!): [Node]! field for the root query type.
+func (n *Nodes) ListField() *graphql.Field {
+	return &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.NewList(n.def.gqlType)),
+		Description: "Fetches objects given their IDs.",
+		Args: graphql.FieldConfigArgument{
+			"ids": &graphql.ArgumentConfig{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.ID))),
+				Description: "The IDs of the objects.",
+			},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			ids, _ := p.Args["ids"].([]interface{})
+			out := make([]interface{}, len(ids))
+			for i, id := range ids {
+				s, _ := id.(string)
+				obj, err := n.Fetch(nodeContext(p), s)
+				if err != nil {
+					return nil, err
+				}
+				out[i] = obj
+			}
+			return out, nil
+		},
+	}
+}
+
+func nodeContext(p graphql.ResolveParams) context.Context {
+	if p.Context == nil {
+		return context.Background()
+	}
+	return p.Context
+}
+
+// NewNodes registers the Node interface on the default loader.
+func NewNodes() (*Nodes, error) {
+	return Default().NewNodes()
+}
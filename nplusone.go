@@ -0,0 +1,100 @@
+package graphqlhelpers
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+)
+
+// NPlusOneWarning reports a field whose resolver ran many times in one request, which usually
+// means it's resolved once for every item of a list, each time making its own query.
+type NPlusOneWarning struct {
+	// Operation is the name of the operation, if it has one.
+	Operation string
+	// Field is the schema coordinate of the field, like "User.posts".
+	Field string
+	// Count is how many times its resolver ran.
+	Count int
+}
+
+// NPlusOneLogger records a warning from N+1 detection.
+type NPlusOneLogger func(ctx context.Context, w NPlusOneWarning)
+
+// DefaultNPlusOneLogger is used by DetectNPlusOne when given a nil logger.  It logs with the
+// standard log package.
+var DefaultNPlusOneLogger NPlusOneLogger = func(ctx context.Context, w NPlusOneWarning) {
+	op := w.Operation
+	if op == "" {
+		op = "(anonymous)"
+	}
+	log.Printf("possible N+1 queries: %s was resolved %d times in operation %s. "+
+		"load it for the whole list at once, with a DataLoader", w.Field, w.Count, op)
+}
+
+type nPlusOneKey struct{}
+
+// resolverCounts counts the resolver calls of one request, by field.
+type resolverCounts struct {
+	mu        sync.Mutex
+	operation string
+	counts    map[string]int
+}
+
+// CountResolvers returns middleware counting the calls of every resolver it wraps, for N+1
+// detection in requests run with DetectNPlusOne or WithNPlusOneDetection.  Install it with Use; in
+// other requests it does nothing.  It is a debugging aid, meant for development.
+func CountResolvers() Middleware {
+	return func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (interface{}, error) {
+			if c, ok := contextValue(p.Context, nPlusOneKey{}).(*resolverCounts); ok {
+				c.mu.Lock()
+				c.operation = operationName(p.Info)
+				c.counts[FieldPath(p.Info)]++
+				c.mu.Unlock()
+			}
+			return next(p)
+		}
+	}
+}
+
+// DetectNPlusOne returns a context for running a request in which CountResolvers counts resolver
+// calls, and a func to call when the request is done, which warns with logger (or
+// DefaultNPlusOneLogger if it's nil) about every field resolved more than threshold times.
+func DetectNPlusOne(ctx context.Context, threshold int, logger NPlusOneLogger) (context.Context, func()) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if logger == nil {
+		logger = DefaultNPlusOneLogger
+	}
+	c := &resolverCounts{counts: map[string]int{}}
+	done := func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		var fields []string
+		for field, n := range c.counts {
+			if n > threshold {
+				fields = append(fields, field)
+			}
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			logger(ctx, NPlusOneWarning{Operation: c.operation, Field: field, Count: c.counts[field]})
+		}
+	}
+	return context.WithValue(ctx, nPlusOneKey{}, c), done
+}
+
+// WithNPlusOneDetection warns with logger (or DefaultNPlusOneLogger if it's nil) when an operation
+// resolves a field more than threshold times.  Resolvers are only counted if they're wrapped in
+// CountResolvers middleware.
+func WithNPlusOneDetection(threshold int, logger NPlusOneLogger) HandlerOption {
+	return func(h *handler) {
+		h.nPlusOne = func(ctx context.Context) (context.Context, func()) {
+			return DetectNPlusOne(ctx, threshold, logger)
+		}
+	}
+}
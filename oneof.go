@@ -0,0 +1,125 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// oneofTag restricts a string arg to a space-separated list of values, like `oneof:"asc desc"`.
+// The arg's graphql type becomes an enum of those values instead of String, named after the args
+// struct and field (SortArgsDirection for the Direction field of SortArgs), with each value's name
+// upper-cased.  Lists of strings may be restricted too, in which case every item must be one of the
+// values.
+const oneofTag = "oneof"
+
+// oneofValues returns the values in field's oneof tag, or nil if it has none.
+func oneofValues(field reflect.StructField) ([]string, error) {
+	tag, ok := field.Tag.Lookup(oneofTag)
+	if !ok {
+		return nil, nil
+	}
+	values := strings.Fields(tag)
+	if len(values) == 0 {
+		return nil, fmt.Errorf("the %s tag has no values", oneofTag)
+	}
+	seen := map[string]bool{}
+	for _, v := range values {
+		if seen[v] {
+			return nil, fmt.Errorf("the %s tag has %s more than once", oneofTag, v)
+		}
+		seen[v] = true
+	}
+	return values, nil
+}
+
+// oneofType replaces the String in gqlType, the input type of a field of parent, with an enum of
+// the field's oneof values.  Enums are cached by name, so that a struct configured more than once
+// keeps the same enum.
+func (e *ArgLoader) oneofType(parent reflect.Type, field reflect.StructField, gqlType graphql.Input) (graphql.Input, error) {
+	values, err := oneofValues(field)
+	if err != nil || values == nil {
+		return gqlType, err
+	}
+	if named := graphql.GetNamed(gqlType); named != graphql.Named(graphql.String) {
+		return nil, fmt.Errorf("the %s tag can only be used on strings, not %s", oneofTag, named)
+	}
+	name := parent.Name() + field.Name
+	enum, ok := e.oneofEnums[name]
+	if ok && !sameEnumValues(enum, values) {
+		return nil, fmt.Errorf("cannot generate enum %s for the %s tag: another field generated it with different values", name, oneofTag)
+	}
+	if !ok {
+		if _, taken := e.typeNames[name]; taken {
+			return nil, fmt.Errorf("cannot generate enum %s for the %s tag: the name is already used by %s",
+				name, oneofTag, e.typeDescription(name))
+		}
+		config := graphql.EnumValueConfigMap{}
+		for _, v := range values {
+			if _, ok := config[enumName(v)]; ok {
+				return nil, fmt.Errorf("the %s tag has more than one value named %s", oneofTag, enumName(v))
+			}
+			config[enumName(v)] = &graphql.EnumValueConfig{Value: v}
+		}
+		enum = graphql.NewEnum(graphql.EnumConfig{Name: name, Values: config})
+		if err := enum.Error(); err != nil {
+			return nil, fmt.Errorf("cannot generate enum %s for the %s tag: %v", name, oneofTag, err)
+		}
+		e.oneofEnums[name] = enum
+		e.typeNames[name] = enum
+	}
+	return replaceString(gqlType, enum), nil
+}
+
+func sameEnumValues(enum *graphql.Enum, values []string) bool {
+	var have []string
+	for _, v := range enum.Values() {
+		have = append(have, v.Value.(string))
+	}
+	want := append([]string(nil), values...)
+	sort.Strings(have)
+	sort.Strings(want)
+	return strings.Join(have, " ") == strings.Join(want, " ")
+}
+
+// replaceString returns t with the String at the bottom of its lists replaced by enum.
+func replaceString(t graphql.Input, enum *graphql.Enum) graphql.Input {
+	switch t := t.(type) {
+	case *graphql.List:
+		return graphql.NewList(replaceString(t.OfType, enum))
+	case *graphql.NonNull:
+		return graphql.NewNonNull(replaceString(t.OfType, enum))
+	}
+	return enum
+}
+
+// checkOneof checks that every string in v is one of values.
+func (e *ArgLoader) checkOneof(v reflect.Value, values []string) error {
+	if values == nil {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		for _, allowed := range values {
+			if v.String() == allowed {
+				return nil
+			}
+		}
+		return e.valueError(&ValueError{Value: v.String(), Problem: "is not one of " + strings.Join(values, ", ")}, v.String())
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return e.checkOneof(v.Elem(), values)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := e.checkOneof(v.Index(i), values); err != nil {
+				return &itemError{index: i, err: err}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,612 @@
+// Package openapigen generates graphqlhelpers args structs from an OpenAPI 3 document, to speed up
+// putting a graphql facade in front of an existing REST service.
+//
+// Each operation gets an args struct with a field for each of its path and query parameters, and
+// an input field for its JSON request body.  The schemas of the body and of object parameters
+// become structs of their own, which ArgsConfig turns into input objects:
+//
+//	// ListPetsArgs are the args of the listPets operation, GET /pets.
+//	type ListPetsArgs struct {
+//		Limit int      `arg:"limit" desc:"How many items to return at one time"`
+//		Tags  []string `arg:"tags" elems:"nonnull"`
+//	}
+//
+// Schemas map to Go types as graphqlhelpers loads them: strings to string (or time.Time for the
+// date-time format), integers to int, numbers to float64 and booleans to bool, while string enums
+// get a oneof tag.  Required properties and parameters get a required tag, descriptions a desc
+// tag, and deprecated ones a deprecated tag.  Header and cookie parameters, and schemas with no
+// Go equivalent, like free-form objects and oneOf, are left out with a comment saying so.
+package openapigen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config controls code generation.
+type Config struct {
+	// Package is the name of the generated file's package.  It defaults to api.
+	Package string
+}
+
+type document struct {
+	Paths      map[string]*pathItem `yaml:"paths"`
+	Components struct {
+		Schemas       map[string]*schema      `yaml:"schemas"`
+		Parameters    map[string]*parameter   `yaml:"parameters"`
+		RequestBodies map[string]*requestBody `yaml:"requestBodies"`
+	} `yaml:"components"`
+}
+
+type pathItem struct {
+	Parameters []*parameter `yaml:"parameters"`
+	Get        *operation   `yaml:"get"`
+	Put        *operation   `yaml:"put"`
+	Post       *operation   `yaml:"post"`
+	Delete     *operation   `yaml:"delete"`
+	Options    *operation   `yaml:"options"`
+	Head       *operation   `yaml:"head"`
+	Patch      *operation   `yaml:"patch"`
+	Trace      *operation   `yaml:"trace"`
+}
+
+type operation struct {
+	OperationID string       `yaml:"operationId"`
+	Summary     string       `yaml:"summary"`
+	Description string       `yaml:"description"`
+	Deprecated  bool         `yaml:"deprecated"`
+	Parameters  []*parameter `yaml:"parameters"`
+	RequestBody *requestBody `yaml:"requestBody"`
+}
+
+type parameter struct {
+	Ref         string  `yaml:"$ref"`
+	Name        string  `yaml:"name"`
+	In          string  `yaml:"in"`
+	Description string  `yaml:"description"`
+	Required    bool    `yaml:"required"`
+	Deprecated  bool    `yaml:"deprecated"`
+	Schema      *schema `yaml:"schema"`
+}
+
+type requestBody struct {
+	Ref         string `yaml:"$ref"`
+	Description string `yaml:"description"`
+	Required    bool   `yaml:"required"`
+	Content     map[string]struct {
+		Schema *schema `yaml:"schema"`
+	} `yaml:"content"`
+}
+
+type schema struct {
+	Ref         string             `yaml:"$ref"`
+	Type        string             `yaml:"type"`
+	Format      string             `yaml:"format"`
+	Description string             `yaml:"description"`
+	Deprecated  bool               `yaml:"deprecated"`
+	Enum        []interface{}      `yaml:"enum"`
+	Items       *schema            `yaml:"items"`
+	Properties  map[string]*schema `yaml:"properties"`
+	Required    []string           `yaml:"required"`
+	Nullable    bool               `yaml:"nullable"`
+	MinItems    int                `yaml:"minItems"`
+	AllOf       []*schema          `yaml:"allOf"`
+	OneOf       []*schema          `yaml:"oneOf"`
+	AnyOf       []*schema          `yaml:"anyOf"`
+}
+
+// goStruct is a generated struct.
+type goStruct struct {
+	name   string
+	doc    string
+	fields []goField
+}
+
+// goField is a field of a generated struct, or a comment saying why a property was left out when
+// skipped is set.
+type goField struct {
+	name    string
+	goType  string
+	tags    []string
+	skipped string
+}
+
+// generator holds the state of one Generate call.
+type generator struct {
+	doc *document
+	// the generated structs, by name, and the names of those made from component schemas, by
+	// schema name.
+	structs    map[string]*goStruct
+	components map[string]string
+	inline     map[*schema]string
+	usesTime   bool
+}
+
+// Generate returns formatted Go source declaring the args structs of the operations in spec, an
+// OpenAPI 3 document in YAML or JSON.  Operations are named by their operationId, or failing that
+// by their method and path.
+func Generate(spec []byte, conf Config) ([]byte, error) {
+	if conf.Package == "" {
+		conf.Package = "api"
+	}
+	var doc document
+	if err := yaml.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("cannot read OpenAPI document: %v", err)
+	}
+	g := &generator{doc: &doc, structs: map[string]*goStruct{}, components: map[string]string{}, inline: map[*schema]string{}}
+	var args []string
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		item := doc.Paths[path]
+		for _, m := range []struct {
+			method string
+			op     *operation
+		}{
+			{"GET", item.Get}, {"PUT", item.Put}, {"POST", item.Post}, {"DELETE", item.Delete},
+			{"OPTIONS", item.Options}, {"HEAD", item.Head}, {"PATCH", item.Patch}, {"TRACE", item.Trace},
+		} {
+			if m.op == nil {
+				continue
+			}
+			name, err := g.operation(m.method, path, item.Parameters, m.op)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %v", m.method, path, err)
+			}
+			args = append(args, name)
+		}
+	}
+	return g.source(conf, args)
+}
+
+// operation generates the args struct of op, and returns its name.
+func (g *generator) operation(method, path string, shared []*parameter, op *operation) (string, error) {
+	var s *goStruct
+	if op.OperationID != "" {
+		s = g.newStruct(goName(op.OperationID) + "Args")
+		s.doc = fmt.Sprintf("%s are the args of the %s operation, %s %s.", s.name, op.OperationID, method, path)
+	} else {
+		s = g.newStruct(goName(strings.ToLower(method)+" "+strings.NewReplacer("{", "by ", "}", "").Replace(path)) + "Args")
+		s.doc = fmt.Sprintf("%s are the args of %s %s.", s.name, method, path)
+	}
+	if summary := oneLine(op.Summary); summary != "" {
+		s.doc += " " + strings.TrimSuffix(summary, ".") + "."
+	}
+
+	// the operation's parameters override the path item's ones of the same name and location.
+	params := map[string]*parameter{}
+	var order []string
+	for _, p := range append(append([]*parameter(nil), shared...), op.Parameters...) {
+		p, err := g.resolveParameter(p)
+		if err != nil {
+			return "", err
+		}
+		key := p.In + " " + p.Name
+		if _, ok := params[key]; !ok {
+			order = append(order, key)
+		}
+		params[key] = p
+	}
+	taken := map[string]bool{}
+	for _, key := range order {
+		p := params[key]
+		if p.In != "path" && p.In != "query" {
+			s.fields = append(s.fields, goField{name: goName(p.Name), skipped: p.In + " parameters are not args"})
+			continue
+		}
+		field, err := g.field(s.name, p.Name, p.Schema, p.Required || p.In == "path", p.Description, p.Deprecated)
+		if err != nil {
+			return "", fmt.Errorf("parameter %s: %v", p.Name, err)
+		}
+		taken[argName(p.Name)] = true
+		s.fields = append(s.fields, field)
+	}
+
+	if op.RequestBody != nil {
+		body, err := g.resolveRequestBody(op.RequestBody)
+		if err != nil {
+			return "", err
+		}
+		name := "input"
+		if taken[name] {
+			name = "body"
+		}
+		media, ok := body.Content["application/json"]
+		if !ok || media.Schema == nil {
+			s.fields = append(s.fields, goField{name: goName(name), skipped: "request bodies other than application/json are not args"})
+		} else {
+			field, err := g.field(s.name, name, media.Schema, body.Required, body.Description, false)
+			if err != nil {
+				return "", fmt.Errorf("request body: %v", err)
+			}
+			s.fields = append(s.fields, field)
+		}
+	}
+	return s.name, nil
+}
+
+// newStruct adds a struct named name, or name with a number after it if name is taken.
+func (g *generator) newStruct(name string) *goStruct {
+	unique := name
+	for i := 2; g.structs[unique] != nil; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	s := &goStruct{name: unique}
+	g.structs[unique] = s
+	return s
+}
+
+// field returns the field for the property or parameter name of the struct parent.
+func (g *generator) field(parent, name string, sch *schema, required bool, desc string, deprecated bool) (goField, error) {
+	f := goField{name: goName(name)}
+	if sch == nil {
+		f.skipped = "it has no schema"
+		return f, nil
+	}
+	resolved, err := g.resolveSchema(sch)
+	if err != nil {
+		return f, err
+	}
+	if desc == "" {
+		desc = resolved.Description
+	}
+	deprecated = deprecated || resolved.Deprecated
+	goType, tags, skipped, err := g.goType(parent+goName(name), sch, required)
+	if err != nil || skipped != "" {
+		f.skipped = skipped
+		return f, err
+	}
+	f.goType = goType
+	f.tags = append([]string{tag("arg", argName(name))}, tags...)
+	if required {
+		f.tags = append(f.tags, tag("required", "true"))
+	}
+	if desc = oneLine(desc); desc != "" {
+		f.tags = append(f.tags, tag("desc", desc))
+	}
+	if deprecated {
+		f.tags = append(f.tags, tag("deprecated", "deprecated in the API"))
+	}
+	return f, nil
+}
+
+// goType returns the Go type of values of sch, named for inline objects after name, with the tags
+// of the field holding them, or why there isn't one.
+func (g *generator) goType(name string, sch *schema, required bool) (goType string, tags []string, skipped string, err error) {
+	component := ""
+	if sch.Ref != "" {
+		component = strings.TrimPrefix(sch.Ref, "#/components/schemas/")
+	}
+	if sch, err = g.resolveSchema(sch); err != nil {
+		return "", nil, "", err
+	}
+	switch {
+	case len(sch.OneOf) > 0 || len(sch.AnyOf) > 0:
+		return "", nil, "oneOf and anyOf schemas have no Go type", nil
+	case len(sch.AllOf) > 0 || sch.Type == "object" || (sch.Type == "" && sch.Properties != nil):
+		merged, err := g.merge(sch)
+		if err != nil {
+			return "", nil, "", err
+		}
+		if len(merged.Properties) == 0 {
+			return "", nil, "free-form objects have no Go type", nil
+		}
+		structName, err := g.objectStruct(name, component, sch, merged)
+		if err != nil {
+			return "", nil, "", err
+		}
+		if !required {
+			// a pointer, so that recursive schemas work.
+			return "*" + structName, nil, "", nil
+		}
+		return structName, nil, "", nil
+	case sch.Type == "array":
+		if sch.Items == nil {
+			return "", nil, "arrays without items have no Go type", nil
+		}
+		elem, elemTags, skipped, err := g.goType(name+"Item", sch.Items, true)
+		if err != nil || skipped != "" {
+			return "", nil, skipped, err
+		}
+		// a oneof tag on a list restricts its items.
+		tags = elemTags
+		items, _ := g.resolveSchema(sch.Items)
+		if items != nil && !items.Nullable {
+			tags = append(tags, tag("elems", "nonnull"))
+		}
+		if sch.MinItems > 0 {
+			tags = append(tags, tag("minitems", strconv.Itoa(sch.MinItems)))
+		}
+		return "[]" + strings.TrimPrefix(elem, "*"), tags, "", nil
+	case sch.Type == "string":
+		if sch.Format == "date-time" {
+			g.usesTime = true
+			return "time.Time", nil, "", nil
+		}
+		if values, ok := enumValues(sch.Enum); ok {
+			tags = append(tags, tag("oneof", strings.Join(values, " ")))
+		}
+		return "string", tags, "", nil
+	case sch.Type == "integer":
+		return "int", nil, "", nil
+	case sch.Type == "number":
+		return "float64", nil, "", nil
+	case sch.Type == "boolean":
+		return "bool", nil, "", nil
+	}
+	return "", nil, fmt.Sprintf("%q schemas have no Go type", sch.Type), nil
+}
+
+// objectStruct returns the name of the struct for the object schema orig, with its allOf schemas
+// merged into sch, generating it if it hasn't been.  Component schemas are named after themselves,
+// and inline ones after name.
+func (g *generator) objectStruct(name, component string, orig, sch *schema) (string, error) {
+	if component != "" {
+		if structName, ok := g.components[component]; ok {
+			return structName, nil
+		}
+		name = component
+	} else if structName, ok := g.inline[orig]; ok {
+		// an inline schema reached twice, through an allOf.
+		return structName, nil
+	}
+	if !strings.HasSuffix(name, "Input") {
+		name += "Input"
+	}
+	s := g.newStruct(goName(name))
+	// registered before its fields are generated, so that recursive schemas end.
+	if component != "" {
+		g.components[component] = s.name
+		s.doc = fmt.Sprintf("%s is the %s schema.", s.name, component)
+	} else {
+		g.inline[orig] = s.name
+		s.doc = fmt.Sprintf("%s is an inline schema.", s.name)
+	}
+	if desc := oneLine(sch.Description); desc != "" {
+		s.doc += " " + desc
+	}
+	required := map[string]bool{}
+	for _, r := range sch.Required {
+		required[r] = true
+	}
+	props := make([]string, 0, len(sch.Properties))
+	for prop := range sch.Properties {
+		props = append(props, prop)
+	}
+	sort.Strings(props)
+	for _, prop := range props {
+		field, err := g.field(s.name, prop, sch.Properties[prop], required[prop], "", false)
+		if err != nil {
+			return "", fmt.Errorf("%s: %v", prop, err)
+		}
+		s.fields = append(s.fields, field)
+	}
+	return s.name, nil
+}
+
+// merge returns sch with the properties and required lists of its allOf schemas merged in.
+func (g *generator) merge(sch *schema) (*schema, error) {
+	if len(sch.AllOf) == 0 {
+		return sch, nil
+	}
+	out := &schema{Type: "object", Description: sch.Description, Properties: map[string]*schema{}}
+	for _, part := range append([]*schema{sch}, sch.AllOf...) {
+		part, err := g.resolveSchema(part)
+		if err != nil {
+			return nil, err
+		}
+		if part != sch {
+			if part, err = g.merge(part); err != nil {
+				return nil, err
+			}
+		}
+		for name, prop := range part.Properties {
+			out.Properties[name] = prop
+		}
+		out.Required = append(out.Required, part.Required...)
+	}
+	return out, nil
+}
+
+// enumValues returns the values of a string enum, if they can all be the values of a oneof tag,
+// which become graphql enum value names.
+func enumValues(enum []interface{}) ([]string, bool) {
+	if len(enum) == 0 {
+		return nil, false
+	}
+	values := make([]string, 0, len(enum))
+	for _, v := range enum {
+		s, ok := v.(string)
+		if !ok || !graphqlNamePattern.MatchString(strings.Replace(s, "-", "_", -1)) {
+			return nil, false
+		}
+		values = append(values, s)
+	}
+	return values, true
+}
+
+func (g *generator) resolveSchema(sch *schema) (*schema, error) {
+	for seen := 0; sch.Ref != ""; seen++ {
+		name, err := localRef(sch.Ref, "schemas")
+		if err != nil {
+			return nil, err
+		}
+		next, ok := g.doc.Components.Schemas[name]
+		if !ok || seen > len(g.doc.Components.Schemas) {
+			return nil, fmt.Errorf("cannot resolve %s", sch.Ref)
+		}
+		sch = next
+	}
+	return sch, nil
+}
+
+func (g *generator) resolveParameter(p *parameter) (*parameter, error) {
+	if p.Ref == "" {
+		return p, nil
+	}
+	name, err := localRef(p.Ref, "parameters")
+	if err != nil {
+		return nil, err
+	}
+	resolved, ok := g.doc.Components.Parameters[name]
+	if !ok || resolved.Ref != "" {
+		return nil, fmt.Errorf("cannot resolve %s", p.Ref)
+	}
+	return resolved, nil
+}
+
+func (g *generator) resolveRequestBody(b *requestBody) (*requestBody, error) {
+	if b.Ref == "" {
+		return b, nil
+	}
+	name, err := localRef(b.Ref, "requestBodies")
+	if err != nil {
+		return nil, err
+	}
+	resolved, ok := g.doc.Components.RequestBodies[name]
+	if !ok || resolved.Ref != "" {
+		return nil, fmt.Errorf("cannot resolve %s", b.Ref)
+	}
+	return resolved, nil
+}
+
+// localRef returns the name in a reference to the components of kind in the same document, like
+// "#/components/schemas/Pet".
+func localRef(ref, kind string) (string, error) {
+	prefix := "#/components/" + kind + "/"
+	if !strings.HasPrefix(ref, prefix) {
+		return "", fmt.Errorf("unsupported reference %s. only references to %s in the same document are supported", ref, prefix)
+	}
+	return strings.TrimPrefix(ref, prefix), nil
+}
+
+func (g *generator) source(conf Config, args []string) ([]byte, error) {
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by openapigen. DO NOT EDIT.\n\npackage %s\n\n", conf.Package)
+	if g.usesTime {
+		out.WriteString("import \"time\"\n\n")
+	}
+	out.WriteString("// OpenAPIArgs holds a value of each of the generated args structs, for ArgLoader.Precompile.\n")
+	out.WriteString("var OpenAPIArgs = []interface{}{\n")
+	for _, name := range args {
+		fmt.Fprintf(&out, "\t%s{},\n", name)
+	}
+	out.WriteString("}\n")
+
+	names := make([]string, 0, len(g.structs))
+	for name := range g.structs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s := g.structs[name]
+		out.WriteString("\n")
+		if s.doc != "" {
+			fmt.Fprintf(&out, "// %s\n", s.doc)
+		}
+		fmt.Fprintf(&out, "type %s struct {\n", s.name)
+		for _, f := range s.fields {
+			if f.skipped != "" {
+				fmt.Fprintf(&out, "\t// %s is left out: %s.\n", f.name, f.skipped)
+				continue
+			}
+			fmt.Fprintf(&out, "\t%s %s `%s`\n", f.name, f.goType, strings.Join(f.tags, " "))
+		}
+		out.WriteString("}\n")
+	}
+	formatted, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid code: %v\n%s", err, out.Bytes())
+	}
+	return formatted, nil
+}
+
+// tag returns a struct tag key and value, with backticks, which can't appear in the raw string
+// literal holding the tags, replaced by quotes.
+func tag(key, value string) string {
+	return key + ":" + strconv.Quote(strings.Replace(value, "`", "'", -1))
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+var graphqlNamePattern = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// argName returns name if it's a valid graphql name, and name in camelCase if not.
+func argName(name string) string {
+	if graphqlNamePattern.MatchString(name) {
+		return name
+	}
+	words := splitWords(name)
+	for i, w := range words {
+		if i == 0 {
+			words[i] = strings.ToLower(w)
+		} else {
+			words[i] = strings.Title(strings.ToLower(w))
+		}
+	}
+	out := strings.Join(words, "")
+	if out == "" || unicode.IsDigit(rune(out[0])) {
+		out = "_" + out
+	}
+	return out
+}
+
+// initialisms are the words goName writes in upper case, as golint would have them.
+var initialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true,
+	"SQL": true, "URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+// goName returns name as an exported Go identifier, as in "PetID" for "pet_id" or "petId".
+func goName(name string) string {
+	var b strings.Builder
+	for _, w := range splitWords(name) {
+		if upper := strings.ToUpper(w); initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	out := b.String()
+	if out == "" || unicode.IsDigit(rune(out[0])) {
+		out = "X" + out
+	}
+	return out
+}
+
+// splitWords splits name at characters other than letters and digits, and where lower case
+// letters or digits are followed by upper case ones.
+func splitWords(name string) []string {
+	var words []string
+	var word []rune
+	runes := []rune(name)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) || r > unicode.MaxASCII {
+			if len(word) > 0 {
+				words, word = append(words, string(word)), nil
+			}
+			continue
+		}
+		if len(word) > 0 && unicode.IsUpper(r) && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])) {
+			words, word = append(words, string(word)), nil
+		}
+		word = append(word, r)
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+	return words
+}
@@ -0,0 +1,81 @@
+package graphqlhelpers
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// optional is implemented by pointers to Optional and Null, which LoadArgs fills in itself rather
+// than loading as input objects.
+type optional interface {
+	// valueType returns the type of the value held.
+	valueType() reflect.Type
+	// setLoaded records that the arg was given, with a value of valueType or, if v isn't valid, as
+	// null.
+	setLoaded(v reflect.Value)
+	// value returns the value held, and whether there is one.
+	value() (reflect.Value, bool)
+	// given reports whether the arg was given, as a value or, for Optional, as null.
+	given() bool
+}
+
+var optionalType = reflect.TypeOf((*optional)(nil)).Elem()
+
+// optionalValueType returns the type held by t if t is an Optional or Null type.
+func optionalValueType(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() != reflect.Struct || !reflect.PtrTo(t).Implements(optionalType) {
+		return nil, false
+	}
+	return reflect.New(t).Interface().(optional).valueType(), true
+}
+
+type requestVariablesKey struct{}
+
+// WithRequestVariables returns a context holding vars, the variables of a request as the client
+// sent them, before graphql-go coerces them.  Give it to graphql.Do with the same variables for
+// Optional args to be loaded as null when they're passed a variable the request holds a null for.
+// graphql-go's own variables hold nil for null variables and missing ones alike, and without the
+// request's variables, both count as omitted, so that partial updates never clear a value the
+// client didn't mention.  NewHandler does this for every request.
+func WithRequestVariables(ctx context.Context, vars map[string]interface{}) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, requestVariablesKey{}, vars)
+}
+
+// explicitNulls returns p's args, plus a nil for each arg that was passed a variable whose value in
+// the request, from WithRequestVariables, is null.  graphql-go leaves null args out of p.Args, as
+// if they had been omitted, and Optional args need to tell the two apart.
+func explicitNulls(p graphql.ResolveParams) map[string]interface{} {
+	vars, _ := contextValue(p.Context, requestVariablesKey{}).(map[string]interface{})
+	if len(p.Info.FieldASTs) == 0 || vars == nil {
+		return p.Args
+	}
+	args := p.Args
+	copied := false
+	for _, arg := range p.Info.FieldASTs[0].Arguments {
+		variable, ok := arg.Value.(*ast.Variable)
+		if !ok || arg.Name == nil || variable.Name == nil {
+			continue
+		}
+		if _, given := args[arg.Name.Value]; given {
+			continue
+		}
+		if v, ok := vars[variable.Name.Value]; !ok || v != nil {
+			continue
+		}
+		if !copied {
+			args = make(map[string]interface{}, len(p.Args)+1)
+			for k, v := range p.Args {
+				args[k] = v
+			}
+			copied = true
+		}
+		args[arg.Name.Value] = nil
+	}
+	return args
+}
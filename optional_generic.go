@@ -0,0 +1,89 @@
+//go:build go1.18
+
+package graphqlhelpers
+
+import "reflect"
+
+// Optional is an arg that tells an omitted value apart from an explicit null, for mutations that
+// make partial updates:
+//
+//	type UpdateUserArgs struct {
+//		ID       string                   `arg:"id" required:"true"`
+//		Nickname Optional[string]         `arg:"nickname"`
+//		Manager  Optional[ManagerInput]   `arg:"manager"`
+//	}
+//
+// Optional[T] has T's graphql type, always nullable.  When the arg is omitted Set is false; when it's
+// null Set and Null are true; and when it has a value Set is true and Value holds it.
+//
+// graphql-go drops null values before resolvers see them, so LoadArgs finds nulls in the request
+// instead: an arg passed a variable that the request's variables hold a null for is null, while
+// one passed a variable the request leaves out is omitted.  The request's variables come from
+// WithRequestVariables, which NewHandler calls; without them, every variable that graphql-go drops
+// reads as omitted.  Nulls inside input objects can't be found this way either, and read as
+// omitted.
+type Optional[T any] struct {
+	Value T
+	// Set is whether the arg was given, as a value or as null.
+	Set bool
+	// Null is whether the arg was given as null.
+	Null bool
+}
+
+// Get returns the arg's value, and whether it has one: whether it was given and wasn't null.
+func (o Optional[T]) Get() (T, bool) {
+	return o.Value, o.Set && !o.Null
+}
+
+func (o *Optional[T]) valueType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (o *Optional[T]) setLoaded(v reflect.Value) {
+	o.Set = true
+	if !v.IsValid() {
+		o.Null = true
+		return
+	}
+	reflect.ValueOf(&o.Value).Elem().Set(v)
+}
+
+func (o *Optional[T]) value() (reflect.Value, bool) {
+	return reflect.ValueOf(&o.Value).Elem(), o.Set && !o.Null
+}
+
+func (o *Optional[T]) given() bool {
+	return o.Set
+}
+
+// Null is a nullable arg, like a pointer but without the allocation or the nil checks.  Valid is
+// false if the arg was null or omitted, which Null doesn't tell apart, and true if Value holds its
+// value.
+type Null[T any] struct {
+	Value T
+	Valid bool
+}
+
+// Get returns the arg's value, and whether it has one.
+func (n Null[T]) Get() (T, bool) {
+	return n.Value, n.Valid
+}
+
+func (n *Null[T]) valueType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (n *Null[T]) setLoaded(v reflect.Value) {
+	if v.IsValid() {
+		reflect.ValueOf(&n.Value).Elem().Set(v)
+		n.Valid = true
+	}
+}
+
+func (n *Null[T]) value() (reflect.Value, bool) {
+	return reflect.ValueOf(&n.Value).Elem(), n.Valid
+}
+
+func (n *Null[T]) given() bool {
+	return n.Valid
+}
@@ -0,0 +1,97 @@
+// Package oteltracing adds OpenTelemetry spans around the argument loading and resolvers of a
+// graphqlhelpers ArgLoader.
+//
+//	oteltracing.Install(loader, nil)
+//
+// Spans carry the field name, parent type, return type and argument count.  Argument values are
+// never recorded, since they may hold secrets.
+package oteltracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	graphqlhelpers "github.com/btubbs/graphql-go-helpers"
+)
+
+// InstrumentationName is the name of the tracer used when none is given.
+const InstrumentationName = "github.com/btubbs/graphql-go-helpers"
+
+// Install adds a resolver span middleware and an argument loading hook to l.  Install it before
+// building fields, since middleware only applies to resolvers built afterwards.  If tracer is nil,
+// the global tracer provider's tracer is used.
+func Install(l *graphqlhelpers.ArgLoader, tracer trace.Tracer) {
+	l.Use(Middleware(tracer))
+	l.OnLoad(LoadHook(tracer))
+}
+
+// Middleware returns resolver middleware that runs each resolver in a "graphql.resolve" span.  The
+// span's context is passed on to the resolver, so spans it starts are children.
+func Middleware(tracer trace.Tracer) graphqlhelpers.Middleware {
+	tracer = orDefault(tracer)
+	return func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (interface{}, error) {
+			ctx, span := tracer.Start(contextOf(p), "graphql.resolve "+graphqlhelpers.FieldPath(p.Info),
+				trace.WithAttributes(fieldAttributes(p)...))
+			defer span.End()
+			p.Context = ctx
+			result, err := next(p)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			return result, err
+		}
+	}
+}
+
+// LoadHook returns a hook that records each LoadArgs call as a "graphql.load_args" span.
+func LoadHook(tracer trace.Tracer) graphqlhelpers.LoadHook {
+	tracer = orDefault(tracer)
+	return func(p graphql.ResolveParams, target interface{}) func(error) {
+		attrs := append(fieldAttributes(p), attribute.String("graphql.args.type", fmt.Sprintf("%T", target)))
+		_, span := tracer.Start(contextOf(p), "graphql.load_args "+graphqlhelpers.FieldPath(p.Info),
+			trace.WithAttributes(attrs...))
+		return func(err error) {
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			span.End()
+		}
+	}
+}
+
+func fieldAttributes(p graphql.ResolveParams) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.String("graphql.field.name", p.Info.FieldName),
+		attribute.Int("graphql.args.count", len(p.Args)),
+	}
+	if p.Info.ParentType != nil {
+		attrs = append(attrs, attribute.String("graphql.field.parent_type", p.Info.ParentType.Name()))
+	}
+	if p.Info.ReturnType != nil {
+		attrs = append(attrs, attribute.String("graphql.field.type", p.Info.ReturnType.String()))
+	}
+	return attrs
+}
+
+func contextOf(p graphql.ResolveParams) context.Context {
+	if p.Context == nil {
+		return context.Background()
+	}
+	return p.Context
+}
+
+func orDefault(tracer trace.Tracer) trace.Tracer {
+	if tracer == nil {
+		return otel.Tracer(InstrumentationName)
+	}
+	return tracer
+}
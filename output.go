@@ -0,0 +1,186 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/graphql-go/graphql"
+)
+
+// RegisterOutput registers gqlType as the graphql output type for values of i's type, for types
+// that OutputType can't generate itself.
+func (e *ArgLoader) RegisterOutput(i interface{}, gqlType graphql.Output) error {
+	t := reflect.TypeOf(i)
+	if _, alreadyRegistered := e.outputTypes[t]; alreadyRegistered {
+		return fmt.Errorf("an output type has already been registered for the %v type", t)
+	}
+	e.outputTypes[t] = gqlType
+	return nil
+}
+
+// OutputType returns the graphql output type for values of i's type.  Registered output types are
+// used as-is, as are registered arg types whose graphql type is a scalar or enum.  Slices become
+// lists, pointers become their element type, and other structs become objects with a field for
+// each field-tagged struct field.  Generated objects are cached, so a struct always maps to the
+// same object.
+func (e *ArgLoader) OutputType(i interface{}) (graphql.Output, error) {
+	return e.outputType(reflect.TypeOf(i))
+}
+
+func (e *ArgLoader) outputType(t reflect.Type) (graphql.Output, error) {
+	if gqlType, ok := e.outputTypes[t]; ok {
+		return gqlType, nil
+	}
+	if gqlType, ok := e.gqlTypes[t]; ok {
+		switch gqlType.(type) {
+		case *graphql.Scalar, *graphql.Enum:
+			return gqlType, nil
+		}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return e.outputType(t.Elem())
+	case reflect.Slice:
+		elemType, err := e.outputType(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return graphql.NewList(elemType), nil
+	case reflect.Struct:
+		return e.object(t)
+	}
+	return nil, fmt.Errorf("no output type found for type %v", t)
+}
+
+// object generates a graphql object for a struct type.  As with input objects, the object is cached
+// before its fields are generated so that self-referencing structs work.
+func (e *ArgLoader) object(t reflect.Type) (*graphql.Object, error) {
+	if obj, ok := e.objects[t]; ok {
+		return obj, nil
+	}
+	name, err := e.objectName(t)
+	if err != nil {
+		return nil, err
+	}
+	var fields graphql.Fields
+	var obj *graphql.Object
+	obj = graphql.NewObject(graphql.ObjectConfig{
+		Name: name,
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			if len(concurrentFields(t)) > 0 {
+				return prefetchingFields(e.interfaceFields(t, fields))
+			}
+			return e.interfaceFields(t, fields)
+		}),
+		Interfaces: graphql.InterfacesThunk(func() []*graphql.Interface {
+			return e.interfacesOf(t, obj)
+		}),
+	})
+	e.objects[t] = obj
+	e.typeNames[name] = obj
+
+	generated, err := e.objectFields(t)
+	if err == nil {
+		err = e.setOutputCosts(name, t)
+	}
+	if err != nil {
+		delete(e.objects, t)
+		delete(e.typeNames, name)
+		return nil, err
+	}
+	fields = generated
+	e.setHidden(name, t)
+	return obj, nil
+}
+
+// objectFields generates the graphql fields for the field-tagged fields of struct type t.
+func (e *ArgLoader) objectFields(t reflect.Type) (graphql.Fields, error) {
+	generated := graphql.Fields{}
+	for _, f := range outputFields(t) {
+		fieldType, err := lazyType(f.field)
+		if err != nil {
+			return nil, fmt.Errorf("cannot configure %s.%s: %v", t.Name(), f.field.Name, err)
+		}
+		if fieldType == nil {
+			fieldType = f.field.Type
+		}
+		gqlType, err := e.outputType(fieldType)
+		if err != nil {
+			return nil, fmt.Errorf("cannot configure %s.%s: %v", t.Name(), f.field.Name, err)
+		}
+		resolve, err := computeResolver(t, f.field)
+		if err != nil {
+			return nil, fmt.Errorf("cannot configure %s.%s: %v", t.Name(), f.field.Name, err)
+		}
+		if resolve == nil {
+			resolve = structFieldResolver(t, f.index)
+			if fieldType != f.field.Type {
+				resolve = lazyResolver(resolve)
+			}
+		}
+		resolve = e.concurrentResolver(fieldType, e.valuerResolver(fieldType, e.flagResolver(fieldType, resolve)))
+		if visibility, ok := f.field.Tag.Lookup(visibilityTag); ok {
+			resolve = e.visibilityResolver(visibility, resolve)
+		}
+		generated[f.name] = &graphql.Field{
+			Type:              gqlType,
+			Description:       e.description(t, f.index, f.field),
+			DeprecationReason: f.field.Tag.Get(deprecatedTag),
+			Resolve:           resolve,
+		}
+	}
+	if len(generated) == 0 {
+		return nil, fmt.Errorf("%v has no fields with a %q tag", t, fieldTag)
+	}
+	return generated, nil
+}
+
+// objectName picks the graphql name for the object generated from t, with the same collision rules
+// as input objects.
+func (e *ArgLoader) objectName(t reflect.Type) (string, error) {
+	if t.Name() == "" {
+		return "", fmt.Errorf("cannot generate an object for anonymous struct %v. declare a named type for it", t)
+	}
+	name := t.Name()
+	if _, taken := e.typeNames[name]; !taken {
+		return name, nil
+	}
+	qualified := qualifiedName(t, name)
+	if _, taken := e.typeNames[qualified]; !e.qualifyNames || taken {
+		return "", fmt.Errorf("cannot generate object %s for %v: the name is already used by %s. "+
+			"rename the struct, or call QualifyDuplicateNames(true) to prefix duplicates with their package name",
+			name, t, e.typeDescription(name))
+	}
+	return qualified, nil
+}
+
+// structFieldResolver returns a resolver reading the field at index from a source value of type t
+// (or a pointer to one).
+func structFieldResolver(t reflect.Type, index []int) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		v := reflect.ValueOf(p.Source)
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return nil, nil
+			}
+			v = v.Elem()
+		}
+		if !v.IsValid() {
+			return nil, nil
+		}
+		if v.Type() != t {
+			return nil, fmt.Errorf("cannot resolve %s: expected a %v source, got %v", p.Info.FieldName, t, v.Type())
+		}
+		return v.FieldByIndex(index).Interface(), nil
+	}
+}
+
+// OutputType returns the graphql output type for values of i's type using the default loader.
+func OutputType(i interface{}) (graphql.Output, error) {
+	return Default().OutputType(i)
+}
+
+// RegisterOutput registers the output type for values of i's type on the default loader.
+func RegisterOutput(i interface{}, gqlType graphql.Output) error {
+	return Default().RegisterOutput(i, gqlType)
+}
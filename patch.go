@@ -0,0 +1,176 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// columnTag names the column that Patch sets from an Optional or Null arg field, as in
+// `column:"nick_name"`.  Fields without one set the column named after their arg.
+const columnTag = "column"
+
+// Patch returns the column values set by args, a loaded args struct (or pointer to one) with
+// Optional fields, for PATCH-style mutations updating only what the client gave:
+//
+//	var args UpdateUserArgs
+//	if err := loader.LoadArgs(p, &args); err != nil {
+//		return nil, err
+//	}
+//	changes, err := loader.Patch(args)
+//	...
+//	db.Model(&user).Updates(changes)
+//
+// Each Optional field that was given sets its column to its value, or to nil if it was null, and
+// each Null field with a value sets its column to the value.  Omitted Optional and Null fields, and
+// fields of other types, are left out.
+func (e *ArgLoader) Patch(args interface{}) (map[string]interface{}, error) {
+	v, err := patchArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]interface{}{}
+	for _, f := range e.argFields(v.Type()) {
+		opt, ok := optionalField(v, f)
+		if !ok || !opt.given() {
+			continue
+		}
+		column := f.name
+		if c, ok := f.field.Tag.Lookup(columnTag); ok {
+			column = c
+		}
+		if val, ok := opt.value(); ok {
+			out[column] = val.Interface()
+		} else {
+			out[column] = nil
+		}
+	}
+	return out, nil
+}
+
+// ApplyPatch sets the fields of model, a pointer to a struct, that args sets: each Optional field
+// of args that was given, and each Null field with a value, sets the model field of the same name to
+// its value, or to its zero value (nil, for pointers) if it was null.  A model field may be of the
+// arg's type, a type it converts to without loss, such as a named type of the same kind or a wider
+// integer, or a pointer to one of those.  ApplyPatch returns an error, and leaves model alone, if a
+// field can't be applied.
+func (e *ArgLoader) ApplyPatch(args interface{}, model interface{}) error {
+	v, err := patchArgs(args)
+	if err != nil {
+		return err
+	}
+	m := reflect.ValueOf(model)
+	if m.Kind() != reflect.Ptr || m.IsNil() || m.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%v is not a pointer to a struct", reflect.TypeOf(model))
+	}
+	m = m.Elem()
+	type change struct {
+		field reflect.Value
+		value reflect.Value
+	}
+	var changes []change
+	for _, f := range e.argFields(v.Type()) {
+		opt, ok := optionalField(v, f)
+		if !ok || !opt.given() {
+			continue
+		}
+		target := m.FieldByName(f.field.Name)
+		if !target.IsValid() || !target.CanSet() {
+			return fmt.Errorf("%v has no exported field %s to apply %s to", m.Type(), f.field.Name, f.name)
+		}
+		val, ok := opt.value()
+		if !ok {
+			changes = append(changes, change{target, reflect.Zero(target.Type())})
+			continue
+		}
+		converted, ok := patchValue(val, target.Type())
+		if !ok {
+			return fmt.Errorf("cannot apply %s, a %v, to %v.%s, a %v",
+				f.name, val.Type(), m.Type(), f.field.Name, target.Type())
+		}
+		changes = append(changes, change{target, converted})
+	}
+	for _, c := range changes {
+		c.field.Set(c.value)
+	}
+	return nil
+}
+
+func patchArgs(args interface{}) (reflect.Value, error) {
+	v := reflect.ValueOf(args)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("%v is not a struct", reflect.TypeOf(args))
+	}
+	return v, nil
+}
+
+// optionalField returns the Optional or Null field f of the args struct v, if it is one.
+func optionalField(v reflect.Value, f argField) (optional, bool) {
+	if _, ok := optionalValueType(f.field.Type); !ok {
+		return nil, false
+	}
+	fv := v.FieldByIndex(f.index)
+	// copy the field, so that unaddressable args structs work too.
+	ptr := reflect.New(fv.Type())
+	ptr.Elem().Set(fv)
+	return ptr.Interface().(optional), true
+}
+
+// patchValue returns val as a value of type t, converting it, or pointing to it, if need be.  Only
+// conversions that can't lose anything are made, so that an int isn't made into the string of a
+// rune, a float isn't truncated and an int64 doesn't wrap in an int8.
+func patchValue(val reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	switch {
+	case val.Type().AssignableTo(t):
+		return val, true
+	case lossless(val.Type(), t) && val.Type().ConvertibleTo(t):
+		return val.Convert(t), true
+	case t.Kind() == reflect.Ptr:
+		elem, ok := patchValue(val, t.Elem())
+		if !ok {
+			return reflect.Value{}, false
+		}
+		ptr := reflect.New(t.Elem())
+		ptr.Elem().Set(elem)
+		return ptr, true
+	}
+	return reflect.Value{}, false
+}
+
+// lossless reports whether every value of type from converts to type to and back unchanged: when
+// they're of the same kind, or to is an integer or float type wider than from.
+func lossless(from, to reflect.Type) bool {
+	switch {
+	case from.Kind() == to.Kind():
+		return true
+	case isSigned(from.Kind()) && isSigned(to.Kind()),
+		isUnsigned(from.Kind()) && isUnsigned(to.Kind()),
+		from.Kind() == reflect.Float32 && to.Kind() == reflect.Float64:
+		return to.Bits() >= from.Bits()
+	case isUnsigned(from.Kind()) && isSigned(to.Kind()):
+		return to.Bits() > from.Bits()
+	}
+	return false
+}
+
+func isSigned(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUnsigned(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
+
+// Patch returns the column values set by an args struct with Optional fields, using the default
+// loader.
+func Patch(args interface{}) (map[string]interface{}, error) {
+	return Default().Patch(args)
+}
+
+// ApplyPatch sets the fields of model that an args struct with Optional fields sets, using the
+// default loader.
+func ApplyPatch(args interface{}, model interface{}) error {
+	return Default().ApplyPatch(args, model)
+}
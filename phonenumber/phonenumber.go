@@ -0,0 +1,99 @@
+// Package phonenumber adds a PhoneNumber scalar to graphqlhelpers, for args and results of type
+// Number.  Numbers are validated and normalized to E.164 with libphonenumber's metadata, so
+// "(415) 555-2671" in the US and "+1 415 555 2671" both load as "+14155552671":
+//
+//	err := phonenumber.Register(loader, "US")
+//
+//	type SendCodeArgs struct {
+//		Phone phonenumber.Number `arg:"phone" required:"true"`
+//	}
+package phonenumber
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/nyaruka/phonenumbers"
+
+	graphqlhelpers "github.com/btubbs/graphql-go-helpers"
+)
+
+// Number is a phone number in E.164 format, like "+14155552671".
+type Number string
+
+// Region returns the ISO 3166 code of the region the number belongs to, like "US", or "" if it
+// can't be determined.
+func (n Number) Region() string {
+	parsed, err := phonenumbers.Parse(string(n), "")
+	if err != nil {
+		return ""
+	}
+	return phonenumbers.GetRegionCodeForNumber(parsed)
+}
+
+// Scalar is the PhoneNumber scalar.  It serializes Numbers as strings, and passes string inputs
+// through for the loader func to validate.
+var Scalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "PhoneNumber",
+	Description: "A phone number, normalized to E.164 format, like +14155552671.",
+	Serialize:   serialize,
+	ParseValue: func(v interface{}) interface{} {
+		if s, ok := v.(string); ok {
+			return s
+		}
+		return nil
+	},
+	ParseLiteral: func(v ast.Value) interface{} {
+		if s, ok := v.(*ast.StringValue); ok {
+			return s.Value
+		}
+		return nil
+	},
+})
+
+func serialize(v interface{}) interface{} {
+	switch n := v.(type) {
+	case Number:
+		return string(n)
+	case *Number:
+		if n == nil {
+			return nil
+		}
+		return string(*n)
+	case string:
+		return n
+	}
+	return nil
+}
+
+// Parse validates s and returns it in E.164 format.  Numbers without a leading + and country code
+// are parsed as numbers in defaultRegion, an ISO 3166 code like "US".  If defaultRegion is "", they
+// are rejected.
+func Parse(s, defaultRegion string) (Number, error) {
+	parsed, err := phonenumbers.Parse(s, defaultRegion)
+	if err != nil {
+		return "", fmt.Errorf("%s is not a phone number: %v", s, err)
+	}
+	if !phonenumbers.IsValidNumber(parsed) {
+		return "", fmt.Errorf("%s is not a valid phone number", s)
+	}
+	return Number(phonenumbers.Format(parsed, phonenumbers.E164)), nil
+}
+
+// Loader returns a loader func for Number args, parsing them with Parse and defaultRegion.
+func Loader(defaultRegion string) func(interface{}) (Number, error) {
+	return func(i interface{}) (Number, error) {
+		s, ok := i.(string)
+		if !ok {
+			return "", fmt.Errorf("%v is not a string", i)
+		}
+		return Parse(s, defaultRegion)
+	}
+}
+
+// Register registers Number on loader with the PhoneNumber scalar, loading args with
+// Loader(defaultRegion).  Results of type Number then use the scalar too.
+func Register(loader *graphqlhelpers.ArgLoader, defaultRegion string) error {
+	return loader.Register(Loader(defaultRegion), Scalar)
+}
@@ -0,0 +1,251 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"unsafe"
+)
+
+// loadPlan is what loadStruct needs to know about a struct type, worked out from its tags the first
+// time the type is loaded and cached, so later loads don't parse them again.
+type loadPlan struct {
+	// every arg field, as argFields returns them.
+	args   []argField
+	fields []fieldPlan
+	rest   *argField
+	// the arg names, aliases included, matched by fields, for the rest field.
+	matched map[string]bool
+	// whether loaded values of the type, or their embedded structs, might have Validate methods.
+	validates bool
+	// whether any field has a ratelimit tag.
+	rateLimited bool
+}
+
+// fieldPlan is the plan for loading one arg field.
+type fieldPlan struct {
+	argField
+	list       listSpec
+	required   bool
+	transforms []Transform
+	oneof      []string
+	directives []*Directive
+	// whether the field is an Optional or Null, which is loaded from a null arg rather than left
+	// alone.
+	optional bool
+
+	// for fields whose values are set as they are, without being converted or checked, holding
+	// one of the built-in scalar types loaded by the base loader funcs, and not inside an embedded
+	// pointer: the field's offset in the struct, and the setter storing a raw value there.  This
+	// avoids the reflection of loadValue in the common case.
+	offset uintptr
+	set    scalarSetter
+}
+
+// scalarSetter stores raw in the field at ptr, and returns false if raw isn't of the field's type,
+// leaving the field alone.
+type scalarSetter func(ptr unsafe.Pointer, raw interface{}) bool
+
+var scalarSetters = map[reflect.Type]scalarSetter{
+	reflect.TypeOf(""): func(ptr unsafe.Pointer, raw interface{}) bool {
+		v, ok := raw.(string)
+		if ok {
+			*(*string)(ptr) = v
+		}
+		return ok
+	},
+	reflect.TypeOf(0): func(ptr unsafe.Pointer, raw interface{}) bool {
+		v, ok := raw.(int)
+		if ok {
+			*(*int)(ptr) = v
+		}
+		return ok
+	},
+	reflect.TypeOf(0.0): func(ptr unsafe.Pointer, raw interface{}) bool {
+		v, ok := raw.(float64)
+		if ok {
+			*(*float64)(ptr) = v
+		}
+		return ok
+	},
+	reflect.TypeOf(false): func(ptr unsafe.Pointer, raw interface{}) bool {
+		v, ok := raw.(bool)
+		if ok {
+			*(*bool)(ptr) = v
+		}
+		return ok
+	},
+}
+
+// plan returns the load plan of struct type t, building it if it isn't cached.  Plans that fail to
+// build aren't cached, so the error is reported every time.
+func (e *ArgLoader) plan(t reflect.Type) (*loadPlan, error) {
+	if p, ok := e.plans.Load(t); ok {
+		return p.(*loadPlan), nil
+	}
+	p, err := e.buildPlan(t)
+	if err != nil {
+		return nil, err
+	}
+	e.plans.Store(t, p)
+	return p, nil
+}
+
+func (e *ArgLoader) buildPlan(t reflect.Type) (*loadPlan, error) {
+	fields := e.argFields(t)
+	p := &loadPlan{args: fields, matched: map[string]bool{}, validates: mayValidate(t)}
+	for idx, f := range fields {
+		if _, ok := f.field.Tag.Lookup(rateLimitTag); ok {
+			p.rateLimited = true
+		}
+		p.matched[f.name] = true
+		for _, alias := range f.aliases {
+			p.matched[alias] = true
+		}
+		if f.rest {
+			if f.field.Type != restType {
+				return nil, fmt.Errorf("%s must be a map[string]interface{} to use the rest option", f.field.Name)
+			}
+			p.rest = &fields[idx]
+			continue
+		}
+		fp := fieldPlan{argField: f}
+		var err error
+		_, ok := optionalValueType(f.field.Type)
+		fp.optional = ok
+		if fp.list, err = listTags(f.field); err != nil {
+			return nil, err
+		}
+		if requiredVal, ok := f.field.Tag.Lookup(requiredTag); ok {
+			if fp.required, err = strconv.ParseBool(requiredVal); err != nil {
+				return nil, fmt.Errorf("%s is not a valid 'required' tag value", requiredVal)
+			}
+		}
+		if fp.transforms, err = e.fieldTransforms(f.field); err != nil {
+			return nil, err
+		}
+		if fp.oneof, err = oneofValues(f.field); err != nil {
+			return nil, err
+		}
+		if fp.directives, err = e.fieldDirectives(f.field); err != nil {
+			return nil, err
+		}
+		if e.builtinLoaders[f.field.Type] && len(fp.transforms) == 0 && fp.oneof == nil && fp.directives == nil &&
+			len(fp.list.nonNullElems) == 0 && fp.list.minItems == 0 {
+			fp.offset, ok = fieldOffset(t, f.index)
+			if ok {
+				fp.set = scalarSetters[f.field.Type]
+			}
+		}
+		p.fields = append(p.fields, fp)
+	}
+	return p, nil
+}
+
+// fieldOffset returns the offset in struct type t of the field at index, unless the field is inside
+// an embedded pointer.
+func fieldOffset(t reflect.Type, index []int) (uintptr, bool) {
+	var offset uintptr
+	for _, i := range index {
+		if t.Kind() != reflect.Struct {
+			return 0, false
+		}
+		field := t.Field(i)
+		offset += field.Offset
+		t = field.Type
+	}
+	return offset, true
+}
+
+// mayValidate reports whether validate might find a Validate method on values of struct type t.
+func mayValidate(t reflect.Type) bool {
+	if reflect.PtrTo(t).Implements(validatorType) {
+		return true
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if _, tagged := field.Tag.Lookup(argTag); !tagged && field.Anonymous && field.Type.Kind() == reflect.Struct {
+			return true
+		}
+	}
+	return false
+}
+
+// planArgFields returns argFields(t), from t's load plan if it has one.
+func (e *ArgLoader) planArgFields(t reflect.Type) []argField {
+	if p, err := e.plan(t); err == nil {
+		return p.args
+	}
+	return e.argFields(t)
+}
+
+// resetPlans drops the cached load plans, when a setting they depend on changes.
+func (e *ArgLoader) resetPlans() {
+	e.plans.Range(func(t, _ interface{}) bool {
+		e.plans.Delete(t)
+		return true
+	})
+}
+
+// Precompile builds and caches what the loader needs to load each of the args structs (or pointers
+// to them) in types, and the input objects they're configured with, including those of nested
+// structs.  Call it at startup with every args struct, to report bad tags and other configuration
+// errors before serving, and so the first requests don't pay for the reflection.  ArgsConfig and
+// LoadArgs work without it, building the same things the first time they need them.
+func (e *ArgLoader) Precompile(types ...interface{}) error {
+	seen := map[reflect.Type]bool{}
+	for _, i := range types {
+		t, err := argsStructType(i)
+		if err != nil {
+			return err
+		}
+		if _, err := e.SafeArgsConfig(reflect.New(t).Interface()); err != nil {
+			return fmt.Errorf("cannot precompile %v: %v", t, err)
+		}
+		if err := e.precompile(t, seen); err != nil {
+			return fmt.Errorf("cannot precompile %v: %v", t, err)
+		}
+	}
+	return nil
+}
+
+// precompile builds the load plan of struct type t and the structs it holds.  seen holds the types
+// already done, so recursive types end.
+func (e *ArgLoader) precompile(t reflect.Type, seen map[reflect.Type]bool) error {
+	if seen[t] {
+		return nil
+	}
+	seen[t] = true
+	p, err := e.plan(t)
+	if err != nil {
+		return err
+	}
+	for _, f := range p.fields {
+		ft := f.field.Type
+		for {
+			if _, registered := e.loaderFuncs[ft]; registered {
+				break
+			}
+			switch ft.Kind() {
+			case reflect.Ptr, reflect.Slice, reflect.Array:
+				ft = ft.Elem()
+				continue
+			case reflect.Struct:
+				if vt, ok := optionalValueType(ft); ok {
+					ft = vt
+					continue
+				}
+				if err := e.precompile(ft, seen); err != nil {
+					return err
+				}
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// Precompile builds and caches what the default loader needs to load each of types.
+func Precompile(types ...interface{}) error {
+	return Default().Precompile(types...)
+}
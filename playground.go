@@ -0,0 +1,58 @@
+package graphqlhelpers
+
+import (
+	"html/template"
+	"net/http"
+	"strings"
+)
+
+// WithPlayground serves a GraphiQL UI at path, for exploring the schema during development.  The UI
+// is served to browsers opening path, and sends its queries to path too, so the handler must also
+// receive GraphQL requests there; mounting it at path itself is simplest:
+//
+//	http.Handle("/graphql", NewHandler(schema, WithPlayground("/graphql")))
+//
+// The page is embedded in the handler, but loads GraphiQL's scripts and styles from a CDN, so the
+// browser needs internet access.
+func WithPlayground(path string) HandlerOption {
+	return func(h *handler) {
+		h.playground = path
+	}
+}
+
+// servesPlayground reports whether r is a browser opening the playground.
+func (h *handler) servesPlayground(r *http.Request) bool {
+	return h.playground != "" &&
+		r.Method == http.MethodGet &&
+		r.URL.Path == h.playground &&
+		r.URL.Query().Get("query") == "" &&
+		strings.Contains(r.Header.Get("Accept"), "text/html")
+}
+
+func (h *handler) servePlayground(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	playgroundPage.Execute(w, h.playground)
+}
+
+var playgroundPage = template.Must(template.New("playground").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>GraphiQL</title>
+  <style>body { margin: 0; height: 100vh; } #graphiql { height: 100vh; }</style>
+  <link rel="stylesheet" href="https://unpkg.com/graphiql@3/graphiql.min.css">
+  <script crossorigin src="https://unpkg.com/react@18/umd/react.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/react-dom@18/umd/react-dom.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/graphiql@3/graphiql.min.js"></script>
+</head>
+<body>
+  <div id="graphiql">Loading…</div>
+  <script>
+    const fetcher = GraphiQL.createFetcher({ url: {{.}} });
+    ReactDOM.createRoot(document.getElementById('graphiql')).render(
+      React.createElement(GraphiQL, { fetcher: fetcher }),
+    );
+  </script>
+</body>
+</html>
+`))
@@ -0,0 +1,47 @@
+package graphqlhelpers
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Resetter may be implemented by pooled args structs that need to do more than be zeroed when
+// they're released, like returning buffers to a pool of their own.  Reset must leave every arg
+// field at its zero value, since LoadArgs leaves args that weren't passed as they are.
+type Resetter interface {
+	Reset()
+}
+
+// argsPools holds a *sync.Pool of pointers to each pooled args struct type.
+var argsPools sync.Map
+
+func argsPool(t reflect.Type) *sync.Pool {
+	if p, ok := argsPools.Load(t); ok {
+		return p.(*sync.Pool)
+	}
+	p, _ := argsPools.LoadOrStore(t, &sync.Pool{New: func() interface{} {
+		return reflect.New(t).Interface()
+	}})
+	return p.(*sync.Pool)
+}
+
+// releaseArgs resets ptr, a pointer to an args struct taken from its pool, and puts it back.
+func releaseArgs(ptr interface{}) {
+	if r, ok := ptr.(Resetter); ok {
+		r.Reset()
+	} else {
+		v := reflect.ValueOf(ptr).Elem()
+		v.Set(reflect.Zero(v.Type()))
+	}
+	argsPool(reflect.TypeOf(ptr).Elem()).Put(ptr)
+}
+
+// WithPooledArgs reuses the field's args structs between calls, taking them from a pool instead of
+// allocating a new one for every call, and putting them back once the resolver func returns.  It
+// suits very hot fields.  The resolver func mustn't keep its args, or anything pointing into them,
+// after it returns.
+func WithPooledArgs() FieldOption {
+	return func(o *fieldOptions) {
+		o.pooledArgs = true
+	}
+}
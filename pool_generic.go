@@ -0,0 +1,27 @@
+//go:build go1.18
+
+package graphqlhelpers
+
+import "reflect"
+
+// AcquireArgs returns a zeroed *T from a pool of them, for loading args into in hot resolvers
+// without allocating:
+//
+//	args := graphqlhelpers.AcquireArgs[SearchArgs]()
+//	defer graphqlhelpers.ReleaseArgs(args)
+//	if err := loader.LoadArgs(p, args); err != nil {
+//		return nil, err
+//	}
+//
+// The pool is shared with fields built with WithPooledArgs.
+func AcquireArgs[T any]() *T {
+	return argsPool(reflect.TypeOf((*T)(nil)).Elem()).Get().(*T)
+}
+
+// ReleaseArgs puts a, taken from AcquireArgs, back in its pool, after calling its Reset method if
+// it's a Resetter, or zeroing it if not.  Nothing may use a after it's released.
+func ReleaseArgs[T any](a *T) {
+	if a != nil {
+		releaseArgs(a)
+	}
+}
@@ -0,0 +1,91 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimeRange is a reusable pair of start/end arguments.  Either end may be omitted to describe an
+// open-ended range.  Embed it in an args struct to add top-level start and end arguments, or give
+// it an arg tag to accept it as a nested input object (or a list of them).  Like every time.Time
+// arg, start and end are RFC3339 Strings, or DateTimes after UseDateTime.
+type TimeRange struct {
+	Start time.Time `arg:"start" desc:"Start of the range (inclusive). Omit for no lower bound."`
+	End   time.Time `arg:"end" desc:"End of the range (exclusive). Must be after start. Omit for no upper bound."`
+}
+
+// Validate checks that End is after Start when both are set.
+func (r TimeRange) Validate() error {
+	if !r.Start.IsZero() && !r.End.IsZero() && !r.End.After(r.Start) {
+		return fmt.Errorf("end (%s) must be after start (%s)",
+			r.End.Format(time.RFC3339), r.Start.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// Contains reports whether t falls within the range.  Unset bounds are treated as unbounded.
+func (r TimeRange) Contains(t time.Time) bool {
+	if !r.Start.IsZero() && t.Before(r.Start) {
+		return false
+	}
+	if !r.End.IsZero() && !t.Before(r.End) {
+		return false
+	}
+	return true
+}
+
+// PageLimits controls how PageArgs are defaulted and capped.
+type PageLimits struct {
+	// Default is the limit used when the client does not provide one.
+	Default int
+	// Max is the largest limit a client may request.
+	Max int
+}
+
+// DefaultPageLimits are applied when loading PageArgs, and given in the description of their limit
+// arg.  Services may override them at startup, before configuring args.
+var DefaultPageLimits = PageLimits{Default: 20, Max: 100}
+
+// PageArgs is a reusable pair of limit/offset pagination arguments.  Embed it in the args struct of
+// any list field.  A limit of 0, like an omitted one, means the default limit, since the two can't
+// be told apart.
+type PageArgs struct {
+	Limit  int `arg:"limit"`
+	Offset int `arg:"offset" desc:"Number of items to skip before returning results. Must not be negative."`
+}
+
+// Descriptions describes the limit arg with DefaultPageLimits, as they are when the args are
+// configured.
+func (PageArgs) Descriptions() map[string]string {
+	limit := "Maximum number of items to return."
+	if DefaultPageLimits.Max > 0 {
+		limit += fmt.Sprintf(" Must not be negative or greater than %d.", DefaultPageLimits.Max)
+	} else {
+		limit += " Must not be negative."
+	}
+	limit += fmt.Sprintf(" Omit it, or pass 0, for the default of %d.", DefaultPageLimits.Default)
+	return map[string]string{"Limit": limit}
+}
+
+// Validate applies the default limit if none was given and enforces DefaultPageLimits.
+func (p *PageArgs) Validate() error {
+	return p.ApplyLimits(DefaultPageLimits)
+}
+
+// ApplyLimits applies the default limit from l if none was given, then checks p against l.  It is
+// useful for fields that need limits other than DefaultPageLimits.
+func (p *PageArgs) ApplyLimits(l PageLimits) error {
+	if p.Limit == 0 {
+		p.Limit = l.Default
+	}
+	if p.Limit < 0 {
+		return fmt.Errorf("limit must not be negative, got %d", p.Limit)
+	}
+	if l.Max > 0 && p.Limit > l.Max {
+		return fmt.Errorf("limit must not be greater than %d, got %d", l.Max, p.Limit)
+	}
+	if p.Offset < 0 {
+		return fmt.Errorf("offset must not be negative, got %d", p.Offset)
+	}
+	return nil
+}
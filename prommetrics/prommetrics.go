@@ -0,0 +1,66 @@
+// Package prommetrics reports graphqlhelpers metrics to Prometheus.
+//
+//	m := prommetrics.New("myapp")
+//	prometheus.MustRegister(m)
+//	loader.Instrument(m)
+package prommetrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics implements graphqlhelpers.Metrics with a counter of load errors, labeled by field and
+// arg, and a histogram of resolver durations in seconds, labeled by field and status ("ok" or
+// "error").  It is also a prometheus.Collector, so it can be registered directly.
+type Metrics struct {
+	LoadErrors *prometheus.CounterVec
+	Durations  *prometheus.HistogramVec
+}
+
+// New returns Metrics named under namespace, as <namespace>_graphql_arg_load_errors_total and
+// <namespace>_graphql_resolver_duration_seconds.
+func New(namespace string) *Metrics {
+	return &Metrics{
+		LoadErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "graphql",
+			Name:      "arg_load_errors_total",
+			Help:      "Number of failures loading graphql arguments, by field and arg.",
+		}, []string{"field", "arg"}),
+		Durations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "graphql",
+			Name:      "resolver_duration_seconds",
+			Help:      "Time taken by graphql resolvers, by field and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"field", "status"}),
+	}
+}
+
+// LoadError counts a failure loading args for field.
+func (m *Metrics) LoadError(field, arg string) {
+	m.LoadErrors.WithLabelValues(field, arg).Inc()
+}
+
+// ResolverDuration observes the duration of a resolver for field.
+func (m *Metrics) ResolverDuration(field string, d time.Duration, failed bool) {
+	status := "ok"
+	if failed {
+		status = "error"
+	}
+	m.Durations.WithLabelValues(field, status).Observe(d.Seconds())
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.LoadErrors.Describe(ch)
+	m.Durations.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.LoadErrors.Collect(ch)
+	m.Durations.Collect(ch)
+}
@@ -0,0 +1,170 @@
+package protoargs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/graphql-go/graphql"
+	"google.golang.org/protobuf/proto"
+
+	graphqlhelpers "github.com/btubbs/graphql-go-helpers"
+)
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	messageType = reflect.TypeOf((*proto.Message)(nil)).Elem()
+)
+
+// GRPCField builds a graphql.Field calling method, a gRPC client method like client.GetUser, of the
+// form
+//
+//	func(ctx context.Context, req *Request, opts ...grpc.CallOption) (*Response, error)
+//
+// (or the same without opts, like a server's method).  The field's Args come from ArgsConfig(Request)
+// and its Type from OutputType(Response), and its resolver loads the args into a new Request and
+// calls method with the resolver's context.  Errors with a gRPC status are returned as
+// *StatusErrors.  The resolver is wrapped in mw, inside graphqlhelpers.RecordErrors.
+func (l *Loader) GRPCField(method interface{}, mw ...graphqlhelpers.Middleware) (*graphql.Field, error) {
+	fn := reflect.ValueOf(method)
+	t := fn.Type()
+	if t.Kind() != reflect.Func {
+		return nil, fmt.Errorf("%v is not a func", method)
+	}
+	in := t.NumIn()
+	if t.IsVariadic() {
+		in--
+	}
+	if in != 2 || t.In(0) != contextType || !isMessagePtr(t.In(1)) ||
+		t.NumOut() != 2 || !isMessagePtr(t.Out(0)) || t.Out(1) != errorType {
+		return nil, fmt.Errorf("method should be a func(context.Context, *Request, ...grpc.CallOption) (*Response, error), not %v", t)
+	}
+	reqType := t.In(1).Elem()
+	args, err := l.SafeArgsConfig(reflect.Zero(t.In(1)).Interface().(proto.Message))
+	if err != nil {
+		return nil, err
+	}
+	obj, err := l.OutputType(reflect.Zero(t.Out(0)).Interface().(proto.Message))
+	if err != nil {
+		return nil, err
+	}
+	resolve := func(p graphql.ResolveParams) (interface{}, error) {
+		ctx := p.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		req := reflect.New(reqType)
+		if err := l.LoadArgs(p, req.Interface().(proto.Message)); err != nil {
+			return nil, err
+		}
+		out := fn.Call([]reflect.Value{reflect.ValueOf(ctx), req})
+		if !out[1].IsNil() {
+			return nil, statusError(out[1].Interface().(error))
+		}
+		if out[0].IsNil() {
+			return nil, nil
+		}
+		return out[0].Interface(), nil
+	}
+	return &graphql.Field{
+		Type:    obj,
+		Args:    args,
+		Resolve: graphqlhelpers.Chain(append([]graphqlhelpers.Middleware{graphqlhelpers.RecordErrors}, mw...)...)(resolve),
+	}, nil
+}
+
+func isMessagePtr(t reflect.Type) bool {
+	return t.Kind() == reflect.Ptr && t.Implements(messageType)
+}
+
+// grpcCodes are the names of the gRPC status codes, by number, with the GraphQL error code each
+// is reported as.
+var grpcCodes = []struct{ name, graphqlCode string }{
+	{"OK", ""},
+	{"CANCELLED", "CANCELLED"},
+	{"UNKNOWN", "INTERNAL_SERVER_ERROR"},
+	{"INVALID_ARGUMENT", "BAD_USER_INPUT"},
+	{"DEADLINE_EXCEEDED", "TIMEOUT"},
+	{"NOT_FOUND", "NOT_FOUND"},
+	{"ALREADY_EXISTS", "CONFLICT"},
+	{"PERMISSION_DENIED", "FORBIDDEN"},
+	{"RESOURCE_EXHAUSTED", "RATE_LIMITED"},
+	{"FAILED_PRECONDITION", "FAILED_PRECONDITION"},
+	{"ABORTED", "CONFLICT"},
+	{"OUT_OF_RANGE", "BAD_USER_INPUT"},
+	{"UNIMPLEMENTED", "NOT_IMPLEMENTED"},
+	{"INTERNAL", "INTERNAL_SERVER_ERROR"},
+	{"UNAVAILABLE", "SERVICE_UNAVAILABLE"},
+	{"DATA_LOSS", "INTERNAL_SERVER_ERROR"},
+	{"UNAUTHENTICATED", "UNAUTHENTICATED"},
+}
+
+// StatusError is the error a GRPCField's resolver fails with when its call returns an error with a
+// gRPC status.  It's an ErrorExtender, so graphqlhelpers.DefaultErrorFormatter reports it with a
+// code extension, like NOT_FOUND or BAD_USER_INPUT, and the gRPC code as the grpcCode extension.
+// Statuses that report server failures, like INTERNAL, have their messages replaced, so they
+// don't leak details of the service.
+type StatusError struct {
+	// Code is the name of the gRPC status code, like "NOT_FOUND".
+	Code string
+	// GraphQLCode is the code the error is reported with.
+	GraphQLCode string
+	Message     string
+	// Err is the error the call returned.
+	Err error
+}
+
+func (e *StatusError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the error the call returned.
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorExtensions returns the code and grpcCode extensions.
+func (e *StatusError) ErrorExtensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.GraphQLCode, "grpcCode": e.Code}
+}
+
+// statusError returns err as a *StatusError, if it has a gRPC status, or as it is if not.
+func statusError(err error) error {
+	code, msg, ok := grpcStatus(err)
+	if !ok || code == 0 || int(code) >= len(grpcCodes) {
+		return err
+	}
+	c := grpcCodes[code]
+	if c.graphqlCode == "INTERNAL_SERVER_ERROR" {
+		msg = "internal error"
+	}
+	return &StatusError{Code: c.name, GraphQLCode: c.graphqlCode, Message: msg, Err: err}
+}
+
+// grpcStatus returns the code and message of the gRPC status of err, or of an error it wraps.  The
+// status is found through its GRPCStatus method, as grpc-go's status.FromError does, but by
+// reflection, so that this package doesn't depend on grpc-go.
+func grpcStatus(err error) (uint32, string, bool) {
+	for ; err != nil; err = errors.Unwrap(err) {
+		method := reflect.ValueOf(err).MethodByName("GRPCStatus")
+		if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+			continue
+		}
+		status := method.Call(nil)[0]
+		if status.Kind() == reflect.Ptr && status.IsNil() {
+			continue
+		}
+		code, message := status.MethodByName("Code"), status.MethodByName("Message")
+		if !code.IsValid() || !message.IsValid() || code.Type().NumIn() != 0 || message.Type().NumIn() != 0 {
+			continue
+		}
+		c, m := code.Call(nil)[0], message.Call(nil)[0]
+		if c.Kind() != reflect.Uint32 || m.Kind() != reflect.String {
+			continue
+		}
+		return uint32(c.Uint()), m.String(), true
+	}
+	return 0, "", false
+}
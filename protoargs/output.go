@@ -0,0 +1,174 @@
+package protoargs
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// OutputType returns the object generated for msg's message type, with a field for each of the
+// message's fields, typed as for args.  Fields that always have a value, like proto3 scalars and
+// repeated fields, are non-null.  The object's fields resolve from sources that are messages of
+// that type.
+func (l *Loader) OutputType(msg proto.Message) (*graphql.Object, error) {
+	return l.object(msg.ProtoReflect().Descriptor())
+}
+
+// object generates the object for a message type.  As with input objects, it's cached before its
+// fields are generated, so recursive messages work.
+func (l *Loader) object(md protoreflect.MessageDescriptor) (*graphql.Object, error) {
+	if obj, ok := l.objects[md.FullName()]; ok {
+		return obj, nil
+	}
+	name, err := l.typeName(md, "")
+	if err != nil {
+		return nil, err
+	}
+	var fields graphql.Fields
+	obj := graphql.NewObject(graphql.ObjectConfig{
+		Name:        name,
+		Description: comments(md),
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return fields
+		}),
+	})
+	l.objects[md.FullName()] = obj
+	l.names[name] = md.FullName()
+
+	generated := graphql.Fields{}
+	fds := md.Fields()
+	for i := 0; i < fds.Len(); i++ {
+		fd := fds.Get(i)
+		t, err := l.outputFieldType(fd)
+		if err != nil {
+			delete(l.objects, md.FullName())
+			delete(l.names, name)
+			return nil, fmt.Errorf("cannot configure %s: %v", fd.FullName(), err)
+		}
+		generated[fd.JSONName()] = &graphql.Field{Type: t, Description: comments(fd), Resolve: fieldResolver(fd)}
+	}
+	fields = generated
+	return obj, nil
+}
+
+// outputFieldType returns the graphql type of the object field for fd.
+func (l *Loader) outputFieldType(fd protoreflect.FieldDescriptor) (graphql.Output, error) {
+	var t graphql.Output
+	var err error
+	switch {
+	case fd.IsMap():
+		t, err = l.object(fd.Message())
+	case fd.Kind() == protoreflect.EnumKind:
+		t, err = l.enum(fd.Enum())
+	case fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind:
+		if known, ok := wellKnownTypes[fd.Message().FullName()]; ok {
+			t = known
+		} else {
+			t, err = l.object(fd.Message())
+		}
+	default:
+		t, err = scalarType(fd.Kind())
+	}
+	if err != nil {
+		return nil, err
+	}
+	if fd.IsList() || fd.IsMap() {
+		t = graphql.NewList(graphql.NewNonNull(t))
+	}
+	if !fd.HasPresence() {
+		t = graphql.NewNonNull(t)
+	}
+	return t, nil
+}
+
+// fieldResolver returns the resolver of the object field for fd, reading it from a source message.
+// Map entries are resolved as map[string]interface{}s of their key and value.
+func fieldResolver(fd protoreflect.FieldDescriptor) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		var m protoreflect.Message
+		switch src := p.Source.(type) {
+		case map[string]interface{}:
+			return src[fd.JSONName()], nil
+		case proto.Message:
+			m = src.ProtoReflect()
+		case protoreflect.Message:
+			m = src
+		}
+		if m == nil || m.Descriptor().FullName() != fd.ContainingMessage().FullName() {
+			return nil, fmt.Errorf("cannot resolve %s: expected a %s source, got %T",
+				p.Info.FieldName, fd.ContainingMessage().FullName(), p.Source)
+		}
+		if fd.HasPresence() && !m.Has(fd) {
+			return nil, nil
+		}
+		return outputValue(fd, m.Get(fd)), nil
+	}
+}
+
+// outputValue converts v, the value of fd, to what its graphql type resolves from.
+func outputValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) interface{} {
+	switch {
+	case fd.IsMap():
+		var entries []map[string]interface{}
+		v.Map().Range(func(k protoreflect.MapKey, value protoreflect.Value) bool {
+			entries = append(entries, map[string]interface{}{
+				"key":   singularValue(fd.MapKey(), k.Value()),
+				"value": singularValue(fd.MapValue(), value),
+			})
+			return true
+		})
+		// maps have no order, so sort the entries to resolve them the same way every time.
+		sort.Slice(entries, func(i, j int) bool {
+			return fmt.Sprint(entries[i]["key"]) < fmt.Sprint(entries[j]["key"])
+		})
+		out := make([]interface{}, len(entries))
+		for i, entry := range entries {
+			out[i] = entry
+		}
+		return out
+	case fd.IsList():
+		list := v.List()
+		out := make([]interface{}, list.Len())
+		for i := range out {
+			out[i] = singularValue(fd, list.Get(i))
+		}
+		return out
+	}
+	return singularValue(fd, v)
+}
+
+func singularValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) interface{} {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		m := v.Message()
+		md := m.Descriptor()
+		switch name := md.FullName(); {
+		case name == timestampName:
+			fields := md.Fields()
+			return time.Unix(m.Get(fields.ByName("seconds")).Int(), m.Get(fields.ByName("nanos")).Int()).UTC()
+		case wellKnownTypes[name] != nil:
+			valueField := md.Fields().ByName("value")
+			return singularValue(valueField, m.Get(valueField))
+		}
+		return m
+	case protoreflect.EnumKind:
+		return v.Enum()
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return int(v.Int())
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return int(v.Uint())
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return strconv.FormatInt(v.Int(), 10)
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return strconv.FormatUint(v.Uint(), 10)
+	case protoreflect.BytesKind:
+		return base64.StdEncoding.EncodeToString(v.Bytes())
+	}
+	return v.Interface()
+}
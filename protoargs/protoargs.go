@@ -0,0 +1,470 @@
+// Package protoargs configures and loads graphql args from protobuf messages, for services whose
+// internal APIs are defined in proto: a field's args come from the fields of its request message,
+// and LoadArgs fills in a message from them, so resolvers can pass it straight to the API.
+//
+// Proto fields become args named by their JSON names, as in protojson.  Their types follow the
+// proto3 JSON mapping: 64-bit integers are Strings, bytes are base64 Strings, enums become graphql
+// enums with the same value names, and messages become input objects.  Repeated fields are lists,
+// and maps are lists of key/value entries.  google.protobuf.Timestamp is a DateTime, and the
+// wrapper types, like google.protobuf.StringValue, are their scalars.
+//
+// Messages can be results too, with OutputType, and GRPCField puts both together to expose a gRPC
+// method as a field.
+package protoargs
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	graphqlhelpers "github.com/btubbs/graphql-go-helpers"
+)
+
+// Loader generates graphql args from protobuf messages and loads them back.  Generated objects,
+// input objects and enums are cached, so a message or enum always maps to the same graphql type.  Build
+// a Loader's types before serving; LoadArgs may then be called concurrently.
+type Loader struct {
+	inputObjects map[protoreflect.FullName]*graphql.InputObject
+	objects      map[protoreflect.FullName]*graphql.Object
+	enums        map[protoreflect.FullName]*graphql.Enum
+	// the proto types the generated graphql types are named for, by graphql name.
+	names map[string]protoreflect.FullName
+}
+
+// New returns a Loader.
+func New() *Loader {
+	return &Loader{
+		inputObjects: map[protoreflect.FullName]*graphql.InputObject{},
+		objects:      map[protoreflect.FullName]*graphql.Object{},
+		enums:        map[protoreflect.FullName]*graphql.Enum{},
+		names:        map[string]protoreflect.FullName{},
+	}
+}
+
+// ArgsConfig returns the args config for msg's fields, to use as the Args of a graphql.Field.  It
+// panics if a field can't be configured.
+func (l *Loader) ArgsConfig(msg proto.Message) graphql.FieldConfigArgument {
+	conf, err := l.SafeArgsConfig(msg)
+	if err != nil {
+		panic(fmt.Sprintf("could not configure arguments: %v", err))
+	}
+	return conf
+}
+
+// SafeArgsConfig is like ArgsConfig, but returns an error instead of panicking.
+func (l *Loader) SafeArgsConfig(msg proto.Message) (graphql.FieldConfigArgument, error) {
+	desc := msg.ProtoReflect().Descriptor()
+	out := graphql.FieldConfigArgument{}
+	fields := desc.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		t, err := l.fieldType(fd)
+		if err != nil {
+			return nil, fmt.Errorf("cannot configure %s: %v", fd.FullName(), err)
+		}
+		out[fd.JSONName()] = &graphql.ArgumentConfig{Type: t, Description: comments(fd)}
+	}
+	return out, nil
+}
+
+// InputType returns the input object generated for msg's message type, for args whose type is
+// written by hand.
+func (l *Loader) InputType(msg proto.Message) (*graphql.InputObject, error) {
+	return l.inputObject(msg.ProtoReflect().Descriptor())
+}
+
+// fieldType returns the graphql type of the arg for fd.
+func (l *Loader) fieldType(fd protoreflect.FieldDescriptor) (graphql.Input, error) {
+	if fd.IsMap() {
+		entry, err := l.inputObject(fd.Message())
+		if err != nil {
+			return nil, err
+		}
+		return graphql.NewList(graphql.NewNonNull(entry)), nil
+	}
+	t, err := l.singularType(fd)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case fd.IsList():
+		// proto lists can't hold nulls.
+		return graphql.NewList(graphql.NewNonNull(t)), nil
+	case fd.Cardinality() == protoreflect.Required:
+		return graphql.NewNonNull(t), nil
+	}
+	return t, nil
+}
+
+// singularType returns the graphql type of one value of fd.
+func (l *Loader) singularType(fd protoreflect.FieldDescriptor) (graphql.Input, error) {
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		return l.enum(fd.Enum())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if t, ok := wellKnownTypes[fd.Message().FullName()]; ok {
+			return t, nil
+		}
+		return l.inputObject(fd.Message())
+	}
+	return scalarType(fd.Kind())
+}
+
+func scalarType(k protoreflect.Kind) (graphql.Input, error) {
+	switch k {
+	case protoreflect.BoolKind:
+		return graphql.Boolean, nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return graphql.Int, nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// graphql's Int is 32 bits, so 64-bit integers are strings, as in JSON.
+		return graphql.String, nil
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return graphql.Float, nil
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		return graphql.String, nil
+	}
+	return nil, fmt.Errorf("%v fields are not supported", k)
+}
+
+const timestampName = "google.protobuf.Timestamp"
+
+// wellKnownTypes are the graphql types of the well-known message types loaded as scalars.  The
+// wrappers load into their value field.
+var wellKnownTypes = map[protoreflect.FullName]graphql.Input{
+	timestampName:                 graphql.DateTime,
+	"google.protobuf.StringValue": graphql.String,
+	"google.protobuf.BytesValue":  graphql.String,
+	"google.protobuf.BoolValue":   graphql.Boolean,
+	"google.protobuf.Int32Value":  graphql.Int,
+	"google.protobuf.UInt32Value": graphql.Int,
+	"google.protobuf.Int64Value":  graphql.String,
+	"google.protobuf.UInt64Value": graphql.String,
+	"google.protobuf.FloatValue":  graphql.Float,
+	"google.protobuf.DoubleValue": graphql.Float,
+}
+
+// inputObject generates the input object for a message type.  As in graphqlhelpers, the object is
+// cached before its fields are generated, and gets them through a thunk, so recursive messages
+// work.
+func (l *Loader) inputObject(md protoreflect.MessageDescriptor) (*graphql.InputObject, error) {
+	if obj, ok := l.inputObjects[md.FullName()]; ok {
+		return obj, nil
+	}
+	name, err := l.typeName(md, "Input")
+	if err != nil {
+		return nil, err
+	}
+	var fields graphql.InputObjectConfigFieldMap
+	obj := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        name,
+		Description: comments(md),
+		Fields: graphql.InputObjectConfigFieldMapThunk(func() graphql.InputObjectConfigFieldMap {
+			return fields
+		}),
+	})
+	l.inputObjects[md.FullName()] = obj
+	l.names[name] = md.FullName()
+
+	generated := graphql.InputObjectConfigFieldMap{}
+	fds := md.Fields()
+	for i := 0; i < fds.Len(); i++ {
+		fd := fds.Get(i)
+		t, err := l.fieldType(fd)
+		if err != nil {
+			delete(l.inputObjects, md.FullName())
+			delete(l.names, name)
+			return nil, fmt.Errorf("cannot configure %s: %v", fd.FullName(), err)
+		}
+		generated[fd.JSONName()] = &graphql.InputObjectFieldConfig{Type: t, Description: comments(fd)}
+	}
+	fields = generated
+	return obj, nil
+}
+
+// enum generates the graphql enum for a proto enum, with a value for each of the enum's values.
+func (l *Loader) enum(ed protoreflect.EnumDescriptor) (*graphql.Enum, error) {
+	if enum, ok := l.enums[ed.FullName()]; ok {
+		return enum, nil
+	}
+	name, err := l.typeName(ed, "")
+	if err != nil {
+		return nil, err
+	}
+	values := graphql.EnumValueConfigMap{}
+	vds := ed.Values()
+	for i := 0; i < vds.Len(); i++ {
+		vd := vds.Get(i)
+		values[string(vd.Name())] = &graphql.EnumValueConfig{Value: vd.Number(), Description: comments(vd)}
+	}
+	enum := graphql.NewEnum(graphql.EnumConfig{Name: name, Description: comments(ed), Values: values})
+	l.enums[ed.FullName()] = enum
+	l.names[name] = ed.FullName()
+	return enum, nil
+}
+
+// typeName names the graphql type generated for d: its name, after the names of the messages it's
+// nested in, plus suffix, as in "UserAddressInput" for a User.Address message.
+func (l *Loader) typeName(d protoreflect.Descriptor, suffix string) (string, error) {
+	parts := []string{string(d.Name())}
+	for p := d.Parent(); p != nil; p = p.Parent() {
+		if _, ok := p.(protoreflect.MessageDescriptor); !ok {
+			break
+		}
+		parts = append([]string{string(p.Name())}, parts...)
+	}
+	name := strings.Join(parts, "") + suffix
+	if other, taken := l.names[name]; taken {
+		return "", fmt.Errorf("cannot generate %s for %s: the name is already used by %s", name, d.FullName(), other)
+	}
+	return name, nil
+}
+
+// comments returns the leading comments of d in its .proto file, if they were kept.
+func comments(d protoreflect.Descriptor) string {
+	return strings.TrimSpace(d.ParentFile().SourceLocations().ByDescriptor(d).LeadingComments)
+}
+
+// LoadArgs sets the fields of msg from p's args, which were configured with ArgsConfig(msg).  Args
+// that weren't given leave their fields alone.  It returns a *graphqlhelpers.ArgError if an arg
+// can't be loaded, or more than one member of a oneof is given.
+func (l *Loader) LoadArgs(p graphql.ResolveParams, msg proto.Message) error {
+	err := loadMessage(p.Args, msg.ProtoReflect())
+	if err == nil {
+		return nil
+	}
+	var path graphqlhelpers.ArgPath
+	for inner := err; ; {
+		le, ok := inner.(*loadError)
+		if !ok {
+			break
+		}
+		path, inner = append(path, le.key), le.err
+	}
+	argErr := &graphqlhelpers.ArgError{Err: err}
+	argErr.Arg, _ = path[0].(string)
+	if len(path) > 1 {
+		argErr.Path = path[1:]
+	}
+	return argErr
+}
+
+// loadError is an error loading the field or list item at key, a field name or an index.
+type loadError struct {
+	key interface{}
+	err error
+}
+
+func (e *loadError) Error() string {
+	if idx, ok := e.key.(int); ok {
+		return fmt.Sprintf("item %d: %v", idx, e.err)
+	}
+	return fmt.Sprintf("cannot load %s: %v", e.key, e.err)
+}
+
+// Unwrap returns the error loading the value.
+func (e *loadError) Unwrap() error {
+	return e.err
+}
+
+func loadMessage(args map[string]interface{}, m protoreflect.Message) error {
+	fds := m.Descriptor().Fields()
+	oneofs := map[protoreflect.FullName]string{}
+	for i := 0; i < fds.Len(); i++ {
+		fd := fds.Get(i)
+		name := fd.JSONName()
+		raw, ok := args[name]
+		if !ok || raw == nil {
+			if fd.Cardinality() == protoreflect.Required {
+				return &loadError{key: name, err: fmt.Errorf("%s is required", name)}
+			}
+			continue
+		}
+		if oneof := fd.ContainingOneof(); oneof != nil && !oneof.IsSynthetic() {
+			if other, taken := oneofs[oneof.FullName()]; taken {
+				return &loadError{key: name, err: fmt.Errorf("only one of %s and %s may be given", other, name)}
+			}
+			oneofs[oneof.FullName()] = name
+		}
+		if err := loadField(m, fd, raw); err != nil {
+			return &loadError{key: name, err: err}
+		}
+	}
+	return nil
+}
+
+func loadField(m protoreflect.Message, fd protoreflect.FieldDescriptor, raw interface{}) error {
+	switch {
+	case fd.IsMap():
+		entries, ok := raw.([]interface{})
+		if !ok {
+			return fmt.Errorf("%v is not a list", raw)
+		}
+		mm := m.Mutable(fd).Map()
+		for idx, item := range entries {
+			entry, _ := item.(map[string]interface{})
+			key, err := scalarValue(fd.MapKey(), entry["key"])
+			if err == nil {
+				var value protoreflect.Value
+				if value, err = loadValue(fd.MapValue(), entry["value"], mm.NewValue); err == nil {
+					mm.Set(key.MapKey(), value)
+				}
+			}
+			if err != nil {
+				return &loadError{key: idx, err: err}
+			}
+		}
+		return nil
+	case fd.IsList():
+		items, ok := raw.([]interface{})
+		if !ok {
+			return fmt.Errorf("%v is not a list", raw)
+		}
+		list := m.Mutable(fd).List()
+		for idx, item := range items {
+			v, err := loadValue(fd, item, list.NewElement)
+			if err != nil {
+				return &loadError{key: idx, err: err}
+			}
+			list.Append(v)
+		}
+		return nil
+	}
+	v, err := loadValue(fd, raw, func() protoreflect.Value { return m.NewField(fd) })
+	if err != nil {
+		return err
+	}
+	m.Set(fd, v)
+	return nil
+}
+
+// loadValue loads one value of fd from raw, using newMessage to make messages.
+func loadValue(fd protoreflect.FieldDescriptor, raw interface{}, newMessage func() protoreflect.Value) (protoreflect.Value, error) {
+	if raw == nil {
+		return protoreflect.Value{}, fmt.Errorf("value cannot be null")
+	}
+	if fd.Kind() != protoreflect.MessageKind && fd.Kind() != protoreflect.GroupKind {
+		return scalarValue(fd, raw)
+	}
+	v := newMessage()
+	m := v.Message()
+	md := m.Descriptor()
+	switch name := md.FullName(); {
+	case name == timestampName:
+		t, ok := raw.(time.Time)
+		if !ok {
+			s, _ := raw.(string)
+			var err error
+			if t, err = time.Parse(time.RFC3339Nano, s); err != nil {
+				return protoreflect.Value{}, fmt.Errorf("%v is not a RFC3339 timestamp", raw)
+			}
+		}
+		m.Set(md.Fields().ByName("seconds"), protoreflect.ValueOfInt64(t.Unix()))
+		m.Set(md.Fields().ByName("nanos"), protoreflect.ValueOfInt32(int32(t.Nanosecond())))
+	case wellKnownTypes[name] != nil:
+		valueField := md.Fields().ByName("value")
+		inner, err := scalarValue(valueField, raw)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		m.Set(valueField, inner)
+	default:
+		args, ok := raw.(map[string]interface{})
+		if !ok {
+			return protoreflect.Value{}, fmt.Errorf("%v is not an input object", raw)
+		}
+		if err := loadMessage(args, m); err != nil {
+			return protoreflect.Value{}, err
+		}
+	}
+	return v, nil
+}
+
+// scalarValue loads a value of fd, which isn't a message, from raw.
+func scalarValue(fd protoreflect.FieldDescriptor, raw interface{}) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		if b, ok := raw.(bool); ok {
+			return protoreflect.ValueOfBool(b), nil
+		}
+		return protoreflect.Value{}, fmt.Errorf("%v is not a bool", raw)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		n, ok := raw.(int)
+		if !ok || n < math.MinInt32 || n > math.MaxInt32 {
+			return protoreflect.Value{}, fmt.Errorf("%v is not a 32-bit int", raw)
+		}
+		return protoreflect.ValueOfInt32(int32(n)), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		n, ok := raw.(int)
+		if !ok || n < 0 || n > math.MaxUint32 {
+			return protoreflect.Value{}, fmt.Errorf("%v is not an unsigned 32-bit int", raw)
+		}
+		return protoreflect.ValueOfUint32(uint32(n)), nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		if n, ok := raw.(int); ok {
+			return protoreflect.ValueOfInt64(int64(n)), nil
+		}
+		s, _ := raw.(string)
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("%v is not a 64-bit int", raw)
+		}
+		return protoreflect.ValueOfInt64(n), nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		if n, ok := raw.(int); ok && n >= 0 {
+			return protoreflect.ValueOfUint64(uint64(n)), nil
+		}
+		s, _ := raw.(string)
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("%v is not an unsigned 64-bit int", raw)
+		}
+		return protoreflect.ValueOfUint64(n), nil
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		var f float64
+		switch n := raw.(type) {
+		case float64:
+			f = n
+		case int:
+			f = float64(n)
+		default:
+			return protoreflect.Value{}, fmt.Errorf("%v is not a float", raw)
+		}
+		if fd.Kind() == protoreflect.FloatKind {
+			return protoreflect.ValueOfFloat32(float32(f)), nil
+		}
+		return protoreflect.ValueOfFloat64(f), nil
+	case protoreflect.StringKind:
+		if s, ok := raw.(string); ok {
+			return protoreflect.ValueOfString(s), nil
+		}
+		return protoreflect.Value{}, fmt.Errorf("%v is not a string", raw)
+	case protoreflect.BytesKind:
+		s, _ := raw.(string)
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("%v is not base64", raw)
+		}
+		return protoreflect.ValueOfBytes(b), nil
+	case protoreflect.EnumKind:
+		switch v := raw.(type) {
+		case protoreflect.EnumNumber:
+			return protoreflect.ValueOfEnum(v), nil
+		case string:
+			if vd := fd.Enum().Values().ByName(protoreflect.Name(v)); vd != nil {
+				return protoreflect.ValueOfEnum(vd.Number()), nil
+			}
+		}
+		return protoreflect.Value{}, fmt.Errorf("%v is not a value of %s", raw, fd.Enum().Name())
+	}
+	return protoreflect.Value{}, fmt.Errorf("%v fields are not supported", fd.Kind())
+}
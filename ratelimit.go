@@ -0,0 +1,82 @@
+package graphqlhelpers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+const rateLimitTag = "ratelimit"
+
+// RateLimiter decides whether a call may go ahead, for the limit named by a ratelimit tag and the
+// value of the tagged arg.  Implementations usually count calls per name and value in a time
+// window, in memory or in a shared store like Redis.
+type RateLimiter interface {
+	Allow(ctx context.Context, limit, value string) (bool, error)
+}
+
+// RateLimitError is returned by LoadArgs when the limiter refuses a call.
+type RateLimitError struct {
+	// Arg is the arg whose value was limited.
+	Arg string
+	// Limit is the name of the limit, from the ratelimit tag.
+	Limit string
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limit exceeded for %s", e.Arg)
+}
+
+// SetRateLimiter sets the limiter enforcing ratelimit tags.  The tag holds the name of a limit, and
+// every call passing the tagged arg is checked against that limit and the arg's value, after the
+// args have loaded and before the resolver runs:
+//
+//	type SendVerificationCodeArgs struct {
+//		Email string `arg:"email" required:"true" ratelimit:"verification-email"`
+//	}
+//
+// Args that aren't passed aren't limited.  If the loader has no limiter, loading args with a
+// ratelimit tag always fails.
+func (e *ArgLoader) SetRateLimiter(l RateLimiter) {
+	e.rateLimiter = l
+}
+
+// SetRateLimiter sets the limiter enforcing ratelimit tags on the default loader.
+func SetRateLimiter(l RateLimiter) {
+	Default().SetRateLimiter(l)
+}
+
+// rateLimit checks the ratelimit tags of the passed args in structVal, which has been loaded from
+// args.
+func (e *ArgLoader) rateLimit(ctx context.Context, args map[string]interface{}, structVal reflect.Value) error {
+	if plan, err := e.plan(structVal.Type()); err == nil && !plan.rateLimited {
+		return nil
+	}
+	for _, f := range e.planArgFields(structVal.Type()) {
+		limit, ok := f.field.Tag.Lookup(rateLimitTag)
+		if !ok {
+			continue
+		}
+		if _, _, passed := lookupArg(args, f); !passed || f.rest {
+			continue
+		}
+		if e.rateLimiter == nil {
+			return fmt.Errorf("cannot enforce the %s tag on %s: no RateLimiter has been set", rateLimitTag, f.field.Name)
+		}
+		v := structVal.FieldByIndex(f.index)
+		for v.Kind() == reflect.Ptr && !v.IsNil() {
+			v = v.Elem()
+		}
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		allowed, err := e.rateLimiter.Allow(ctx, limit, fmt.Sprint(v.Interface()))
+		if err != nil {
+			return fmt.Errorf("cannot check the rate limit for %s: %v", f.name, err)
+		}
+		if !allowed {
+			return &RateLimitError{Arg: f.name, Limit: limit}
+		}
+	}
+	return nil
+}
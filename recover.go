@@ -0,0 +1,87 @@
+package graphqlhelpers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+
+	"github.com/graphql-go/graphql"
+)
+
+// ErrInternal is the error a recovered resolver fails with.  It deliberately says nothing about the
+// panic, which may include internals that clients shouldn't see; those go to the PanicLogger.
+var ErrInternal = errors.New("internal error")
+
+// PanicLogger records a panic recovered from the resolver of the field described by info, along with
+// the stack trace of the panicking goroutine.
+type PanicLogger func(info graphql.ResolveInfo, recovered interface{}, stack []byte)
+
+// DefaultPanicLogger is used by Recover, and by RecoverWith when given a nil logger.  It logs with
+// the standard log package, and can be replaced to send panics somewhere else.
+var DefaultPanicLogger PanicLogger = func(info graphql.ResolveInfo, recovered interface{}, stack []byte) {
+	log.Printf("panic resolving %s: %v\n%s", FieldPath(info), recovered, stack)
+}
+
+// Recover wraps resolver so that a panic inside it is logged with DefaultPanicLogger and turned into
+// an ErrInternal error for the field, instead of crashing the request.
+func Recover(resolver graphql.FieldResolveFn) graphql.FieldResolveFn {
+	return RecoverWith(nil)(resolver)
+}
+
+// RecoverWith returns middleware that recovers panicking resolvers like Recover, logging with logger
+// (or DefaultPanicLogger if it's nil).
+func RecoverWith(logger PanicLogger) Middleware {
+	return func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (result interface{}, err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					logPanic := logger
+					if logPanic == nil {
+						logPanic = DefaultPanicLogger
+					}
+					logPanic(p.Info, r, debug.Stack())
+					result, err = nil, ErrInternal
+				}
+			}()
+			return next(p)
+		}
+	}
+}
+
+// maxPanicValueLen is the longest raw value a LoaderPanicError keeps.
+const maxPanicValueLen = 256
+
+// LoaderPanicError is the error a loader func registered with Register fails with when it panics.
+// LoadArgs returns it wrapped in an *ArgError, so it can be found with errors.As:
+//
+//	var panicErr *LoaderPanicError
+//	if errors.As(err, &panicErr) {
+//		log.Printf("%v on %s\n%s", panicErr, panicErr.Value, panicErr.Stack)
+//	}
+//
+// Its message only names the func and what it panicked with; the value and stack are kept out of
+// it, since clients may see it.
+type LoaderPanicError struct {
+	// Func is the name of the loader func.
+	Func string
+	// Value is the raw value the loader func was called with, formatted with %#v.  Values longer
+	// than 256 bytes are truncated, and end with "...".
+	Value string
+	// Recovered is what the loader func panicked with.
+	Recovered interface{}
+	// Stack is the stack trace of the panicking goroutine.
+	Stack []byte
+}
+
+func newLoaderPanicError(fname string, value interface{}, recovered interface{}) *LoaderPanicError {
+	v := fmt.Sprintf("%#v", value)
+	if len(v) > maxPanicValueLen {
+		v = v[:maxPanicValueLen] + "..."
+	}
+	return &LoaderPanicError{Func: fname, Value: v, Recovered: recovered, Stack: debug.Stack()}
+}
+
+func (e *LoaderPanicError) Error() string {
+	return fmt.Sprintf("%s panicked: %v", e.Func, e.Recovered)
+}
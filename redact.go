@@ -0,0 +1,104 @@
+package graphqlhelpers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+)
+
+const redactTag = "redact"
+
+// Redacted replaces the values of redacted args in RedactedArgs.
+const Redacted = "[REDACTED]"
+
+// RedactedArgs is like DumpArgs, but replaces the value of every arg tagged `redact:"true"` with
+// Redacted, at any depth, so loaded args can be logged without leaking passwords or tokens.
+func (e *ArgLoader) RedactedArgs(i interface{}) (map[string]interface{}, error) {
+	dumped, err := e.DumpArgs(i)
+	if err != nil {
+		return nil, err
+	}
+	t := reflect.TypeOf(i)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	e.redactStruct(t, dumped)
+	return dumped, nil
+}
+
+func (e *ArgLoader) redactStruct(t reflect.Type, dumped map[string]interface{}) {
+	for _, f := range e.argFields(t) {
+		val, ok := dumped[f.name]
+		if !ok || f.rest {
+			continue
+		}
+		if redacted(f.field) {
+			dumped[f.name] = Redacted
+			continue
+		}
+		e.redactValue(f.field.Type, val)
+	}
+}
+
+// redactValue redacts the args nested in val, the dumped value of a field of type t.
+func (e *ArgLoader) redactValue(t reflect.Type, val interface{}) {
+	if _, registered := e.loaderFuncs[t]; registered {
+		return
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		e.redactValue(t.Elem(), val)
+	case reflect.Slice, reflect.Array:
+		items, _ := val.([]interface{})
+		for _, item := range items {
+			e.redactValue(t.Elem(), item)
+		}
+	case reflect.Struct:
+		if vt, ok := optionalValueType(t); ok {
+			e.redactValue(vt, val)
+		} else if m, ok := val.(map[string]interface{}); ok {
+			e.redactStruct(t, m)
+		}
+	}
+}
+
+func redacted(field reflect.StructField) bool {
+	r, _ := strconv.ParseBool(field.Tag.Get(redactTag))
+	return r
+}
+
+// ArgLogFunc receives the args loaded for a field (like "Query.login"), with redacted values
+// masked, or the error if loading failed.
+type ArgLogFunc func(ctx context.Context, field string, args map[string]interface{}, err error)
+
+// LogArgs installs a load hook that passes the result of every LoadArgs call to log, with the
+// values of args tagged `redact:"true"` masked.
+func (e *ArgLoader) LogArgs(log ArgLogFunc) {
+	e.OnLoad(func(p graphql.ResolveParams, target interface{}) func(error) {
+		return func(err error) {
+			if err != nil {
+				log(p.Context, FieldPath(p.Info), nil, err)
+				return
+			}
+			args, dumpErr := e.RedactedArgs(target)
+			if dumpErr != nil {
+				log(p.Context, FieldPath(p.Info), nil, fmt.Errorf("cannot log args: %v", dumpErr))
+				return
+			}
+			log(p.Context, FieldPath(p.Info), args, nil)
+		}
+	})
+}
+
+// RedactedArgs dumps an args struct with redacted values masked, using the default loader.
+func RedactedArgs(i interface{}) (map[string]interface{}, error) {
+	return Default().RedactedArgs(i)
+}
+
+// LogArgs installs an arg logging hook on the default loader.
+func LogArgs(log ArgLogFunc) {
+	Default().LogArgs(log)
+}
@@ -0,0 +1,33 @@
+//go:build go1.21
+
+package graphqlhelpers
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+)
+
+// SlogArgs returns an ArgLogFunc that logs loaded args to logger as structured attributes, one per
+// arg, at debug level (or warn level, with the error, when loading failed).
+func SlogArgs(logger *slog.Logger) ArgLogFunc {
+	return func(ctx context.Context, field string, args map[string]interface{}, err error) {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		if err != nil {
+			logger.WarnContext(ctx, "graphql args failed to load", slog.String("field", field), slog.Any("error", err))
+			return
+		}
+		names := make([]string, 0, len(args))
+		for name := range args {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		attrs := make([]any, len(names))
+		for i, name := range names {
+			attrs[i] = slog.Any(name, args[name])
+		}
+		logger.DebugContext(ctx, "graphql args loaded", slog.String("field", field), slog.Group("args", attrs...))
+	}
+}
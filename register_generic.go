@@ -0,0 +1,44 @@
+//go:build go1.18
+
+package graphqlhelpers
+
+import (
+	"reflect"
+	"runtime"
+
+	"github.com/graphql-go/graphql"
+)
+
+// RegisterTOn registers parse on e as the loader func for T, like Register, but with its signature
+// checked by the compiler rather than when it's registered:
+//
+//	err := RegisterTOn(loader, func(i interface{}) (uuid.UUID, error) {
+//		s, ok := i.(string)
+//		if !ok {
+//			return uuid.UUID{}, fmt.Errorf("%v is not a string", i)
+//		}
+//		return uuid.Parse(s)
+//	}, UUIDScalar)
+//
+// Register is still there for loader funcs whose types are only known at runtime.
+func RegisterTOn[T any](e *ArgLoader, parse func(interface{}) (T, error), gqlType graphql.Output) error {
+	fname := runtime.FuncForPC(reflect.ValueOf(parse).Pointer()).Name()
+	wrapped := func(i interface{}) (v reflect.Value, err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = newLoaderPanicError(fname, i, p)
+			}
+		}()
+		out, err := parse(i)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(&out).Elem(), nil
+	}
+	return e.register(reflect.TypeOf((*T)(nil)).Elem(), wrapped, gqlType)
+}
+
+// RegisterT registers parse as the loader func for T on the default loader.
+func RegisterT[T any](parse func(interface{}) (T, error), gqlType graphql.Output) error {
+	return RegisterTOn(Default(), parse, gqlType)
+}
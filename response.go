@@ -0,0 +1,154 @@
+package graphqlhelpers
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/location"
+)
+
+// Response is a graphql.Result with room for error and response extensions, which graphql-go
+// doesn't support itself.
+type Response struct {
+	Data       interface{}            `json:"data"`
+	Errors     []ResponseError        `json:"errors,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// ResponseError is an error in a Response.
+type ResponseError struct {
+	Message    string                    `json:"message"`
+	Locations  []location.SourceLocation `json:"locations,omitempty"`
+	Extensions map[string]interface{}    `json:"extensions,omitempty"`
+}
+
+// ErrorExtender may be implemented by the errors resolvers fail with to set the extensions of
+// their errors in a Response, such as a code, when they're formatted by DefaultErrorFormatter.
+type ErrorExtender interface {
+	ErrorExtensions() map[string]interface{}
+}
+
+// ErrorFormatter customizes an error in a Response.  err is the error a resolver built by this
+// package failed with, so it can be inspected with errors.As, or a gqlerrors.FormattedError for
+// other errors, like validation failures.  out starts with err's message and locations.
+type ErrorFormatter func(ctx context.Context, err error, out *ResponseError)
+
+// DefaultErrorFormatter sets a code extension on the errors this package's features fail with:
+//
+//	BAD_USER_INPUT         an *ArgError, whose arg is added as the arg extension, and the path
+//	                       to the nested value that failed, like ["input", "addresses", 2, "zip"],
+//	                       as the argPath extension
+//	FORBIDDEN              an *AccessError, or ErrHidden
+//	RATE_LIMITED           a *RateLimitError
+//	TIMEOUT                a *TimeoutError
+//	SERVICE_UNAVAILABLE    ErrCircuitOpen
+//	INTERNAL_SERVER_ERROR  ErrInternal, from a recovered panic
+//
+// Errors that are ErrorExtenders set their own extensions instead.
+func DefaultErrorFormatter(ctx context.Context, err error, out *ResponseError) {
+	var extender ErrorExtender
+	var argErr *ArgError
+	var accessErr *AccessError
+	var rateErr *RateLimitError
+	var timeoutErr *TimeoutError
+	switch {
+	case errors.As(err, &extender):
+		for k, v := range extender.ErrorExtensions() {
+			out.setExtension(k, v)
+		}
+	case errors.As(err, &argErr):
+		out.setExtension("code", "BAD_USER_INPUT")
+		out.setExtension("arg", argErr.Arg)
+		out.setExtension("argPath", append(ArgPath{argErr.Arg}, argErr.Path...))
+	case errors.As(err, &accessErr), errors.Is(err, ErrHidden):
+		out.setExtension("code", "FORBIDDEN")
+	case errors.As(err, &rateErr):
+		out.setExtension("code", "RATE_LIMITED")
+	case errors.As(err, &timeoutErr):
+		out.setExtension("code", "TIMEOUT")
+	case errors.Is(err, ErrCircuitOpen):
+		out.setExtension("code", "SERVICE_UNAVAILABLE")
+	case errors.Is(err, ErrInternal):
+		out.setExtension("code", "INTERNAL_SERVER_ERROR")
+	}
+}
+
+func (e *ResponseError) setExtension(key string, value interface{}) {
+	if e.Extensions == nil {
+		e.Extensions = map[string]interface{}{}
+	}
+	e.Extensions[key] = value
+}
+
+type errorLogKey struct{}
+
+// errorLog keeps the errors that resolvers fail with during a request, by message, because
+// graphql-go only keeps their messages and locations.
+type errorLog struct {
+	mu     sync.Mutex
+	errors map[string]error
+}
+
+// withErrorLog returns a context in which the resolvers built by this package log their errors.
+func withErrorLog(ctx context.Context) (context.Context, *errorLog) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	log := &errorLog{errors: map[string]error{}}
+	return context.WithValue(ctx, errorLogKey{}, log), log
+}
+
+// RecordErrors keeps the errors that next fails with for the Response, so that they reach the
+// ErrorFormatter as they are, rather than as their messages.  Every resolver built by this package
+// is wrapped in it; wrap resolvers built elsewhere in it too, outside any other middleware, for
+// their errors to be formatted.
+func RecordErrors(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+	return recordErrors(next)
+}
+
+// recordErrors is the outermost middleware of every resolver built by this package, logging the
+// errors it fails with when the request has an errorLog.
+func recordErrors(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		result, err := next(p)
+		if err != nil && p.Context != nil {
+			if log, ok := p.Context.Value(errorLogKey{}).(*errorLog); ok {
+				log.mu.Lock()
+				if _, seen := log.errors[err.Error()]; !seen {
+					log.errors[err.Error()] = err
+				}
+				log.mu.Unlock()
+			}
+		}
+		return result, err
+	}
+}
+
+// newResponse converts res to a Response, formatting its errors with format.  log holds the
+// errors logged while res was executed, if there is one.
+func newResponse(ctx context.Context, res *graphql.Result, log *errorLog, format ErrorFormatter) *Response {
+	out := &Response{Data: res.Data}
+	for _, formatted := range res.Errors {
+		var err error = formatted
+		if log != nil {
+			log.mu.Lock()
+			if original, ok := log.errors[formatted.Message]; ok {
+				err = original
+			}
+			log.mu.Unlock()
+		}
+		respErr := ResponseError{Message: formatted.Message, Locations: formatted.Locations}
+		if format != nil {
+			format(ctx, err, &respErr)
+		}
+		out.Errors = append(out.Errors, respErr)
+	}
+	return out
+}
+
+// errorResponse returns a Response holding only an error with msg.
+func errorResponse(msg string) *Response {
+	return &Response{Errors: []ResponseError{{Message: msg}}}
+}
@@ -0,0 +1,228 @@
+package graphqlhelpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// fieldTag names the graphql output field a struct field corresponds to, the way argTag does for
+// arguments.
+const fieldTag = "field"
+
+// outputField is a struct field that has been tagged as an output field.
+type outputField struct {
+	name  string
+	index []int
+	field reflect.StructField
+}
+
+// outputFields returns the field-tagged fields of a struct type.  As with args, untagged embedded
+// structs have their fields promoted.
+func outputFields(t reflect.Type) []outputField {
+	var out []outputField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, ok := field.Tag.Lookup(fieldTag)
+		if name == "-" {
+			continue
+		}
+		if !ok {
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				for _, f := range outputFields(field.Type) {
+					f.index = append([]int{i}, f.index...)
+					out = append(out, f)
+				}
+			}
+			continue
+		}
+		if idx := strings.Index(name, ","); idx >= 0 {
+			name = name[:idx]
+		}
+		if name == "" {
+			name = CamelCase(field.Name)
+		}
+		out = append(out, outputField{name: name, index: []int{i}, field: field})
+	}
+	return out
+}
+
+// LoadResult maps a graphql result payload (such as the Data of a graphql.Result, or the "data"
+// member of a decoded JSON response) into a struct whose fields have field tags naming the result
+// fields they hold.  Nested objects, lists, and pointers are followed.  Values of registered types
+// are converted with their loader funcs, and numbers are converted between Go numeric types as long
+// as no precision is lost, so payloads decoded from JSON load as well as ones straight from
+// graphql-go.
+func (e *ArgLoader) LoadResult(data map[string]interface{}, target interface{}) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%v is not a pointer to a struct", target)
+	}
+	return e.loadResultStruct(data, v.Elem())
+}
+
+func (e *ArgLoader) loadResultStruct(data map[string]interface{}, structVal reflect.Value) error {
+	for _, f := range outputFields(structVal.Type()) {
+		raw, ok := data[f.name]
+		if !ok || raw == nil {
+			continue
+		}
+		v, err := e.loadResultValue(f.field.Type, raw)
+		if err != nil {
+			return fmt.Errorf("cannot populate %s: %v", f.field.Name, err)
+		}
+		structVal.FieldByIndex(f.index).Set(v)
+	}
+	return nil
+}
+
+func (e *ArgLoader) loadResultValue(t reflect.Type, raw interface{}) (reflect.Value, error) {
+	rawVal := reflect.ValueOf(raw)
+	if rawVal.Type().AssignableTo(t) {
+		return rawVal, nil
+	}
+	if loaderFunc, ok := e.loaderFuncs[t]; ok {
+		if v, err := loaderFunc(raw); err == nil {
+			return v, nil
+		}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		v, err := e.loadResultValue(t.Elem(), raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out := reflect.New(t.Elem())
+		out.Elem().Set(v)
+		return out, nil
+	case reflect.Slice:
+		items, ok := raw.([]interface{})
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("%v is not a list", raw)
+		}
+		out := reflect.MakeSlice(t, len(items), len(items))
+		for idx, item := range items {
+			if item == nil {
+				continue
+			}
+			v, err := e.loadResultValue(t.Elem(), item)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("item %d: %v", idx, err)
+			}
+			out.Index(idx).Set(v)
+		}
+		return out, nil
+	case reflect.Struct:
+		m, ok := raw.(map[string]interface{})
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("%v is not an object", raw)
+		}
+		out := reflect.New(t).Elem()
+		if err := e.loadResultStruct(m, out); err != nil {
+			return reflect.Value{}, err
+		}
+		return out, nil
+	case reflect.String, reflect.Bool:
+		if rawVal.Kind() == t.Kind() {
+			return rawVal.Convert(t), nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return convertNumber(t, raw)
+	}
+	return reflect.Value{}, fmt.Errorf("cannot convert %v to %v", raw, t)
+}
+
+// convertNumber converts any Go number (or json.Number) into a value of numeric type t, refusing
+// conversions that would lose information.  Integers are converted as integers, not through a
+// float64, so ones above 2^53 load exactly.
+func convertNumber(t reflect.Type, raw interface{}) (reflect.Value, error) {
+	var (
+		i int64
+		u uint64
+		f float64
+		// which of i, u and f holds the number.
+		signed, unsigned bool
+	)
+	switch n := raw.(type) {
+	case json.Number:
+		if parsed, err := n.Int64(); err == nil {
+			i, signed = parsed, true
+		} else if parsed, err := strconv.ParseUint(n.String(), 10, 64); err == nil {
+			u, unsigned = parsed, true
+		} else if f, err = n.Float64(); err != nil {
+			return reflect.Value{}, err
+		}
+	default:
+		v := reflect.ValueOf(raw)
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			i, signed = v.Int(), true
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			u, unsigned = v.Uint(), true
+		case reflect.Float32, reflect.Float64:
+			f = v.Float()
+		default:
+			return reflect.Value{}, fmt.Errorf("%v is not a number", raw)
+		}
+	}
+	out := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.Float32, reflect.Float64:
+		switch {
+		case signed:
+			f = float64(i)
+		case unsigned:
+			f = float64(u)
+		}
+		out.SetFloat(f)
+		return out, nil
+	}
+	if !signed && !unsigned {
+		if f != math.Trunc(f) {
+			return reflect.Value{}, fmt.Errorf("%v is not an integer", raw)
+		}
+		switch {
+		case f >= 0 && f < math.Exp2(64):
+			u, unsigned = uint64(f), true
+		case f < 0 && f >= -math.Exp2(63):
+			i, signed = int64(f), true
+		default:
+			return reflect.Value{}, fmt.Errorf("%v overflows %v", raw, t)
+		}
+	}
+	switch t.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if signed {
+			if i < 0 {
+				return reflect.Value{}, fmt.Errorf("%v overflows %v", raw, t)
+			}
+			u = uint64(i)
+		}
+		if out.OverflowUint(u) {
+			return reflect.Value{}, fmt.Errorf("%v overflows %v", raw, t)
+		}
+		out.SetUint(u)
+	default:
+		if unsigned {
+			if u > math.MaxInt64 {
+				return reflect.Value{}, fmt.Errorf("%v overflows %v", raw, t)
+			}
+			i = int64(u)
+		}
+		if out.OverflowInt(i) {
+			return reflect.Value{}, fmt.Errorf("%v overflows %v", raw, t)
+		}
+		out.SetInt(i)
+	}
+	return out, nil
+}
+
+// LoadResult maps a graphql result payload into a struct using the default loader.
+func LoadResult(data map[string]interface{}, target interface{}) error {
+	return Default().LoadResult(data, target)
+}
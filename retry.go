@@ -0,0 +1,122 @@
+package graphqlhelpers
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/graphql-go/graphql"
+)
+
+// RetryPolicy configures Retry.
+type RetryPolicy struct {
+	// Attempts is the most times the resolver is called, the first call included.  It defaults to
+	// 3.
+	Attempts int
+	// BaseDelay is the longest wait before the first retry, doubling for every retry after it.  It
+	// defaults to 100ms.
+	BaseDelay time.Duration
+	// MaxDelay caps the wait before a retry.  It defaults to 5s.
+	MaxDelay time.Duration
+	// Retryable reports whether a call that failed with err is worth retrying.  It defaults to
+	// IsRetryable.
+	Retryable func(err error) bool
+}
+
+// IsRetryable reports whether err might go away if the resolver is called again: it's false for the
+// errors of this package's input and access checks, like an *ArgError or *AccessError, which
+// would only happen again, for ErrCircuitOpen, since an open breaker is there to stop calls, and for
+// ErrInternal, since a resolver that panicked would most likely panic again.  It's true for
+// everything else.
+func IsRetryable(err error) bool {
+	var argErr *ArgError
+	var accessErr *AccessError
+	var rateErr *RateLimitError
+	return !errors.As(err, &argErr) && !errors.As(err, &accessErr) && !errors.As(err, &rateErr) &&
+		!errors.Is(err, ErrHidden) && !errors.Is(err, ErrCircuitOpen) && !errors.Is(err, ErrInternal)
+}
+
+// RetryOn returns a Retryable func for RetryPolicy that retries errors matching any of errs with
+// errors.Is, like a sentinel error for an unavailable backend.
+func RetryOn(errs ...error) func(err error) bool {
+	return func(err error) bool {
+		for _, target := range errs {
+			if errors.Is(err, target) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Retry returns middleware that calls the resolvers it wraps again when they fail with an error
+// policy says is retryable, waiting a little longer before each retry.  Each wait is a random time
+// up to BaseDelay, doubled for each retry and capped at MaxDelay, so that failing callers don't
+// all retry in step.  Retrying stops early if the request's context is done, and the field then
+// fails with the last error.  It's meant for hand-written resolvers calling unreliable backends,
+// and resolvers with side effects should only be retried if they're safe to repeat.  Everything
+// inside the middleware runs again for each attempt; fields built by FieldFromFunc should use
+// WithRetry instead, which doesn't load their args again.
+func Retry(policy RetryPolicy) Middleware {
+	policy = policy.withDefaults()
+	return func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (interface{}, error) {
+			return policy.do(nodeContext(p), func() (interface{}, error) {
+				return next(p)
+			})
+		}
+	}
+}
+
+// WithRetry retries calls of the field's resolver func as policy says, as with Retry.  Only the
+// func is called again: its args are loaded, and checked against rate limits, once, and the
+// field's middleware, WithTimeout included, runs once around all of the attempts.
+func WithRetry(policy RetryPolicy) FieldOption {
+	policy = policy.withDefaults()
+	return func(o *fieldOptions) {
+		o.retry = &policy
+	}
+}
+
+// withDefaults returns the policy with its unset fields set to their defaults.
+func (policy RetryPolicy) withDefaults() RetryPolicy {
+	if policy.Attempts <= 0 {
+		policy.Attempts = 3
+	}
+	if policy.BaseDelay <= 0 {
+		policy.BaseDelay = 100 * time.Millisecond
+	}
+	if policy.MaxDelay <= 0 {
+		policy.MaxDelay = 5 * time.Second
+	}
+	if policy.Retryable == nil {
+		policy.Retryable = IsRetryable
+	}
+	return policy
+}
+
+// do makes call until it succeeds, fails with an error that isn't retryable, runs out of attempts
+// or ctx is done.
+func (policy RetryPolicy) do(ctx context.Context, call func() (interface{}, error)) (interface{}, error) {
+	delay := policy.BaseDelay
+	if delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+	for attempt := 1; ; attempt++ {
+		result, err := call()
+		if err == nil || attempt == policy.Attempts || !policy.Retryable(err) {
+			return result, err
+		}
+		wait := time.NewTimer(time.Duration(rand.Int63n(int64(delay) + 1)))
+		select {
+		case <-wait.C:
+		case <-ctx.Done():
+			wait.Stop()
+			return result, err
+		}
+		if delay *= 2; delay > policy.MaxDelay {
+			delay = policy.MaxDelay
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// QueryRule checks a parsed request before it is executed, returning an error to reject it.  Rules
+// see the operation that will run (chosen by operationName, as graphql.Do does) and the request's
+// variables.
+type QueryRule func(schema graphql.Schema, doc *ast.Document, operationName string, vars map[string]interface{}) error
+
+// Execute is like graphql.Do, but first checks the request against rules, such as CostLimit and
+// DepthLimit.  If a rule rejects the request it isn't executed, and the rule's error is the only
+// error in the result.  Requests that don't parse are left to graphql.Do to report.
+func Execute(params graphql.Params, rules ...QueryRule) *graphql.Result {
+	if len(rules) > 0 {
+		doc, err := parser.Parse(parser.ParseParams{
+			Source: source.NewSource(&source.Source{Body: []byte(params.RequestString), Name: "GraphQL request"}),
+		})
+		if err == nil {
+			for _, rule := range rules {
+				if err := rule(params.Schema, doc, params.OperationName, params.VariableValues); err != nil {
+					return &graphql.Result{Errors: []gqlerrors.FormattedError{gqlerrors.NewFormattedError(err.Error())}}
+				}
+			}
+		}
+	}
+	return graphql.Do(params)
+}
+
+// operation returns the operation of doc that a request for operationName would execute.
+func operation(doc *ast.Document, operationName string) (*ast.OperationDefinition, error) {
+	var found *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if operationName == "" {
+			if found != nil {
+				return nil, fmt.Errorf("must provide operation name if query contains multiple operations")
+			}
+			found = op
+		} else if op.Name != nil && op.Name.Value == operationName {
+			return op, nil
+		}
+	}
+	if found == nil {
+		if operationName != "" {
+			return nil, fmt.Errorf("unknown operation named %q", operationName)
+		}
+		return nil, fmt.Errorf("must provide an operation")
+	}
+	return found, nil
+}
+
+// fragments returns the fragment definitions of doc by name.
+func fragments(doc *ast.Document) map[string]*ast.FragmentDefinition {
+	out := map[string]*ast.FragmentDefinition{}
+	for _, def := range doc.Definitions {
+		if frag, ok := def.(*ast.FragmentDefinition); ok && frag.Name != nil {
+			out[frag.Name.Value] = frag
+		}
+	}
+	return out
+}
+
+// rootType returns the schema's root type for op.
+func rootType(schema graphql.Schema, op *ast.OperationDefinition) (*graphql.Object, error) {
+	var root *graphql.Object
+	switch op.Operation {
+	case ast.OperationTypeQuery:
+		root = schema.QueryType()
+	case ast.OperationTypeMutation:
+		root = schema.MutationType()
+	case ast.OperationTypeSubscription:
+		root = schema.SubscriptionType()
+	}
+	if root == nil {
+		return nil, fmt.Errorf("the schema has no %s type", op.Operation)
+	}
+	return root, nil
+}
+
+// fieldDefinition returns the definition of the named field of a composite type, or nil.
+func fieldDefinition(t graphql.Type, name string) *graphql.FieldDefinition {
+	switch t := t.(type) {
+	case *graphql.Object:
+		return t.Fields()[name]
+	case *graphql.Interface:
+		return t.Fields()[name]
+	}
+	return nil
+}
+
+// DepthLimit returns a rule for Execute that rejects operations nesting fields more than max levels
+// deep, counting root fields as depth 1 and looking through fragments.  Introspection fields (those
+// starting with "__") aren't counted, so tools like GraphiQL keep working.  It doesn't depend on how
+// the schema was built.
+func DepthLimit(max int) QueryRule {
+	return func(schema graphql.Schema, doc *ast.Document, operationName string, vars map[string]interface{}) error {
+		op, err := operation(doc, operationName)
+		if err != nil {
+			return err
+		}
+		depth, err := selectionDepth(op.SelectionSet, fragments(doc), map[string]bool{})
+		if err != nil {
+			return err
+		}
+		if depth > max {
+			return fmt.Errorf("query depth %d exceeds the maximum of %d", depth, max)
+		}
+		return nil
+	}
+}
+
+// selectionDepth returns the depth of the deepest field in set.  visiting holds the fragments being
+// measured, so that fragment cycles are caught.
+func selectionDepth(set *ast.SelectionSet, frags map[string]*ast.FragmentDefinition, visiting map[string]bool) (int, error) {
+	if set == nil {
+		return 0, nil
+	}
+	deepest := 0
+	for _, sel := range set.Selections {
+		var depth int
+		var err error
+		switch sel := sel.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(sel.Name.Value, "__") {
+				continue
+			}
+			depth, err = selectionDepth(sel.SelectionSet, frags, visiting)
+			depth++
+		case *ast.InlineFragment:
+			depth, err = selectionDepth(sel.SelectionSet, frags, visiting)
+		case *ast.FragmentSpread:
+			name := sel.Name.Value
+			frag, ok := frags[name]
+			if !ok {
+				return 0, fmt.Errorf("unknown fragment %q", name)
+			}
+			if visiting[name] {
+				return 0, fmt.Errorf("fragment %q spreads itself", name)
+			}
+			visiting[name] = true
+			depth, err = selectionDepth(frag.SelectionSet, frags, visiting)
+			delete(visiting, name)
+		}
+		if err != nil {
+			return 0, err
+		}
+		if depth > deepest {
+			deepest = depth
+		}
+	}
+	return deepest, nil
+}
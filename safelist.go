@@ -0,0 +1,196 @@
+package graphqlhelpers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/printer"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/graphql-go/graphql/language/visitor"
+)
+
+// NormalizeQuery returns the canonical text of a GraphQL document, printed from its syntax tree,
+// so that documents differing only in whitespace, commas or comments normalize the same.
+func NormalizeQuery(query string) (string, error) {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return "", err
+	}
+	return normalizedText(doc), nil
+}
+
+// QueryHash returns the hex sha256 hash of the normalized text of a GraphQL document.
+func QueryHash(query string) (string, error) {
+	normalized, err := NormalizeQuery(query)
+	if err != nil {
+		return "", err
+	}
+	return hashText(normalized), nil
+}
+
+// normalizedText prints doc.  graphql-go's printer writes string values between quotes without
+// escaping them, so that "x\", b: \"y" would print like two args; the string values are escaped for
+// the printer, and put back afterwards.
+func normalizedText(doc *ast.Document) string {
+	var values []*ast.StringValue
+	seen := map[*ast.StringValue]bool{}
+	visitor.Visit(doc, &visitor.VisitorOptions{
+		Enter: func(p visitor.VisitFuncParams) (string, interface{}) {
+			// the visitor can enter a node more than once.
+			if v, ok := p.Node.(*ast.StringValue); ok && !seen[v] {
+				seen[v] = true
+				values = append(values, v)
+			}
+			return visitor.ActionNoChange, nil
+		},
+	}, nil)
+	raw := make([]string, len(values))
+	for i, v := range values {
+		raw[i] = v.Value
+		v.Value = escapeString(v.Value)
+	}
+	defer func() {
+		for i, v := range values {
+			v.Value = raw[i]
+		}
+	}()
+	s, _ := printer.Print(doc).(string)
+	return s
+}
+
+// escapeString escapes s for the inside of a GraphQL string literal.
+func escapeString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	return b.String()
+}
+
+func hashText(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// Safelist holds the queries a public endpoint will run, by the hash of their normalized text.
+type Safelist struct {
+	mu     sync.RWMutex
+	hashes map[string]bool
+}
+
+// NewSafelist returns a Safelist allowing the given queries.  Each entry is either a query's text
+// or its QueryHash.
+func NewSafelist(entries ...string) (*Safelist, error) {
+	s := &Safelist{hashes: map[string]bool{}}
+	for _, entry := range entries {
+		if err := s.Add(entry); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// ReadSafelist reads a Safelist from a JSON object mapping query hashes to query texts, as written
+// by the safelist command.  Every query is checked against its hash.
+func ReadSafelist(r io.Reader) (*Safelist, error) {
+	var manifest map[string]string
+	if err := json.NewDecoder(r).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("cannot read safelist: %v", err)
+	}
+	s := &Safelist{hashes: map[string]bool{}}
+	for hash, query := range manifest {
+		actual, err := QueryHash(query)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read safelist: query %s: %v", hash, err)
+		}
+		if actual != hash {
+			return nil, fmt.Errorf("cannot read safelist: query %s has hash %s", hash, actual)
+		}
+		s.hashes[hash] = true
+	}
+	return s, nil
+}
+
+// Add allows a query, given as its text or its QueryHash.
+func (s *Safelist) Add(entry string) error {
+	hash := entry
+	if !isQueryHash(entry) {
+		var err error
+		if hash, err = QueryHash(entry); err != nil {
+			return fmt.Errorf("cannot add query to safelist: %v", err)
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hashes[hash] = true
+	return nil
+}
+
+// Allows reports whether the safelist holds a query, given as its text or its QueryHash.
+func (s *Safelist) Allows(entry string) bool {
+	hash := entry
+	if !isQueryHash(entry) {
+		var err error
+		if hash, err = QueryHash(entry); err != nil {
+			return false
+		}
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.hashes[hash]
+}
+
+// Rule returns a rule for Execute that rejects every request whose document isn't in the safelist.
+// Documents are compared by their normalized text, so clients may reformat their queries, but the
+// whole document, including every operation and fragment in it, must match.
+func (s *Safelist) Rule() QueryRule {
+	return func(schema graphql.Schema, doc *ast.Document, operationName string, vars map[string]interface{}) error {
+		hash := hashText(normalizedText(doc))
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		if !s.hashes[hash] {
+			return fmt.Errorf("query %s is not in the safelist", hash)
+		}
+		return nil
+	}
+}
+
+// isQueryHash reports whether s looks like a hex sha256 hash rather than a query.
+func isQueryHash(s string) bool {
+	if len(s) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
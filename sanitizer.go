@@ -0,0 +1,49 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"strings"
+)
+
+// sanitizeTag names the sanitizer that cleans a string arg after it is loaded, like
+// `sanitize:"html"`.  Sanitizers run before any transforms in the field's transform tag, and may be
+// used on the same field types.
+const sanitizeTag = "sanitize"
+
+// Sanitizer cleans user-generated content, such as by removing unsafe HTML.  A bluemonday
+// *Policy is a Sanitizer, and the htmlsanitize sub-package registers bluemonday's policies as
+// sanitizers.
+type Sanitizer interface {
+	Sanitize(s string) string
+}
+
+// RegisterSanitizer makes s available to sanitize tags as name.  No sanitizers are registered by
+// default.
+func (e *ArgLoader) RegisterSanitizer(name string, s Sanitizer) error {
+	if name == "" || strings.ContainsAny(name, ", ") {
+		return fmt.Errorf("%q is not a valid sanitizer name", name)
+	}
+	if s == nil {
+		return fmt.Errorf("sanitizer %s cannot be nil", name)
+	}
+	if _, ok := e.sanitizers[name]; ok {
+		return fmt.Errorf("a sanitizer named %s has already been registered", name)
+	}
+	e.sanitizers[name] = s
+	return nil
+}
+
+// RegisterSanitizer registers a sanitizer on the default loader.
+func RegisterSanitizer(name string, s Sanitizer) error {
+	return Default().RegisterSanitizer(name, s)
+}
+
+// sanitizer returns the Sanitize method of the sanitizer named by a sanitize tag.
+func (e *ArgLoader) sanitizer(tag string) (Transform, error) {
+	name := strings.TrimSpace(tag)
+	s, ok := e.sanitizers[name]
+	if !ok {
+		return nil, fmt.Errorf("%q is not a registered sanitizer", name)
+	}
+	return s.Sanitize, nil
+}
@@ -0,0 +1,164 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/graphql-go/graphql"
+)
+
+// SchemaBuilder assembles a complete graphql.Schema from resolver structs.  Every exported method
+// of a registered struct that looks like a resolver func (see FieldFromFunc) becomes a field named
+// after the method, camelCased, so a method
+//
+//	func (q *Queries) User(ctx context.Context, args UserArgs) (*User, error)
+//
+// becomes a user(...) field on the root query type.  Methods whose first param isn't a
+// context.Context are ignored, so resolver structs can have helper methods too.
+type SchemaBuilder struct {
+	loader       *ArgLoader
+	query        graphql.Fields
+	mutation     graphql.Fields
+	subscription graphql.Fields
+
+	// the first error encountered while adding fields, returned by Build.
+	err error
+}
+
+// NewSchemaBuilder returns a SchemaBuilder that configures fields with this loader.
+func (e *ArgLoader) NewSchemaBuilder() *SchemaBuilder {
+	return &SchemaBuilder{
+		loader:       e,
+		query:        graphql.Fields{},
+		mutation:     graphql.Fields{},
+		subscription: graphql.Fields{},
+	}
+}
+
+// NewSchemaBuilder returns a SchemaBuilder that configures fields with the default loader.
+func NewSchemaBuilder() *SchemaBuilder {
+	return Default().NewSchemaBuilder()
+}
+
+// Query adds the resolver methods of r as fields of the root query type.
+func (b *SchemaBuilder) Query(r interface{}) *SchemaBuilder {
+	b.addMethods("query", r)
+	return b
+}
+
+// Mutation adds the resolver methods of r as fields of the root mutation type.
+func (b *SchemaBuilder) Mutation(r interface{}) *SchemaBuilder {
+	b.addMethods("mutation", r)
+	return b
+}
+
+// QueryField adds a single resolver func as a field of the root query type.
+func (b *SchemaBuilder) QueryField(name string, fn interface{}, opts ...FieldOption) *SchemaBuilder {
+	b.addFunc("query", name, fn, opts...)
+	return b
+}
+
+// MutationField adds a single resolver func as a field of the root mutation type.
+func (b *SchemaBuilder) MutationField(name string, fn interface{}, opts ...FieldOption) *SchemaBuilder {
+	b.addFunc("mutation", name, fn, opts...)
+	return b
+}
+
+// AddField adds a field built elsewhere (by hand, or by one of the other builders) to the root
+// query, mutation or subscription type, as chosen by op.
+func (b *SchemaBuilder) AddField(op, name string, field *graphql.Field) *SchemaBuilder {
+	fields, err := b.fieldsFor(op)
+	if err != nil {
+		b.setErr(err)
+		return b
+	}
+	b.add(op, fields, name, field)
+	return b
+}
+
+// Build returns the schema, or the first error encountered while adding fields.
+func (b *SchemaBuilder) Build() (graphql.Schema, error) {
+	if b.err != nil {
+		return graphql.Schema{}, b.err
+	}
+	if len(b.query) == 0 {
+		return graphql.Schema{}, fmt.Errorf("a schema needs at least one query field")
+	}
+	conf := graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{Name: rootTypeNames["query"], Fields: b.query}),
+		// implementations of interfaces may not be returned directly by any field.
+		Types: b.loader.implementations(),
+	}
+	if len(b.mutation) > 0 {
+		conf.Mutation = graphql.NewObject(graphql.ObjectConfig{Name: rootTypeNames["mutation"], Fields: b.mutation})
+	}
+	if len(b.subscription) > 0 {
+		conf.Subscription = graphql.NewObject(graphql.ObjectConfig{Name: rootTypeNames["subscription"], Fields: b.subscription})
+	}
+	return graphql.NewSchema(conf)
+}
+
+// rootTypeNames are the names Build gives the root types of each operation type.
+var rootTypeNames = map[string]string{
+	"query":        "Query",
+	"mutation":     "Mutation",
+	"subscription": "Subscription",
+}
+
+func (b *SchemaBuilder) fieldsFor(op string) (graphql.Fields, error) {
+	switch op {
+	case "query":
+		return b.query, nil
+	case "mutation":
+		return b.mutation, nil
+	case "subscription":
+		return b.subscription, nil
+	}
+	return nil, fmt.Errorf("%q is not an operation type", op)
+}
+
+func (b *SchemaBuilder) addMethods(op string, r interface{}) {
+	v := reflect.ValueOf(r)
+	t := v.Type()
+	found := false
+	for i := 0; i < t.NumMethod(); i++ {
+		method := t.Method(i)
+		if method.Type.NumIn() < 2 || method.Type.In(1) != contextType {
+			// not a resolver.  (In(0) is the receiver.)
+			continue
+		}
+		found = true
+		b.addFunc(op, CamelCase(method.Name), v.Method(i).Interface())
+	}
+	if !found {
+		b.setErr(fmt.Errorf("%v has no resolver methods", t))
+	}
+}
+
+func (b *SchemaBuilder) addFunc(op, name string, fn interface{}, opts ...FieldOption) {
+	field, err := b.loader.SafeFieldFromFunc(fn, opts...)
+	if err != nil {
+		b.setErr(fmt.Errorf("%s: %v", name, err))
+		return
+	}
+	fields, _ := b.fieldsFor(op)
+	b.add(op, fields, name, field)
+}
+
+func (b *SchemaBuilder) add(op string, fields graphql.Fields, name string, field *graphql.Field) {
+	if _, ok := fields[name]; ok {
+		b.setErr(fmt.Errorf("field %s has already been added", name))
+		return
+	}
+	if err := b.loader.setFieldArgCosts(rootTypeNames[op]+"."+name, field); err != nil {
+		b.setErr(fmt.Errorf("%s: %v", name, err))
+		return
+	}
+	fields[name] = field
+}
+
+func (b *SchemaBuilder) setErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
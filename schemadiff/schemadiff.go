@@ -0,0 +1,368 @@
+// Package schemadiff compares two versions of a GraphQL schema and classifies every change as
+// breaking, dangerous or safe, so CI can catch a struct edit that would break existing clients.
+//
+// Schemas are compared as SDL, so a checked-in snapshot (from graphqlhelpers.PrintSchema or
+// graphqlhelpers.SDL) can be diffed against the schema the code builds today:
+//
+//	changes, err := schemadiff.DiffSDL(snapshot, graphqlhelpers.PrintSchema(schema))
+//	if breaking := schemadiff.Only(changes, schemadiff.Breaking); len(breaking) > 0 {
+//		// fail the build
+//	}
+package schemadiff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/printer"
+
+	graphqlhelpers "github.com/btubbs/graphql-go-helpers"
+)
+
+// Level is how much a change can hurt existing clients.
+type Level int
+
+const (
+	// Safe changes can't break any existing query.
+	Safe Level = iota
+	// Dangerous changes won't make existing queries fail validation, but may change how clients
+	// behave, like a new enum value a client's switch statement doesn't handle.
+	Dangerous
+	// Breaking changes can make existing queries fail.
+	Breaking
+)
+
+func (l Level) String() string {
+	switch l {
+	case Safe:
+		return "safe"
+	case Dangerous:
+		return "dangerous"
+	case Breaking:
+		return "breaking"
+	}
+	return fmt.Sprintf("Level(%d)", int(l))
+}
+
+// Change is one difference between two schemas.
+type Change struct {
+	Level Level
+	// Path is the schema coordinate of the change, like "Query.user(id)" or "Color.RED".
+	Path    string
+	Message string
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s: %s", c.Level, c.Path, c.Message)
+}
+
+// Diff compares two built schemas.
+func Diff(oldSchema, newSchema graphql.Schema) ([]Change, error) {
+	return DiffSDL(graphqlhelpers.PrintSchema(oldSchema), graphqlhelpers.PrintSchema(newSchema))
+}
+
+// DiffSDL compares two schemas written in SDL.  The changes are sorted by path.
+func DiffSDL(oldSDL, newSDL string) ([]Change, error) {
+	oldTypes, err := parse(oldSDL)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse old schema: %v", err)
+	}
+	newTypes, err := parse(newSDL)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse new schema: %v", err)
+	}
+	d := &differ{}
+	for name, o := range oldTypes {
+		n, ok := newTypes[name]
+		if !ok {
+			d.add(Breaking, name, "%s %s was removed", o.kind, name)
+			continue
+		}
+		if o.kind != n.kind {
+			d.add(Breaking, name, "%s changed from %s to %s", name, o.kind, n.kind)
+			continue
+		}
+		d.typeDef(name, o, n)
+	}
+	for name, n := range newTypes {
+		if _, ok := oldTypes[name]; !ok {
+			d.add(Safe, name, "%s %s was added", n.kind, name)
+		}
+	}
+	sort.SliceStable(d.changes, func(i, j int) bool {
+		if d.changes[i].Path != d.changes[j].Path {
+			return d.changes[i].Path < d.changes[j].Path
+		}
+		return d.changes[i].Message < d.changes[j].Message
+	})
+	return d.changes, nil
+}
+
+// Only returns the changes of the given level.
+func Only(changes []Change, level Level) []Change {
+	var out []Change
+	for _, c := range changes {
+		if c.Level == level {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// typeDef is the comparable part of a type definition.
+type typeDef struct {
+	kind       string
+	fields     map[string]fieldDef // objects and interfaces
+	inputs     map[string]inputDef // input objects
+	values     map[string]bool     // enums
+	members    map[string]bool     // unions
+	interfaces map[string]bool     // objects
+}
+
+type fieldDef struct {
+	typ  ast.Type
+	args map[string]inputDef
+}
+
+// inputDef is an argument or input object field.
+type inputDef struct {
+	typ ast.Type
+	// def is the printed default value, or "" if there isn't one.
+	def string
+}
+
+func parse(sdl string) (map[string]typeDef, error) {
+	doc, err := parser.Parse(parser.ParseParams{Source: legacyImplements(sdl)})
+	if err != nil {
+		return nil, err
+	}
+	types := map[string]typeDef{}
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *ast.ScalarDefinition:
+			types[def.Name.Value] = typeDef{kind: "scalar"}
+		case *ast.EnumDefinition:
+			t := typeDef{kind: "enum", values: map[string]bool{}}
+			for _, v := range def.Values {
+				t.values[v.Name.Value] = true
+			}
+			types[def.Name.Value] = t
+		case *ast.UnionDefinition:
+			types[def.Name.Value] = typeDef{kind: "union", members: names(def.Types)}
+		case *ast.InputObjectDefinition:
+			types[def.Name.Value] = typeDef{kind: "input", inputs: inputDefs(def.Fields)}
+		case *ast.InterfaceDefinition:
+			types[def.Name.Value] = typeDef{kind: "interface", fields: fieldDefs(def.Fields)}
+		case *ast.ObjectDefinition:
+			types[def.Name.Value] = typeDef{
+				kind:       "type",
+				fields:     fieldDefs(def.Fields),
+				interfaces: names(def.Interfaces),
+			}
+		}
+	}
+	return types, nil
+}
+
+// legacyImplements replaces the & separators in implements lists with spaces, since graphql-go's
+// parser only understands the older space-separated form.  Strings and comments are left alone.
+func legacyImplements(sdl string) string {
+	out := []byte(sdl)
+	for i := 0; i < len(out); i++ {
+		switch {
+		case out[i] == '#':
+			for i < len(out) && out[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(sdl[i:], `"""`):
+			end := strings.Index(sdl[i+3:], `"""`)
+			if end < 0 {
+				return string(out)
+			}
+			i += end + 5
+		case out[i] == '"':
+			for i++; i < len(out) && out[i] != '"' && out[i] != '\n'; i++ {
+				if out[i] == '\\' {
+					i++
+				}
+			}
+		case out[i] == '&':
+			out[i] = ' '
+		}
+	}
+	return string(out)
+}
+
+func names(named []*ast.Named) map[string]bool {
+	out := map[string]bool{}
+	for _, n := range named {
+		out[n.Name.Value] = true
+	}
+	return out
+}
+
+func fieldDefs(fields []*ast.FieldDefinition) map[string]fieldDef {
+	out := map[string]fieldDef{}
+	for _, f := range fields {
+		out[f.Name.Value] = fieldDef{typ: f.Type, args: inputDefs(f.Arguments)}
+	}
+	return out
+}
+
+func inputDefs(values []*ast.InputValueDefinition) map[string]inputDef {
+	out := map[string]inputDef{}
+	for _, v := range values {
+		d := inputDef{typ: v.Type}
+		if v.DefaultValue != nil {
+			d.def = printNode(v.DefaultValue)
+		}
+		out[v.Name.Value] = d
+	}
+	return out
+}
+
+func printNode(n ast.Node) string {
+	s, _ := printer.Print(n).(string)
+	return s
+}
+
+type differ struct {
+	changes []Change
+}
+
+func (d *differ) add(level Level, path, format string, args ...interface{}) {
+	d.changes = append(d.changes, Change{Level: level, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (d *differ) typeDef(name string, o, n typeDef) {
+	switch o.kind {
+	case "enum":
+		for v := range o.values {
+			if !n.values[v] {
+				d.add(Breaking, name+"."+v, "enum value %s was removed", v)
+			}
+		}
+		for v := range n.values {
+			if !o.values[v] {
+				d.add(Dangerous, name+"."+v, "enum value %s was added", v)
+			}
+		}
+	case "union":
+		for m := range o.members {
+			if !n.members[m] {
+				d.add(Breaking, name, "%s was removed from the union", m)
+			}
+		}
+		for m := range n.members {
+			if !o.members[m] {
+				d.add(Dangerous, name, "%s was added to the union", m)
+			}
+		}
+	case "input":
+		d.inputs(name, "input field", o.inputs, n.inputs)
+	case "type", "interface":
+		for i := range o.interfaces {
+			if !n.interfaces[i] {
+				d.add(Breaking, name, "%s no longer implements %s", name, i)
+			}
+		}
+		for i := range n.interfaces {
+			if !o.interfaces[i] {
+				d.add(Dangerous, name, "%s now implements %s", name, i)
+			}
+		}
+		d.fields(name, o.fields, n.fields)
+	}
+}
+
+func (d *differ) fields(typeName string, o, n map[string]fieldDef) {
+	for name, of := range o {
+		path := typeName + "." + name
+		nf, ok := n[name]
+		if !ok {
+			d.add(Breaking, path, "field was removed")
+			continue
+		}
+		if !safeOutputChange(of.typ, nf.typ) {
+			d.add(Breaking, path, "type changed from %s to %s", printNode(of.typ), printNode(nf.typ))
+		} else if printNode(of.typ) != printNode(nf.typ) {
+			d.add(Safe, path, "type changed from %s to %s", printNode(of.typ), printNode(nf.typ))
+		}
+		d.inputs(path, "argument", of.args, nf.args)
+	}
+	for name := range n {
+		if _, ok := o[name]; !ok {
+			d.add(Safe, typeName+"."+name, "field was added")
+		}
+	}
+}
+
+// inputs compares the args of a field or the fields of an input object.  Both are only ever written
+// by clients, so the rules are the same.
+func (d *differ) inputs(parent, noun string, o, n map[string]inputDef) {
+	path := func(name string) string {
+		if noun == "argument" {
+			return fmt.Sprintf("%s(%s)", parent, name)
+		}
+		return parent + "." + name
+	}
+	for name, oi := range o {
+		ni, ok := n[name]
+		if !ok {
+			d.add(Breaking, path(name), "%s was removed", noun)
+			continue
+		}
+		if !safeOutputChange(ni.typ, oi.typ) {
+			d.add(Breaking, path(name), "type changed from %s to %s", printNode(oi.typ), printNode(ni.typ))
+		} else if printNode(oi.typ) != printNode(ni.typ) {
+			d.add(Safe, path(name), "type changed from %s to %s", printNode(oi.typ), printNode(ni.typ))
+		}
+		if oi.def != ni.def {
+			d.add(Dangerous, path(name), "default value changed from %s to %s", orNone(oi.def), orNone(ni.def))
+		}
+	}
+	for name, ni := range n {
+		if _, ok := o[name]; ok {
+			continue
+		}
+		if _, nonNull := ni.typ.(*ast.NonNull); nonNull && ni.def == "" {
+			d.add(Breaking, path(name), "required %s was added", noun)
+		} else {
+			d.add(Safe, path(name), "optional %s was added", noun)
+		}
+	}
+}
+
+func orNone(def string) string {
+	if def == "" {
+		return "none"
+	}
+	return def
+}
+
+// safeOutputChange reports whether a field whose type changes from o to n still returns something
+// every existing client can handle: the same type, or a non-null version of it.  An input changing
+// from o to n is safe if the reverse change would be safe for an output.
+func safeOutputChange(o, n ast.Type) bool {
+	if nn, ok := n.(*ast.NonNull); ok {
+		if on, ok := o.(*ast.NonNull); ok {
+			return safeOutputChange(on.Type, nn.Type)
+		}
+		return safeOutputChange(o, nn.Type)
+	}
+	switch o := o.(type) {
+	case *ast.NonNull:
+		return false
+	case *ast.List:
+		nl, ok := n.(*ast.List)
+		return ok && safeOutputChange(o.Type, nl.Type)
+	case *ast.Named:
+		nn, ok := n.(*ast.Named)
+		return ok && o.Name.Value == nn.Name.Value
+	}
+	return false
+}
@@ -0,0 +1,308 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// builtinScalars are left out of printed SDL, since every schema has them.
+var builtinScalars = map[string]bool{
+	"String":  true,
+	"Int":     true,
+	"Float":   true,
+	"Boolean": true,
+	"ID":      true,
+}
+
+// SDL returns the GraphQL schema definition language for every type the loader has registered or
+// generated (plus the types they refer to), sorted by name, after the definitions of any directives
+// registered with InSDL set.  It is meant to be checked in and diffed, so the output is stable for
+// a given set of types.
+func (e *ArgLoader) SDL() string {
+	types := map[string]graphql.Type{}
+	for _, t := range e.typeNames {
+		collectTypes(t, types)
+	}
+	for _, t := range e.outputTypes {
+		collectTypes(t, types)
+	}
+	return e.directiveDefinitions() + printTypes(types, e.inputFieldDirectives)
+}
+
+// SDL returns the SDL for every type known to the default loader.
+func SDL() string {
+	return Default().SDL()
+}
+
+// PrintSchema returns the GraphQL schema definition language for a whole schema: a schema block if
+// the root types aren't named Query, Mutation and Subscription, followed by every type in the
+// schema other than the built-in scalars and introspection types, sorted by name.
+func PrintSchema(schema graphql.Schema) string {
+	types := map[string]graphql.Type{}
+	for name, t := range schema.TypeMap() {
+		if strings.HasPrefix(name, "__") {
+			continue
+		}
+		types[name] = t
+	}
+	var b strings.Builder
+	roots := []struct {
+		op  string
+		obj *graphql.Object
+		def string
+	}{
+		{"query", schema.QueryType(), "Query"},
+		{"mutation", schema.MutationType(), "Mutation"},
+		{"subscription", schema.SubscriptionType(), "Subscription"},
+	}
+	custom := false
+	for _, r := range roots {
+		if r.obj != nil && r.obj.Name() != r.def {
+			custom = true
+		}
+	}
+	if custom {
+		b.WriteString("schema {\n")
+		for _, r := range roots {
+			if r.obj != nil {
+				fmt.Fprintf(&b, "  %s: %s\n", r.op, r.obj.Name())
+			}
+		}
+		b.WriteString("}\n\n")
+	}
+	b.WriteString(printTypes(types, nil))
+	return b.String()
+}
+
+// collectTypes adds t and every named type reachable from it to types.
+func collectTypes(t graphql.Type, types map[string]graphql.Type) {
+	switch t := t.(type) {
+	case *graphql.List:
+		collectTypes(t.OfType, types)
+		return
+	case *graphql.NonNull:
+		collectTypes(t.OfType, types)
+		return
+	}
+	if t == nil || reflect.ValueOf(t).IsNil() {
+		return
+	}
+	if _, seen := types[t.Name()]; seen {
+		return
+	}
+	types[t.Name()] = t
+	switch t := t.(type) {
+	case *graphql.Object:
+		for _, i := range t.Interfaces() {
+			collectTypes(i, types)
+		}
+		for _, f := range t.Fields() {
+			collectFieldTypes(f, types)
+		}
+	case *graphql.Interface:
+		for _, f := range t.Fields() {
+			collectFieldTypes(f, types)
+		}
+	case *graphql.Union:
+		for _, o := range t.Types() {
+			collectTypes(o, types)
+		}
+	case *graphql.InputObject:
+		for _, f := range t.Fields() {
+			collectTypes(f.Type, types)
+		}
+	}
+}
+
+func collectFieldTypes(f *graphql.FieldDefinition, types map[string]graphql.Type) {
+	collectTypes(f.Type, types)
+	for _, a := range f.Args {
+		collectTypes(a.Type, types)
+	}
+}
+
+// printTypes prints the definitions of types.  directives holds the names of the directives to
+// apply to input object fields, keyed by "Type.field".
+func printTypes(types map[string]graphql.Type, directives map[string][]string) string {
+	names := make([]string, 0, len(types))
+	for name := range types {
+		if !builtinScalars[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	defs := make([]string, 0, len(names))
+	for _, name := range names {
+		defs = append(defs, printType(types[name], directives))
+	}
+	if len(defs) == 0 {
+		return ""
+	}
+	return strings.Join(defs, "\n\n") + "\n"
+}
+
+func printType(t graphql.Type, directives map[string][]string) string {
+	var b strings.Builder
+	printDescription(&b, "", t.Description())
+	switch t := t.(type) {
+	case *graphql.Scalar:
+		fmt.Fprintf(&b, "scalar %s", t.Name())
+	case *graphql.Enum:
+		fmt.Fprintf(&b, "enum %s {\n", t.Name())
+		values := t.Values()
+		sorted := make([]*graphql.EnumValueDefinition, len(values))
+		copy(sorted, values)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+		for _, v := range sorted {
+			printDescription(&b, "  ", v.Description)
+			fmt.Fprintf(&b, "  %s%s\n", v.Name, printDeprecation(v.DeprecationReason))
+		}
+		b.WriteString("}")
+	case *graphql.InputObject:
+		fmt.Fprintf(&b, "input %s {\n", t.Name())
+		fields := t.Fields()
+		for _, name := range sortedKeys(fields) {
+			f := fields[name]
+			printDescription(&b, "  ", f.Description())
+			fmt.Fprintf(&b, "  %s: %s%s", name, f.Type.String(), printDefault(f.DefaultValue, f.Type))
+			for _, d := range directives[t.Name()+"."+name] {
+				b.WriteString(" @" + d)
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("}")
+	case *graphql.Object:
+		fmt.Fprintf(&b, "type %s", t.Name())
+		if ifaces := t.Interfaces(); len(ifaces) > 0 {
+			names := make([]string, len(ifaces))
+			for i, iface := range ifaces {
+				names[i] = iface.Name()
+			}
+			sort.Strings(names)
+			b.WriteString(" implements " + strings.Join(names, " & "))
+		}
+		b.WriteString(" {\n")
+		printFields(&b, t.Fields())
+		b.WriteString("}")
+	case *graphql.Interface:
+		fmt.Fprintf(&b, "interface %s {\n", t.Name())
+		printFields(&b, t.Fields())
+		b.WriteString("}")
+	case *graphql.Union:
+		names := make([]string, 0, len(t.Types()))
+		for _, o := range t.Types() {
+			names = append(names, o.Name())
+		}
+		sort.Strings(names)
+		fmt.Fprintf(&b, "union %s = %s", t.Name(), strings.Join(names, " | "))
+	}
+	return b.String()
+}
+
+func printFields(b *strings.Builder, fields graphql.FieldDefinitionMap) {
+	for _, name := range sortedKeys(fields) {
+		f := fields[name]
+		printDescription(b, "  ", f.Description)
+		fmt.Fprintf(b, "  %s", name)
+		if len(f.Args) > 0 {
+			args := make([]*graphql.Argument, len(f.Args))
+			copy(args, f.Args)
+			sort.Slice(args, func(i, j int) bool { return args[i].Name() < args[j].Name() })
+			parts := make([]string, len(args))
+			for i, a := range args {
+				parts[i] = fmt.Sprintf("%s: %s%s", a.Name(), a.Type.String(), printDefault(a.DefaultValue, a.Type))
+			}
+			b.WriteString("(" + strings.Join(parts, ", ") + ")")
+		}
+		fmt.Fprintf(b, ": %s%s\n", f.Type.String(), printDeprecation(f.DeprecationReason))
+	}
+}
+
+func printDescription(b *strings.Builder, indent, desc string) {
+	if desc == "" {
+		return
+	}
+	if !strings.Contains(desc, "\n") {
+		fmt.Fprintf(b, "%s%s\n", indent, strconv.Quote(desc))
+		return
+	}
+	fmt.Fprintf(b, "%s\"\"\"\n", indent)
+	for _, line := range strings.Split(desc, "\n") {
+		fmt.Fprintf(b, "%s%s\n", indent, line)
+	}
+	fmt.Fprintf(b, "%s\"\"\"\n", indent)
+}
+
+func printDeprecation(reason string) string {
+	if reason == "" {
+		return ""
+	}
+	return fmt.Sprintf(" @deprecated(reason: %s)", strconv.Quote(reason))
+}
+
+func printDefault(v interface{}, t graphql.Type) string {
+	if v == nil {
+		return ""
+	}
+	return " = " + printValue(v, t)
+}
+
+// printValue prints a Go value as a graphql literal of type t.
+func printValue(v interface{}, t graphql.Type) string {
+	switch t := t.(type) {
+	case *graphql.NonNull:
+		return printValue(v, t.OfType)
+	case *graphql.List:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice {
+			return printValue(v, t.OfType)
+		}
+		items := make([]string, rv.Len())
+		for i := range items {
+			items[i] = printValue(rv.Index(i).Interface(), t.OfType)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case *graphql.Enum:
+		if name, ok := t.Serialize(v).(string); ok {
+			return name
+		}
+	case *graphql.InputObject:
+		if m, ok := v.(map[string]interface{}); ok {
+			fields := t.Fields()
+			var parts []string
+			for _, name := range sortedKeys(m) {
+				var fieldType graphql.Type
+				if f, ok := fields[name]; ok {
+					fieldType = f.Type
+				}
+				parts = append(parts, name+": "+printValue(m[name], fieldType))
+			}
+			return "{" + strings.Join(parts, ", ") + "}"
+		}
+	case *graphql.Scalar:
+		v = t.Serialize(v)
+	}
+	switch v := v.(type) {
+	case string:
+		return strconv.Quote(v)
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// sortedKeys returns the keys of any map with string keys, sorted.
+func sortedKeys(m interface{}) []string {
+	keys := reflect.ValueOf(m).MapKeys()
+	out := make([]string, len(keys))
+	for i, k := range keys {
+		out[i] = k.String()
+	}
+	sort.Strings(out)
+	return out
+}
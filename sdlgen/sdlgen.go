@@ -0,0 +1,298 @@
+// Package sdlgen generates Go arg structs from a GraphQL SDL document, for schema-first services
+// that want to load arguments with graphqlhelpers.
+//
+// Every input type becomes a struct with arg tags, every field with arguments gets an args struct
+// named after its parent type and itself (so Query.user(...) becomes QueryUserArgs), and every enum
+// becomes a string type with constants, a graphql.Enum, and a loader func.  A RegisterTypes func
+// registers the generated enums on an ArgLoader.
+package sdlgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// Config controls code generation.
+type Config struct {
+	// Package is the name of the generated package.
+	Package string
+
+	// Scalars maps custom scalar names to the Go types used for them, like "UUID": "uuid.UUID".
+	// The types must be registered on the ArgLoader separately.  String, Int, Float, Boolean, ID and
+	// DateTime are always mapped.
+	Scalars map[string]string
+
+	// Imports are extra import paths needed by the Scalars types.
+	Imports []string
+}
+
+var builtinScalars = map[string]string{
+	"String":   "string",
+	"ID":       "string",
+	"Int":      "int",
+	"Float":    "float64",
+	"Boolean":  "bool",
+	"DateTime": "time.Time",
+}
+
+// Generate returns formatted Go source for the types in sdl.
+func Generate(sdl []byte, conf Config) ([]byte, error) {
+	doc, err := parser.Parse(parser.ParseParams{Source: string(sdl)})
+	if err != nil {
+		return nil, err
+	}
+	g := &generator{conf: conf, enums: map[string]bool{}, inputs: map[string]bool{}}
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *ast.EnumDefinition:
+			g.enums[def.Name.Value] = true
+		case *ast.InputObjectDefinition:
+			g.inputs[def.Name.Value] = true
+		}
+	}
+
+	var enumNames []string
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *ast.EnumDefinition:
+			g.enum(def)
+			enumNames = append(enumNames, def.Name.Value)
+		case *ast.InputObjectDefinition:
+			if err := g.structType(def.Name.Value, description(def.Description), def.Fields); err != nil {
+				return nil, err
+			}
+		case *ast.ObjectDefinition:
+			for _, f := range def.Fields {
+				if len(f.Arguments) == 0 {
+					continue
+				}
+				name := exportedName(def.Name.Value) + exportedName(f.Name.Value) + "Args"
+				desc := fmt.Sprintf("%s holds the arguments of %s.%s.", name, def.Name.Value, f.Name.Value)
+				if err := g.structType(name, desc, f.Arguments); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	g.registerFunc(enumNames)
+	return g.source()
+}
+
+type generator struct {
+	conf   Config
+	enums  map[string]bool
+	inputs map[string]bool
+	body   bytes.Buffer
+
+	usesTime    bool
+	usesGraphql bool
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.body, format, args...)
+}
+
+func (g *generator) comment(text string) {
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		g.printf("// %s\n", strings.TrimSpace(line))
+	}
+}
+
+func (g *generator) enum(def *ast.EnumDefinition) {
+	g.usesGraphql = true
+	name := def.Name.Value
+	typeName := exportedName(name)
+	if desc := description(def.Description); desc != "" {
+		g.comment(desc)
+	} else {
+		g.comment(fmt.Sprintf("%s is the %s enum.", typeName, name))
+	}
+	g.printf("type %s string\n\n", typeName)
+	g.printf("// The %s values.\nconst (\n", typeName)
+	for _, v := range def.Values {
+		g.printf("\t%s %s = %s\n", typeName+enumValueName(v.Name.Value), typeName, strconv.Quote(v.Name.Value))
+	}
+	g.printf(")\n\n")
+
+	g.printf("// %sEnum is the graphql type of %s.\n", typeName, typeName)
+	g.printf("var %sEnum = graphql.NewEnum(graphql.EnumConfig{\n", typeName)
+	g.printf("\tName: %s,\n", strconv.Quote(name))
+	if desc := description(def.Description); desc != "" {
+		g.printf("\tDescription: %s,\n", strconv.Quote(desc))
+	}
+	g.printf("\tValues: graphql.EnumValueConfigMap{\n")
+	for _, v := range def.Values {
+		g.printf("\t\t%s: &graphql.EnumValueConfig{Value: %s", strconv.Quote(v.Name.Value), typeName+enumValueName(v.Name.Value))
+		if desc := description(v.Description); desc != "" {
+			g.printf(", Description: %s", strconv.Quote(desc))
+		}
+		g.printf("},\n")
+	}
+	g.printf("\t},\n})\n\n")
+
+	g.printf("// Load%s loads a %s argument.\n", typeName, typeName)
+	g.printf("func Load%s(i interface{}) (%s, error) {\n", typeName, typeName)
+	g.printf("\tswitch v := i.(type) {\n\tcase %s:\n\t\treturn v, nil\n", typeName)
+	g.printf("\tcase string:\n\t\treturn %s(v), nil\n\t}\n", typeName)
+	g.printf("\treturn \"\", fmt.Errorf(\"%%v is not a %s\", i)\n}\n\n", typeName)
+}
+
+func (g *generator) structType(name, desc string, fields []*ast.InputValueDefinition) error {
+	typeName := exportedName(name)
+	if desc != "" {
+		g.comment(desc)
+	} else {
+		g.comment(fmt.Sprintf("%s is the %s input type.", typeName, name))
+	}
+	g.printf("type %s struct {\n", typeName)
+	for _, f := range fields {
+		goType, required, err := g.goType(f.Type, true)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %v", name, f.Name.Value, err)
+		}
+		tag := fmt.Sprintf(`arg:"%s"`, f.Name.Value)
+		if required {
+			tag += ` required:"true"`
+		}
+		if d := description(f.Description); d != "" {
+			tag += fmt.Sprintf(` desc:%s`, strconv.Quote(strings.Join(strings.Fields(d), " ")))
+		}
+		g.printf("\t%s %s `%s`\n", exportedName(f.Name.Value), goType, tag)
+	}
+	g.printf("}\n\n")
+	return nil
+}
+
+// goType returns the Go type for a graphql type reference, and whether it is non-null.  Input
+// object fields are pointers unless they are lists, so that recursive input types compile.
+func (g *generator) goType(t ast.Type, top bool) (string, bool, error) {
+	switch t := t.(type) {
+	case *ast.NonNull:
+		inner, _, err := g.goType(t.Type, top)
+		return inner, true, err
+	case *ast.List:
+		inner, _, err := g.goType(t.Type, false)
+		return "[]" + strings.TrimPrefix(inner, "*"), false, err
+	case *ast.Named:
+		name := t.Name.Value
+		if goType, ok := g.conf.Scalars[name]; ok {
+			return goType, false, nil
+		}
+		if goType, ok := builtinScalars[name]; ok {
+			if goType == "time.Time" {
+				g.usesTime = true
+			}
+			return goType, false, nil
+		}
+		if g.enums[name] {
+			return exportedName(name), false, nil
+		}
+		if g.inputs[name] {
+			return "*" + exportedName(name), false, nil
+		}
+		return "", false, fmt.Errorf("no Go type for %s. map custom scalars with Config.Scalars", name)
+	}
+	return "", false, fmt.Errorf("unsupported type %v", t)
+}
+
+func (g *generator) registerFunc(enumNames []string) {
+	g.printf("// RegisterTypes registers the generated enum types on l.\n")
+	g.printf("func RegisterTypes(l *graphqlhelpers.ArgLoader) error {\n")
+	if len(enumNames) > 0 {
+		g.usesGraphql = true
+		g.printf("\tfor _, r := range []struct {\n\t\tloader interface{}\n\t\tgqlType graphql.Output\n\t}{\n")
+		for _, name := range enumNames {
+			typeName := exportedName(name)
+			g.printf("\t\t{Load%s, %sEnum},\n", typeName, typeName)
+		}
+		g.printf("\t} {\n\t\tif err := l.Register(r.loader, r.gqlType); err != nil {\n\t\t\treturn err\n\t\t}\n\t}\n")
+	}
+	g.printf("\treturn nil\n}\n")
+}
+
+func (g *generator) source() ([]byte, error) {
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by sdlgen. DO NOT EDIT.\n\npackage %s\n\n", g.conf.Package)
+	imports := append([]string{}, g.conf.Imports...)
+	if g.usesGraphql {
+		imports = append(imports, "fmt", "github.com/graphql-go/graphql")
+	}
+	if g.usesTime {
+		imports = append(imports, "time")
+	}
+	imports = append(imports, "github.com/btubbs/graphql-go-helpers")
+	sort.Strings(imports)
+	// standard library imports first, then everything else.
+	var std, other []string
+	for _, imp := range imports {
+		if strings.Contains(strings.SplitN(imp, "/", 2)[0], ".") {
+			other = append(other, imp)
+		} else {
+			std = append(std, imp)
+		}
+	}
+	out.WriteString("import (\n")
+	for _, imp := range std {
+		fmt.Fprintf(&out, "\t%q\n", imp)
+	}
+	if len(std) > 0 {
+		out.WriteString("\n")
+	}
+	for _, imp := range other {
+		if imp == "github.com/btubbs/graphql-go-helpers" {
+			fmt.Fprintf(&out, "\tgraphqlhelpers %q\n", imp)
+			continue
+		}
+		fmt.Fprintf(&out, "\t%q\n", imp)
+	}
+	out.WriteString(")\n\n")
+	out.Write(g.body.Bytes())
+	formatted, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid code: %v\n%s", err, out.Bytes())
+	}
+	return formatted, nil
+}
+
+func description(s *ast.StringValue) string {
+	if s == nil {
+		return ""
+	}
+	return s.Value
+}
+
+// exportedName turns a graphql name into an exported Go identifier.
+func exportedName(s string) string {
+	if s == "" {
+		return s
+	}
+	runes := []rune(s)
+	runes[0] = unicode.ToUpper(runes[0])
+	out := string(runes)
+	if strings.HasSuffix(out, "Id") {
+		out = strings.TrimSuffix(out, "Id") + "ID"
+	}
+	return out
+}
+
+// enumValueName turns an UPPER_SNAKE enum value into the CamelCase suffix of its Go constant.
+func enumValueName(s string) string {
+	var b strings.Builder
+	for _, word := range strings.Split(strings.ToLower(s), "_") {
+		if word == "" {
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
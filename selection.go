@@ -0,0 +1,274 @@
+package graphqlhelpers
+
+import (
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// FieldSet is the tree of fields a client selected beneath a field, keyed by field name rather
+// than alias.  Fields selected more than once, under different aliases or in several fragments,
+// appear once with their sub-selections merged.  Fields skipped with @skip or @include don't
+// appear.  The zero FieldSet is empty.
+type FieldSet struct {
+	typ    graphql.Type
+	schema *graphql.Schema
+	fields map[string]*selectedField
+	order  []string
+}
+
+// selectedField is a field in a FieldSet.  A field selected directly, or in a fragment that applies
+// to every value of the FieldSet's type, is selected for all types; a field only selected in
+// fragments on some of the members of a union or interface is selected for those members.
+type selectedField struct {
+	all   bool
+	types map[string]bool
+	set   FieldSet
+}
+
+// SelectedFields returns the fields selected beneath the field being resolved, looking through
+// inline fragments and fragment spreads, so resolvers can fetch only what the client asked for:
+//
+//	fields := graphqlhelpers.SelectedFields(p)
+//	if fields.Has("address.country") {
+//		...join the countries table...
+//	}
+//
+// On a field returning a union or interface, the fields of fragments on particular types are
+// included too, and OnType narrows the set to those selected for one of them.
+func SelectedFields(p graphql.ResolveParams) FieldSet {
+	schema := p.Info.Schema
+	set := newFieldSet(namedType(p.Info.ReturnType), &schema)
+	c := selectionCollector{info: p.Info, visiting: map[string]bool{}}
+	for _, field := range p.Info.FieldASTs {
+		c.collect(&set, set.typ, "", field.SelectionSet)
+	}
+	return set
+}
+
+func newFieldSet(t graphql.Type, schema *graphql.Schema) FieldSet {
+	return FieldSet{typ: t, schema: schema, fields: map[string]*selectedField{}}
+}
+
+// TypeName returns the name of the type the fields were selected on, or "" for the zero FieldSet.
+func (s FieldSet) TypeName() string {
+	if s.typ == nil {
+		return ""
+	}
+	return s.typ.Name()
+}
+
+// Fields returns the names of the selected fields, in the order they were first selected.
+func (s FieldSet) Fields() []string {
+	return append([]string(nil), s.order...)
+}
+
+// Has reports whether the field at path, a dot-separated list of field names like
+// "user.address", was selected.
+func (s FieldSet) Has(path string) bool {
+	_, ok := s.lookup(path)
+	return ok
+}
+
+// Sub returns the fields selected beneath the field at path, or an empty FieldSet if it wasn't
+// selected.
+func (s FieldSet) Sub(path string) FieldSet {
+	f, _ := s.lookup(path)
+	if f == nil {
+		return FieldSet{}
+	}
+	return f.set
+}
+
+// OnType returns the fields selected for values of the object type named typeName: those selected
+// for every type, plus those in fragments on typeName, on an interface it implements or on a union
+// it belongs to.  The returned set's TypeName is typeName.
+func (s FieldSet) OnType(typeName string) FieldSet {
+	out := FieldSet{typ: s.typ, schema: s.schema, fields: map[string]*selectedField{}}
+	var obj *graphql.Object
+	if s.schema != nil {
+		obj, _ = s.schema.Type(typeName).(*graphql.Object)
+	}
+	if obj != nil {
+		out.typ = obj
+	}
+	for _, name := range s.order {
+		f := s.fields[name]
+		if f.all || f.types[typeName] || s.appliesTo(f.types, obj) {
+			out.fields[name] = &selectedField{all: true, set: f.set}
+			out.order = append(out.order, name)
+		}
+	}
+	return out
+}
+
+// appliesTo reports whether any of the abstract types named in types covers obj.
+func (s FieldSet) appliesTo(types map[string]bool, obj *graphql.Object) bool {
+	if obj == nil {
+		return false
+	}
+	for name := range types {
+		if coversType(s.schema.Type(name), obj) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s FieldSet) lookup(path string) (*selectedField, bool) {
+	var f *selectedField
+	for _, name := range strings.Split(path, ".") {
+		var ok bool
+		if f, ok = s.fields[name]; !ok {
+			return nil, false
+		}
+		s = f.set
+	}
+	return f, f != nil
+}
+
+// add records that the field name was selected, in a fragment on the type named cond or, if cond
+// is empty, for every type, and returns it so its sub-selections can be added.
+func (s *FieldSet) add(name, cond string, t graphql.Type) *selectedField {
+	f, ok := s.fields[name]
+	if !ok {
+		f = &selectedField{types: map[string]bool{}, set: newFieldSet(t, s.schema)}
+		s.fields[name] = f
+		s.order = append(s.order, name)
+	}
+	if cond == "" {
+		f.all = true
+	} else {
+		f.types[cond] = true
+	}
+	return f
+}
+
+// selectionCollector builds FieldSets from the selections of a query.  visiting holds the
+// fragments being collected, so that fragment cycles end.
+type selectionCollector struct {
+	info     graphql.ResolveInfo
+	visiting map[string]bool
+}
+
+// collect adds the fields of sel to set.  scope is the type the selections are made on, which
+// differs from set's type inside fragments, and cond is the type condition the fields are selected
+// under, or "" if they apply to every value of set's type.
+func (c selectionCollector) collect(set *FieldSet, scope graphql.Type, cond string, sel *ast.SelectionSet) {
+	if sel == nil {
+		return
+	}
+	for _, s := range sel.Selections {
+		switch s := s.(type) {
+		case *ast.Field:
+			if !c.included(s.Directives) {
+				continue
+			}
+			t := fieldType(scope, s.Name.Value)
+			f := set.add(s.Name.Value, cond, t)
+			c.collect(&f.set, t, "", s.SelectionSet)
+		case *ast.InlineFragment:
+			if !c.included(s.Directives) {
+				continue
+			}
+			fragScope, fragCond := c.narrow(set, scope, cond, s.TypeCondition)
+			c.collect(set, fragScope, fragCond, s.SelectionSet)
+		case *ast.FragmentSpread:
+			name := s.Name.Value
+			frag, ok := c.info.Fragments[name].(*ast.FragmentDefinition)
+			if !ok || c.visiting[name] || !c.included(s.Directives) {
+				continue
+			}
+			c.visiting[name] = true
+			fragScope, fragCond := c.narrow(set, scope, cond, frag.TypeCondition)
+			c.collect(set, fragScope, fragCond, frag.SelectionSet)
+			delete(c.visiting, name)
+		}
+	}
+}
+
+// narrow returns the scope and type condition of the selections in a fragment on typeCond.
+func (c selectionCollector) narrow(set *FieldSet, scope graphql.Type, cond string, typeCond *ast.Named) (graphql.Type, string) {
+	if typeCond == nil || typeCond.Name == nil {
+		return scope, cond
+	}
+	t := c.info.Schema.Type(typeCond.Name.Value)
+	if t == nil {
+		return scope, cond
+	}
+	if set.typ != nil && t.Name() == set.typ.Name() {
+		return t, cond
+	}
+	if obj, ok := set.typ.(*graphql.Object); ok && coversType(t, obj) {
+		return t, cond
+	}
+	// a fragment on an interface inside a fragment on one of its implementations doesn't widen the
+	// condition.
+	if obj, ok := c.info.Schema.Type(cond).(*graphql.Object); ok && coversType(t, obj) {
+		return t, cond
+	}
+	return t, t.Name()
+}
+
+// included applies the @skip and @include directives.
+func (c selectionCollector) included(directives []*ast.Directive) bool {
+	for _, d := range directives {
+		if d.Name == nil || len(d.Arguments) == 0 {
+			continue
+		}
+		var value bool
+		switch v := d.Arguments[0].Value.(type) {
+		case *ast.BooleanValue:
+			value = v.Value
+		case *ast.Variable:
+			value, _ = c.info.VariableValues[v.Name.Value].(bool)
+		}
+		if (d.Name.Value == "skip" && value) || (d.Name.Value == "include" && !value) {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldType returns the named type of the field name of t, or nil if t has no such field.
+func fieldType(t graphql.Type, name string) graphql.Type {
+	var fields graphql.FieldDefinitionMap
+	switch t := t.(type) {
+	case *graphql.Object:
+		fields = t.Fields()
+	case *graphql.Interface:
+		fields = t.Fields()
+	}
+	if f, ok := fields[name]; ok {
+		return namedType(f.Type)
+	}
+	return nil
+}
+
+func namedType(t graphql.Type) graphql.Type {
+	named, _ := graphql.GetNamed(t).(graphql.Type)
+	return named
+}
+
+// coversType reports whether every value of type obj is also of type t: whether t is obj, an
+// interface obj implements or a union obj belongs to.
+func coversType(t graphql.Type, obj *graphql.Object) bool {
+	switch t := t.(type) {
+	case *graphql.Object:
+		return t.Name() == obj.Name()
+	case *graphql.Interface:
+		for _, iface := range obj.Interfaces() {
+			if iface.Name() == t.Name() {
+				return true
+			}
+		}
+	case *graphql.Union:
+		for _, member := range t.Types() {
+			if member.Name() == obj.Name() {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Semaphore limits how many resolver calls run at once.  Pass the same Semaphore to the fields of a
+// group, like every field that queries one database, to limit them together.
+type Semaphore struct {
+	slots chan struct{}
+}
+
+// NewSemaphore returns a Semaphore letting n calls run at once.  It panics if n isn't positive.
+func NewSemaphore(n int) *Semaphore {
+	if n <= 0 {
+		panic(fmt.Sprintf("a semaphore needs at least 1 slot, not %d", n))
+	}
+	return &Semaphore{slots: make(chan struct{}, n)}
+}
+
+// Limit returns middleware letting the resolvers it wraps run only while they hold one of s's
+// slots.  Calls wait for a slot to free up, and fail with the context's error if the request's
+// context is done first.
+func Limit(s *Semaphore) Middleware {
+	return func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (interface{}, error) {
+			ctx := nodeContext(p)
+			select {
+			case s.slots <- struct{}{}:
+			case <-ctx.Done():
+				return nil, fmt.Errorf("waiting to resolve %s: %w", FieldPath(p.Info), ctx.Err())
+			}
+			defer func() { <-s.slots }()
+			return next(p)
+		}
+	}
+}
+
+// WithConcurrencyLimit lets at most n calls of the field's resolver run at once, across all
+// requests, with Limit middleware inside any middleware installed on the loader with Use.
+func WithConcurrencyLimit(n int) FieldOption {
+	return WithMiddleware(Limit(NewSemaphore(n)))
+}
+
+// WithSemaphore limits the field's resolver with s, which it may share with other fields, as Limit
+// does.
+func WithSemaphore(s *Semaphore) FieldOption {
+	return WithMiddleware(Limit(s))
+}
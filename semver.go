@@ -0,0 +1,214 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// SemVer is a semantic version, as defined by https://semver.org.  After RegisterSemVer, args and
+// results of type SemVer use the SemVer scalar.
+type SemVer struct {
+	Major, Minor, Patch uint64
+	// Prerelease holds the dot-separated identifiers after the "-", like "rc.1", or "".
+	Prerelease string
+	// Build holds the dot-separated build metadata after the "+", or "".  It is ignored when
+	// comparing versions.
+	Build string
+}
+
+// ParseSemVer parses a semantic version like "1.4.2" or "2.0.0-rc.1+build.5".  A leading "v" is
+// allowed and dropped.
+func ParseSemVer(s string) (SemVer, error) {
+	rest := strings.TrimPrefix(s, "v")
+	var v SemVer
+	if i := strings.IndexByte(rest, '+'); i >= 0 {
+		v.Build, rest = rest[i+1:], rest[:i]
+		if err := checkSemVerIdentifiers(v.Build, false); err != nil {
+			return SemVer{}, fmt.Errorf("%s is not a semantic version: build metadata %v", s, err)
+		}
+	}
+	if i := strings.IndexByte(rest, '-'); i >= 0 {
+		v.Prerelease, rest = rest[i+1:], rest[:i]
+		if err := checkSemVerIdentifiers(v.Prerelease, true); err != nil {
+			return SemVer{}, fmt.Errorf("%s is not a semantic version: prerelease %v", s, err)
+		}
+	}
+	parts := strings.Split(rest, ".")
+	if len(parts) != 3 {
+		return SemVer{}, fmt.Errorf("%s is not a semantic version: it must have major, minor and patch numbers", s)
+	}
+	nums := make([]uint64, 3)
+	for i, part := range parts {
+		if !isNumericIdentifier(part) {
+			return SemVer{}, fmt.Errorf("%s is not a semantic version: %q is not a number without leading zeros", s, part)
+		}
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return SemVer{}, fmt.Errorf("%s is not a semantic version: %v", s, err)
+		}
+		nums[i] = n
+	}
+	v.Major, v.Minor, v.Patch = nums[0], nums[1], nums[2]
+	return v, nil
+}
+
+// checkSemVerIdentifiers checks dot-separated prerelease or build identifiers.  Numeric prerelease
+// identifiers must not have leading zeros.
+func checkSemVerIdentifiers(s string, prerelease bool) error {
+	for _, id := range strings.Split(s, ".") {
+		if id == "" {
+			return fmt.Errorf("identifiers must not be empty")
+		}
+		for _, r := range id {
+			if (r < '0' || r > '9') && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && r != '-' {
+				return fmt.Errorf("%q may only contain letters, digits and hyphens", id)
+			}
+		}
+		if prerelease && isDigits(id) && !isNumericIdentifier(id) {
+			return fmt.Errorf("%q must not have leading zeros", id)
+		}
+	}
+	return nil
+}
+
+// isNumericIdentifier reports whether s is a number without leading zeros.
+func isNumericIdentifier(s string) bool {
+	return s != "" && isDigits(s) && (s == "0" || s[0] != '0')
+}
+
+// String returns the version without a leading "v", like "2.0.0-rc.1+build.5".
+func (v SemVer) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// Compare returns -1 if v has lower precedence than other, 1 if it has higher precedence, and 0 if
+// they have the same precedence.  Prereleases come before their release, and build metadata is
+// ignored, so 1.0.0-rc.1 < 1.0.0 and 1.0.0+a == 1.0.0+b.
+func (v SemVer) Compare(other SemVer) int {
+	for _, pair := range [][2]uint64{{v.Major, other.Major}, {v.Minor, other.Minor}, {v.Patch, other.Patch}} {
+		if pair[0] != pair[1] {
+			if pair[0] < pair[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case v.Prerelease == other.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case other.Prerelease == "":
+		return -1
+	}
+	a, b := strings.Split(v.Prerelease, "."), strings.Split(other.Prerelease, ".")
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := comparePrereleaseIdentifiers(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+	return 0
+}
+
+// comparePrereleaseIdentifiers compares numeric identifiers numerically and others in ASCII order,
+// with numeric identifiers before the others.
+func comparePrereleaseIdentifiers(a, b string) int {
+	aNum, bNum := isDigits(a), isDigits(b)
+	switch {
+	case aNum && bNum:
+		if len(a) != len(b) {
+			if len(a) < len(b) {
+				return -1
+			}
+			return 1
+		}
+	case aNum:
+		return -1
+	case bNum:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// LessThan reports whether v has lower precedence than other.
+func (v SemVer) LessThan(other SemVer) bool {
+	return v.Compare(other) < 0
+}
+
+// GreaterThan reports whether v has higher precedence than other.
+func (v SemVer) GreaterThan(other SemVer) bool {
+	return v.Compare(other) > 0
+}
+
+// Equal reports whether v and other have the same precedence, ignoring build metadata.
+func (v SemVer) Equal(other SemVer) bool {
+	return v.Compare(other) == 0
+}
+
+// SemVerScalar is the graphql scalar of SemVer args and results.
+var SemVerScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "SemVer",
+	Description: "A semantic version, like 1.4.2 or 2.0.0-rc.1.",
+	Serialize: func(v interface{}) interface{} {
+		switch v := v.(type) {
+		case SemVer:
+			return v.String()
+		case *SemVer:
+			if v == nil {
+				return nil
+			}
+			return v.String()
+		case string:
+			return v
+		}
+		return nil
+	},
+	ParseValue: func(v interface{}) interface{} {
+		if s, ok := v.(string); ok {
+			return s
+		}
+		return nil
+	},
+	ParseLiteral: func(v ast.Value) interface{} {
+		if s, ok := v.(*ast.StringValue); ok {
+			return s.Value
+		}
+		return nil
+	},
+})
+
+// LoadSemVer loads a SemVer from a string with ParseSemVer.
+func LoadSemVer(i interface{}) (SemVer, error) {
+	s, ok := i.(string)
+	if !ok {
+		return SemVer{}, fmt.Errorf("%v is not a string", i)
+	}
+	return ParseSemVer(s)
+}
+
+// RegisterSemVer registers SemVer on the loader with the SemVer scalar and LoadSemVer.
+func (e *ArgLoader) RegisterSemVer() error {
+	return e.Register(LoadSemVer, SemVerScalar)
+}
+
+// RegisterSemVer registers SemVer on the default loader.
+func RegisterSemVer() error {
+	return Default().RegisterSemVer()
+}
@@ -0,0 +1,174 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/graphql-go/graphql"
+)
+
+// sortTag marks model struct fields that may be sorted on.  Its value is the name of the sort
+// field, which becomes both an enum value (upper-cased) and OrderBy.Field.
+const sortTag = "sort"
+
+// SortDirection is the direction of an OrderBy.
+type SortDirection string
+
+// The two sort directions.
+const (
+	SortAsc  SortDirection = "ASC"
+	SortDesc SortDirection = "DESC"
+)
+
+// SortDirectionEnum is the graphql enum used for the direction field of every generated OrderBy
+// input object.
+var SortDirectionEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "SortDirection",
+	Values: graphql.EnumValueConfigMap{
+		string(SortAsc):  &graphql.EnumValueConfig{Value: SortAsc, Description: "Ascending order"},
+		string(SortDesc): &graphql.EnumValueConfig{Value: SortDesc, Description: "Descending order"},
+	},
+})
+
+// OrderBy is a loaded sort argument.  Field is the value of the sort tag on the model field the
+// client chose.
+type OrderBy struct {
+	Field     string
+	Direction SortDirection
+}
+
+// Desc reports whether o sorts in descending order.
+func (o OrderBy) Desc() bool {
+	return o.Direction == SortDesc
+}
+
+// SortFieldsEnum returns a graphql enum of the sortable fields of model, which must be a struct (or
+// pointer to one) with sort tags on the fields that may be sorted on.  The enum is named after the
+// model, so a User model produces a UserSortField enum.
+func SortFieldsEnum(model interface{}) (*graphql.Enum, error) {
+	t := reflect.TypeOf(model)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%v is not a struct", model)
+	}
+	values := graphql.EnumValueConfigMap{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, ok := field.Tag.Lookup(sortTag)
+		if !ok {
+			continue
+		}
+		values[enumName(name)] = &graphql.EnumValueConfig{
+			Value:       name,
+			Description: field.Tag.Get(descTag),
+		}
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%v has no fields with a %q tag", t, sortTag)
+	}
+	return graphql.NewEnum(graphql.EnumConfig{
+		Name:   t.Name() + "SortField",
+		Values: values,
+	}), nil
+}
+
+// RegisterOrderBy registers target's type as a sort argument for model.  target must be a value of
+// OrderBy or of a type defined as OrderBy (e.g. "type UserOrderBy graphqlhelpers.OrderBy"), so that
+// each model gets its own Go type and therefore its own graphql input object.  Args struct fields
+// of that type (or slices of it, for multi-column sorts) are then configured and loaded like any
+// other registered type.
+func (e *ArgLoader) RegisterOrderBy(target interface{}, model interface{}) error {
+	targetType := reflect.TypeOf(target)
+	orderByType := reflect.TypeOf(OrderBy{})
+	if !targetType.ConvertibleTo(orderByType) || targetType.Kind() != reflect.Struct {
+		return fmt.Errorf("%v cannot be used as an OrderBy", targetType)
+	}
+	fieldsEnum, err := SortFieldsEnum(model)
+	if err != nil {
+		return err
+	}
+	gqlType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: strings.TrimSuffix(fieldsEnum.Name(), "SortField") + "OrderBy",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"field": &graphql.InputObjectFieldConfig{
+				Type:        graphql.NewNonNull(fieldsEnum),
+				Description: "Field to sort by",
+			},
+			"direction": &graphql.InputObjectFieldConfig{
+				Type:         SortDirectionEnum,
+				DefaultValue: SortAsc,
+				Description:  "Sort direction. Defaults to ascending.",
+			},
+		},
+	})
+	loaderFunc := func(i interface{}) (reflect.Value, error) {
+		o, err := LoadOrderBy(i)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(o).Convert(targetType), nil
+	}
+	if err := e.register(targetType, loaderFunc, gqlType); err != nil {
+		return err
+	}
+	return e.registerDumper(targetType, func(v reflect.Value) (interface{}, error) {
+		o := v.Convert(orderByType).Interface().(OrderBy)
+		return map[string]interface{}{
+			"field":     fieldsEnum.Serialize(o.Field),
+			"direction": string(o.Direction),
+		}, nil
+	})
+}
+
+// LoadOrderBy loads an OrderBy from a raw input object value.
+func LoadOrderBy(i interface{}) (OrderBy, error) {
+	m, ok := i.(map[string]interface{})
+	if !ok {
+		return OrderBy{}, fmt.Errorf("%v is not an input object", i)
+	}
+	field, ok := m["field"].(string)
+	if !ok {
+		return OrderBy{}, fmt.Errorf("%v is not a valid sort field", m["field"])
+	}
+	o := OrderBy{Field: field, Direction: SortAsc}
+	switch d := m["direction"].(type) {
+	case nil:
+	case SortDirection:
+		o.Direction = d
+	case string:
+		o.Direction = SortDirection(d)
+	default:
+		return OrderBy{}, fmt.Errorf("%v is not a valid sort direction", d)
+	}
+	if o.Direction != SortAsc && o.Direction != SortDesc {
+		return OrderBy{}, fmt.Errorf("%v is not a valid sort direction", o.Direction)
+	}
+	return o, nil
+}
+
+// RegisterOrderBy registers target's type as a sort argument for model on the default loader.
+func RegisterOrderBy(target interface{}, model interface{}) error {
+	return Default().RegisterOrderBy(target, model)
+}
+
+// enumName converts a camelCase or snake_case name into the UPPER_SNAKE_CASE conventionally used
+// for graphql enum values.
+func enumName(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) ||
+			(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+			b.WriteRune('_')
+		}
+		if r == '-' || r == ' ' {
+			r = '_'
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,165 @@
+// Package sqlfilter translates filters loaded by graphqlhelpers into parameterized SQL WHERE
+// clauses.
+package sqlfilter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	graphqlhelpers "github.com/btubbs/graphql-go-helpers"
+)
+
+// identifierRe matches filter field names that are safe to use as column names when there is no
+// explicit mapping for them.
+var identifierRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Translator converts a graphqlhelpers.Filter into SQL.
+type Translator struct {
+	// Columns maps filter field names (the values of the filter tags on the model) to SQL column
+	// expressions.  Columns are written into the query verbatim, so they must come from code, never
+	// from user input.  Fields without a mapping use the field name itself as the column, provided
+	// it's a plain identifier.
+	Columns map[string]string
+
+	// Placeholder returns the bind parameter for the nth (1-based) argument.  Defaults to Question.
+	Placeholder func(n int) string
+}
+
+// Question is the "?" placeholder style used by MySQL and SQLite.
+func Question(n int) string {
+	return "?"
+}
+
+// Dollar is the "$1" placeholder style used by Postgres.
+func Dollar(n int) string {
+	return fmt.Sprintf("$%d", n)
+}
+
+// Where translates f into a WHERE clause (without the WHERE keyword) and the args to bind to it.
+// An empty filter produces an empty clause and no args.
+func (t Translator) Where(f graphqlhelpers.Filter) (string, []interface{}, error) {
+	w := &writer{t: t}
+	clause, err := w.filter(f)
+	if err != nil {
+		return "", nil, err
+	}
+	return clause, w.args, nil
+}
+
+// Where translates f using the default Translator with the given column mapping.
+func Where(f graphqlhelpers.Filter, columns map[string]string) (string, []interface{}, error) {
+	return Translator{Columns: columns}.Where(f)
+}
+
+// writer accumulates args while a filter is translated, so placeholders are numbered correctly.
+type writer struct {
+	t    Translator
+	args []interface{}
+}
+
+func (w *writer) bind(v interface{}) string {
+	w.args = append(w.args, v)
+	placeholder := w.t.Placeholder
+	if placeholder == nil {
+		placeholder = Question
+	}
+	return placeholder(len(w.args))
+}
+
+func (w *writer) filter(f graphqlhelpers.Filter) (string, error) {
+	var parts []string
+	for _, c := range f.Conditions {
+		part, err := w.condition(c)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, part)
+	}
+	for _, sub := range f.And {
+		part, err := w.filter(sub)
+		if err != nil {
+			return "", err
+		}
+		if part != "" {
+			parts = append(parts, "("+part+")")
+		}
+	}
+	if len(f.Or) > 0 {
+		var ors []string
+		bound := len(w.args)
+		for _, sub := range f.Or {
+			part, err := w.filter(sub)
+			if err != nil {
+				return "", err
+			}
+			if part == "" {
+				// an empty filter matches everything, so the whole OR does too.  The args of the
+				// subs already written are dropped with them, so later placeholders stay in step.
+				ors = nil
+				w.args = w.args[:bound]
+				break
+			}
+			ors = append(ors, "("+part+")")
+		}
+		if len(ors) > 0 {
+			parts = append(parts, "("+strings.Join(ors, " OR ")+")")
+		}
+	}
+	return strings.Join(parts, " AND "), nil
+}
+
+func (w *writer) condition(c graphqlhelpers.Condition) (string, error) {
+	col, err := w.t.Column(c.Field)
+	if err != nil {
+		return "", err
+	}
+	switch c.Op {
+	case graphqlhelpers.OpEq:
+		return col + " = " + w.bind(c.Value), nil
+	case graphqlhelpers.OpNe:
+		return col + " <> " + w.bind(c.Value), nil
+	case graphqlhelpers.OpGt:
+		return col + " > " + w.bind(c.Value), nil
+	case graphqlhelpers.OpLt:
+		return col + " < " + w.bind(c.Value), nil
+	case graphqlhelpers.OpContains:
+		s, ok := c.Value.(string)
+		if !ok {
+			return "", fmt.Errorf("%s: contains requires a string, got %v", c.Field, c.Value)
+		}
+		return col + " LIKE " + w.bind("%"+escapeLike(s)+"%") + " ESCAPE '!'", nil
+	case graphqlhelpers.OpIn:
+		values, ok := c.Value.([]interface{})
+		if !ok {
+			return "", fmt.Errorf("%s: in requires a list, got %v", c.Field, c.Value)
+		}
+		if len(values) == 0 {
+			// nothing is in the empty set.
+			return "1 = 0", nil
+		}
+		placeholders := make([]string, len(values))
+		for i, v := range values {
+			placeholders[i] = w.bind(v)
+		}
+		return col + " IN (" + strings.Join(placeholders, ", ") + ")", nil
+	}
+	return "", fmt.Errorf("%s: unsupported operator %q", c.Field, c.Op)
+}
+
+// Column returns the SQL column for a filter (or sort) field name.
+func (t Translator) Column(field string) (string, error) {
+	if col, ok := t.Columns[field]; ok {
+		return col, nil
+	}
+	if identifierRe.MatchString(field) {
+		return field, nil
+	}
+	return "", fmt.Errorf("no column mapping for field %q", field)
+}
+
+// escapeLike escapes the LIKE wildcards in s so that contains matches them literally.  The escape
+// character is !, since a backslash would need escaping itself in MySQL string literals.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`!`, `!!`, `%`, `!%`, `_`, `!_`).Replace(s)
+}
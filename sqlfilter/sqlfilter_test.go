@@ -0,0 +1,36 @@
+package sqlfilter
+
+import (
+	"reflect"
+	"testing"
+
+	graphqlhelpers "github.com/btubbs/graphql-go-helpers"
+)
+
+func TestWhereOrWithEmptySub(t *testing.T) {
+	f := graphqlhelpers.Filter{And: []graphqlhelpers.Filter{
+		{Or: []graphqlhelpers.Filter{
+			{Conditions: []graphqlhelpers.Condition{{Field: "a", Op: graphqlhelpers.OpEq, Value: "X"}}},
+			{},
+		}},
+		{Conditions: []graphqlhelpers.Condition{{Field: "b", Op: graphqlhelpers.OpEq, Value: "Y"}}},
+	}}
+	for _, tc := range []struct {
+		placeholder func(int) string
+		clause      string
+	}{
+		{Question, "(b = ?)"},
+		{Dollar, "(b = $1)"},
+	} {
+		clause, args, err := Translator{Placeholder: tc.placeholder}.Where(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if clause != tc.clause {
+			t.Errorf("got clause %q, want %q", clause, tc.clause)
+		}
+		if want := []interface{}{"Y"}; !reflect.DeepEqual(args, want) {
+			t.Errorf("got args %v, want %v", args, want)
+		}
+	}
+}
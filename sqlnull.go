@@ -0,0 +1,134 @@
+package graphqlhelpers
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+
+	"github.com/graphql-go/graphql"
+)
+
+// LoadNullString loads a String arg into a sql.NullString.  A null or omitted arg leaves it
+// invalid.
+func LoadNullString(i interface{}) (sql.NullString, error) {
+	s, err := LoadString(i)
+	return sql.NullString{String: s, Valid: err == nil}, err
+}
+
+// LoadNullInt64 loads an Int arg into a sql.NullInt64.  A null or omitted arg leaves it invalid.
+func LoadNullInt64(i interface{}) (sql.NullInt64, error) {
+	n, err := LoadInt(i)
+	return sql.NullInt64{Int64: int64(n), Valid: err == nil}, err
+}
+
+// LoadNullFloat64 loads a Float arg into a sql.NullFloat64.  A null or omitted arg leaves it
+// invalid.
+func LoadNullFloat64(i interface{}) (sql.NullFloat64, error) {
+	f, err := LoadFloat(i)
+	return sql.NullFloat64{Float64: f, Valid: err == nil}, err
+}
+
+// LoadNullBool loads a Boolean arg into a sql.NullBool.  A null or omitted arg leaves it invalid.
+func LoadNullBool(i interface{}) (sql.NullBool, error) {
+	b, err := LoadBool(i)
+	return sql.NullBool{Bool: b, Valid: err == nil}, err
+}
+
+// LoadNullTime loads a DateTime arg into a sql.NullTime.  A null or omitted arg leaves it invalid.
+func LoadNullTime(i interface{}) (sql.NullTime, error) {
+	t, err := LoadTime(i)
+	return sql.NullTime{Time: t, Valid: err == nil}, err
+}
+
+// sqlNullTypes are the database/sql null types, with their loader funcs and graphql types.
+var sqlNullTypes = []struct {
+	zero       driver.Valuer
+	loaderFunc interface{}
+	gqlType    *graphql.Scalar
+}{
+	{sql.NullString{}, LoadNullString, graphql.String},
+	{sql.NullInt64{}, LoadNullInt64, graphql.Int},
+	{sql.NullFloat64{}, LoadNullFloat64, graphql.Float},
+	{sql.NullBool{}, LoadNullBool, graphql.Boolean},
+	{sql.NullTime{}, LoadNullTime, graphql.DateTime},
+}
+
+// RegisterSQLNullTypes registers sql.NullString, sql.NullInt64, sql.NullFloat64, sql.NullBool and
+// sql.NullTime on the loader, as args and results, so database models using them can be args and
+// output structs as they are.  Each has the graphql type of the value it holds, always nullable:
+// invalid values resolve to null, and are what null or omitted args load as.
+func (e *ArgLoader) RegisterSQLNullTypes() error {
+	for _, n := range sqlNullTypes {
+		if err := e.Register(n.loaderFunc, n.gqlType); err != nil {
+			return err
+		}
+		gqlType := n.gqlType
+		if err := e.registerDumper(reflect.TypeOf(n.zero), func(v reflect.Value) (interface{}, error) {
+			value, err := v.Interface().(driver.Valuer).Value()
+			if err != nil || value == nil {
+				return nil, err
+			}
+			return gqlType.Serialize(value), nil
+		}); err != nil {
+			return err
+		}
+		if err := e.RegisterOutput(n.zero, n.gqlType); err != nil {
+			return err
+		}
+		e.valuerOutputs[reflect.TypeOf(n.zero)] = true
+	}
+	return nil
+}
+
+// valuerResolver converts the results of resolve to the values of their Value methods, if t, the Go
+// type it returns, is a valuer output type (or a pointer or slice of them), since graphql-go's
+// scalars can't serialize them.
+func (e *ArgLoader) valuerResolver(t reflect.Type, resolve graphql.FieldResolveFn) graphql.FieldResolveFn {
+	elem := t
+	for elem.Kind() == reflect.Ptr || elem.Kind() == reflect.Slice {
+		elem = elem.Elem()
+	}
+	if !e.valuerOutputs[elem] {
+		return resolve
+	}
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		result, err := resolve(p)
+		if err != nil || result == nil {
+			return result, err
+		}
+		return valuerValue(reflect.ValueOf(result))
+	}
+}
+
+func valuerValue(v reflect.Value) (interface{}, error) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return valuerValue(v.Elem())
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+		out := make([]interface{}, v.Len())
+		for i := range out {
+			item, err := valuerValue(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			out[i] = item
+		}
+		return out, nil
+	}
+	valuer, ok := v.Interface().(driver.Valuer)
+	if !ok {
+		return v.Interface(), nil
+	}
+	return valuer.Value()
+}
+
+// RegisterSQLNullTypes registers the database/sql null types on the default loader.
+func RegisterSQLNullTypes() error {
+	return Default().RegisterSQLNullTypes()
+}
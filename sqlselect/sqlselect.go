@@ -0,0 +1,101 @@
+// Package sqlselect maps the fields a client selected, as read by graphqlhelpers.SelectedFields,
+// to the SQL columns and joins needed to resolve them, so resolvers can select only those columns
+// instead of SELECT *, and fetch nested objects in the same query instead of one query per row.
+package sqlselect
+
+import (
+	"fmt"
+	"strings"
+
+	graphqlhelpers "github.com/btubbs/graphql-go-helpers"
+)
+
+// Table describes how the fields of one graphql object type are stored.  Columns and joins are
+// written into the query verbatim, so they must come from code, never from user input.
+type Table struct {
+	// Key lists the columns always selected, whatever fields were asked for, like the primary key
+	// that other resolvers need to load related rows.
+	Key []string
+
+	// Columns maps field names to the column expressions they're resolved from.  A field may need
+	// several columns, like a fullName built from first_name and last_name.  Fields without a
+	// mapping, like those with resolvers of their own, need no columns.
+	Columns map[string][]string
+
+	// Joins maps the names of object fields stored in another table to the JOIN clause that brings
+	// that table into the query.  When such a field is selected, the clause is added and the
+	// columns for its own selected fields are looked up in the Table of its type, so they should be
+	// qualified with the joined table's name.
+	Joins map[string]string
+}
+
+// Mapper holds the Table of each graphql object type, by type name.
+type Mapper map[string]Table
+
+// Selection is the columns and joins needed for a set of selected fields.
+type Selection struct {
+	Columns []string
+	Joins   []string
+}
+
+// ColumnList returns the columns separated by commas, for use after SELECT.
+func (s Selection) ColumnList() string {
+	return strings.Join(s.Columns, ", ")
+}
+
+// Select returns the columns and joins needed to resolve fields.  Columns and joins appear once
+// each, in the order their fields were selected, after any key columns.  For fields on a union or
+// interface, narrow them with FieldSet.OnType first.
+func (m Mapper) Select(fields graphqlhelpers.FieldSet) (Selection, error) {
+	s := &selector{m: m, seen: map[string]bool{}}
+	if err := s.add(fields); err != nil {
+		return Selection{}, err
+	}
+	return s.sel, nil
+}
+
+// Select returns the columns and joins needed to resolve fields, using the given tables.
+func Select(fields graphqlhelpers.FieldSet, tables map[string]Table) (Selection, error) {
+	return Mapper(tables).Select(fields)
+}
+
+// selector accumulates a Selection, skipping columns and joins it already has.  seen holds both.
+type selector struct {
+	m    Mapper
+	sel  Selection
+	seen map[string]bool
+}
+
+// add adds the columns and joins for fields.
+func (s *selector) add(fields graphqlhelpers.FieldSet) error {
+	name := fields.TypeName()
+	table, ok := s.m[name]
+	if !ok {
+		return fmt.Errorf("no table has been mapped for type %q", name)
+	}
+	s.columns(table.Key)
+	for _, field := range fields.Fields() {
+		s.columns(table.Columns[field])
+		join, ok := table.Joins[field]
+		if !ok {
+			continue
+		}
+		if !s.seen[join] {
+			s.seen[join] = true
+			s.sel.Joins = append(s.sel.Joins, join)
+		}
+		if err := s.add(fields.Sub(field)); err != nil {
+			return fmt.Errorf("%s.%s: %v", name, field, err)
+		}
+	}
+	return nil
+}
+
+func (s *selector) columns(cols []string) {
+	for _, col := range cols {
+		if !s.seen[col] {
+			s.seen[col] = true
+			s.sel.Columns = append(s.sel.Columns, col)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package graphqlhelpers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+)
+
+// subscriptionKey is the root object key holding the subscriptionState of a subscription operation.
+const subscriptionKey = "graphqlhelpers.subscription"
+
+// subscriptionState is shared between ExecuteSubscription and the root field resolvers built by
+// Subscribe.  graphql-go executes a subscription operation like a query, so ExecuteSubscription runs
+// the operation once to start the subscription (with started false), then once per event (with
+// event set) to build each result.
+type subscriptionState struct {
+	started bool
+	events  <-chan interface{}
+	event   interface{}
+}
+
+// subscriptionResolver returns the resolver for a field built by Subscribe, calling subscribe to
+// start the subscription.
+func subscriptionResolver(subscribe func(p graphql.ResolveParams) (<-chan interface{}, error)) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		root, _ := p.Info.RootValue.(map[string]interface{})
+		state, ok := root[subscriptionKey].(*subscriptionState)
+		if !ok {
+			return nil, fmt.Errorf("subscription field %s must be executed with ExecuteSubscription", p.Info.FieldName)
+		}
+		if state.started {
+			return state.event, nil
+		}
+		if state.events != nil {
+			return nil, fmt.Errorf("a subscription operation can only have one root field")
+		}
+		events, err := subscribe(p)
+		if err != nil {
+			return nil, err
+		}
+		state.events = events
+		return nil, nil
+	}
+}
+
+// ExecuteSubscription runs a subscription operation against a schema whose subscription fields
+// were built with Subscribe.  It starts the subscription, then executes the operation's selection
+// set against each event, sending the results on the returned channel until the event channel is
+// closed or ctx is done.  params.Context is replaced by ctx.
+func ExecuteSubscription(ctx context.Context, params graphql.Params) (<-chan *graphql.Result, error) {
+	start := &subscriptionState{}
+	params.Context = ctx
+	params.RootObject = subscriptionRoot(params.RootObject, start)
+	if res := graphql.Do(params); res.HasErrors() {
+		return nil, fmt.Errorf("cannot start subscription: %v", res.Errors[0].Message)
+	}
+	if start.events == nil {
+		return nil, fmt.Errorf("cannot start subscription: the operation did not select a subscription field")
+	}
+
+	results := make(chan *graphql.Result)
+	go func() {
+		defer close(results)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-start.events:
+				if !ok {
+					return
+				}
+				p := params
+				p.RootObject = subscriptionRoot(params.RootObject, &subscriptionState{started: true, event: event})
+				select {
+				case results <- graphql.Do(p):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return results, nil
+}
+
+// subscriptionRoot returns a copy of root with the subscription state added.
+func subscriptionRoot(root map[string]interface{}, state *subscriptionState) map[string]interface{} {
+	out := make(map[string]interface{}, len(root)+1)
+	for k, v := range root {
+		out[k] = v
+	}
+	out[subscriptionKey] = state
+	return out
+}
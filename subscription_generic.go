@@ -0,0 +1,91 @@
+//go:build go1.18
+
+package graphqlhelpers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/graphql-go/graphql"
+)
+
+// SubscribeOn builds a subscription root field from a func that loads its Args struct and returns a
+// channel of events.  The field's Args come from ArgsConfig(Args), and its type from
+// OutputType(Event), so each event is resolved like the result of a query field.  Use a struct{}
+// Args for subscriptions without arguments.  Execute the schema's subscriptions with
+// ExecuteSubscription.
+//
+//	field, err := SubscribeOn(loader, func(ctx context.Context, args MessageArgs) (<-chan Message, error) {
+//		return hub.Subscribe(ctx, args.Room), nil
+//	})
+//	builder.AddField("subscription", "messages", field)
+//
+// The event channel should be closed when ctx is done.
+func SubscribeOn[Args any, Event any](e *ArgLoader, fn func(ctx context.Context, args Args) (<-chan Event, error), opts ...FieldOption) (*graphql.Field, error) {
+	var o fieldOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	argsType := reflect.TypeOf((*Args)(nil)).Elem()
+	if argsType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("subscription args should be a struct, not %v", argsType)
+	}
+	field := &graphql.Field{
+		Description:       o.description,
+		DeprecationReason: o.deprecationReason,
+	}
+	var err error
+	field.Args, err = e.SafeArgsConfig(new(Args))
+	if err != nil {
+		return nil, err
+	}
+	if err := e.noteArgCosts(field, argsType); err != nil {
+		return nil, err
+	}
+	field.Type, err = e.outputType(reflect.TypeOf((*Event)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+	field.Resolve = e.wrap(subscriptionResolver(func(p graphql.ResolveParams) (<-chan interface{}, error) {
+		var args Args
+		if err := e.LoadArgs(p, &args); err != nil {
+			return nil, err
+		}
+		ctx := p.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		events, err := fn(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+		out := make(chan interface{})
+		go func() {
+			defer close(out)
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case event, ok := <-events:
+					if !ok {
+						return
+					}
+					select {
+					case out <- event:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}()
+		return out, nil
+	}), o.middleware...)
+	return field, nil
+}
+
+// Subscribe builds a subscription root field from a func returning a channel of events, using the
+// default loader.
+func Subscribe[Args any, Event any](fn func(ctx context.Context, args Args) (<-chan Event, error), opts ...FieldOption) (*graphql.Field, error) {
+	return SubscribeOn(Default(), fn, opts...)
+}
@@ -0,0 +1,77 @@
+package testhelpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+
+	graphqlhelpers "github.com/btubbs/graphql-go-helpers"
+)
+
+// ResolveField calls field's resolver with args, and stores what it returns in result, which must
+// be a pointer.  args may be nil, a map of args as graphql-go passes them, or an args struct, which
+// is converted with graphqlhelpers.DumpArgs.  If the resolver's result can be assigned to *result it
+// is, and otherwise it's converted through JSON, so a test can read a resolver's result into a
+// struct of its own.
+func ResolveField(field *graphql.Field, args interface{}, result interface{}, opts ...Option) error {
+	if field == nil || field.Resolve == nil {
+		return fmt.Errorf("the field has no resolver")
+	}
+	argMap, err := argsMap(args)
+	if err != nil {
+		return err
+	}
+	out := reflect.ValueOf(result)
+	if out.Kind() != reflect.Ptr || out.IsNil() {
+		return fmt.Errorf("result should be a non-nil pointer, not %T", result)
+	}
+	resolved, err := field.Resolve(NewResolveParams(argMap, opts...))
+	if err != nil {
+		return err
+	}
+	v := reflect.ValueOf(resolved)
+	for v.IsValid() && !v.Type().AssignableTo(out.Elem().Type()) && v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.IsValid() && v.Type().AssignableTo(out.Elem().Type()) {
+		out.Elem().Set(v)
+		return nil
+	}
+	b, err := json.Marshal(resolved)
+	if err != nil {
+		return fmt.Errorf("cannot convert the %T result to %T: %v", resolved, result, err)
+	}
+	if err := json.Unmarshal(b, result); err != nil {
+		return fmt.Errorf("cannot convert the %T result to %T: %v", resolved, result, err)
+	}
+	return nil
+}
+
+// ExecField is like ResolveField, but fails the test if the resolver fails or its result can't be
+// stored in result.
+//
+//	var user User
+//	testhelpers.ExecField(t, field, UserArgs{ID: "42"}, &user)
+func ExecField(t testing.TB, field *graphql.Field, args interface{}, result interface{}, opts ...Option) {
+	t.Helper()
+	if err := ResolveField(field, args, result, opts...); err != nil {
+		t.Fatalf("resolving field: %v", err)
+	}
+}
+
+func argsMap(args interface{}) (map[string]interface{}, error) {
+	switch args := args.(type) {
+	case nil:
+		return nil, nil
+	case map[string]interface{}:
+		return args, nil
+	}
+	m, err := graphqlhelpers.DumpArgs(args)
+	if err != nil {
+		return nil, fmt.Errorf("cannot convert args: %v", err)
+	}
+	return m, nil
+}
@@ -0,0 +1,129 @@
+package testhelpers
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+
+	graphqlhelpers "github.com/btubbs/graphql-go-helpers"
+)
+
+// UpdateSnapshotsEnv is the environment variable that makes AssertSchemaSnapshot rewrite golden
+// files instead of comparing against them, when it's set to anything but "" or "0":
+//
+//	UPDATE_SNAPSHOTS=1 go test ./...
+const UpdateSnapshotsEnv = "UPDATE_SNAPSHOTS"
+
+// AssertSchemaSnapshot fails the test if schema's SDL, as printed by graphqlhelpers.PrintSchema,
+// differs from the golden file at path, and reports the difference as a line diff.  Checking the
+// golden file in makes every schema change show up in code review, even when it comes from editing
+// a struct tag.
+//
+//	func TestSchema(t *testing.T) {
+//		testhelpers.AssertSchemaSnapshot(t, schema, "testdata/schema.graphql")
+//	}
+//
+// Run the tests with UPDATE_SNAPSHOTS=1 to write the golden file, creating it and its directory if
+// needed.
+func AssertSchemaSnapshot(t testing.TB, schema graphql.Schema, path string) {
+	t.Helper()
+	got := graphqlhelpers.PrintSchema(schema)
+	if v := os.Getenv(UpdateSnapshotsEnv); v != "" && v != "0" {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("writing schema snapshot: %v", err)
+		}
+		if err := ioutil.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("writing schema snapshot: %v", err)
+		}
+		return
+	}
+	want, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		t.Fatalf("schema snapshot %s does not exist; run the tests with %s=1 to create it", path, UpdateSnapshotsEnv)
+	}
+	if err != nil {
+		t.Fatalf("reading schema snapshot: %v", err)
+	}
+	if string(want) != got {
+		t.Errorf("schema differs from snapshot %s (- snapshot, + schema); run the tests with %s=1 to update it:\n%s",
+			path, UpdateSnapshotsEnv, lineDiff(string(want), got))
+	}
+}
+
+// diffContext is how many unchanged lines lineDiff shows around each change.
+const diffContext = 3
+
+// lineDiff returns a unified-style diff of the lines of a and b, with the lines only in a prefixed
+// by "-", the lines only in b by "+", and a few unchanged lines around each change.
+func lineDiff(a, b string) string {
+	x := strings.Split(strings.TrimSuffix(a, "\n"), "\n")
+	y := strings.Split(strings.TrimSuffix(b, "\n"), "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of x[i:] and y[j:].
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	type line struct {
+		op   byte
+		text string
+		num  int // where the line is, or would be, in b
+	}
+	var lines []line
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			lines = append(lines, line{' ', x[i], j + 1})
+			i++
+			j++
+		case i < len(x) && (j == len(y) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, line{'-', x[i], j + 1})
+			i++
+		default:
+			lines = append(lines, line{'+', y[j], j + 1})
+			j++
+		}
+	}
+
+	// keep the changed lines and the unchanged lines within diffContext of one.
+	keep := make([]bool, len(lines))
+	for k, l := range lines {
+		if l.op == ' ' {
+			continue
+		}
+		for d := k - diffContext; d <= k+diffContext; d++ {
+			if d >= 0 && d < len(lines) {
+				keep[d] = true
+			}
+		}
+	}
+	var out strings.Builder
+	for k, l := range lines {
+		if !keep[k] {
+			continue
+		}
+		if k == 0 || !keep[k-1] {
+			fmt.Fprintf(&out, "@@ schema line %d @@\n", l.num)
+		}
+		fmt.Fprintf(&out, "%c %s\n", l.op, l.text)
+	}
+	return out.String()
+}
@@ -0,0 +1,65 @@
+// Package testhelpers builds fixtures for unit testing resolvers that use graphqlhelpers, so tests
+// don't have to construct graphql-go's types by hand.
+package testhelpers
+
+import (
+	"context"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Option customizes the graphql.ResolveParams built by NewResolveParams.
+type Option func(*graphql.ResolveParams)
+
+// WithContext sets the params' context.  It defaults to context.Background().
+func WithContext(ctx context.Context) Option {
+	return func(p *graphql.ResolveParams) {
+		p.Context = ctx
+	}
+}
+
+// WithSource sets the params' source, the parent value of the field being resolved.
+func WithSource(source interface{}) Option {
+	return func(p *graphql.ResolveParams) {
+		p.Source = source
+	}
+}
+
+// WithField sets the field being resolved, as the field named fieldName on an object named
+// parentType, so that code calling graphqlhelpers.FieldPath sees "parentType.fieldName".
+func WithField(parentType, fieldName string) Option {
+	return func(p *graphql.ResolveParams) {
+		p.Info.FieldName = fieldName
+		p.Info.ParentType = graphql.NewObject(graphql.ObjectConfig{
+			Name:   parentType,
+			Fields: graphql.Fields{fieldName: &graphql.Field{Type: graphql.String}},
+		})
+	}
+}
+
+// WithInfo replaces the params' whole ResolveInfo.
+func WithInfo(info graphql.ResolveInfo) Option {
+	return func(p *graphql.ResolveParams) {
+		p.Info = info
+	}
+}
+
+// NewResolveParams returns params for calling a resolver, or LoadArgs, with args.  args are given
+// as graphql-go passes them, after coercion: strings, ints, float64s, bools, and
+// map[string]interface{} and []interface{} for input objects and lists.
+//
+//	p := testhelpers.NewResolveParams(map[string]interface{}{"id": "42"}, testhelpers.WithField("Query", "user"))
+//	user, err := resolveUser(p)
+func NewResolveParams(args map[string]interface{}, opts ...Option) graphql.ResolveParams {
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+	p := graphql.ResolveParams{
+		Args:    args,
+		Context: context.Background(),
+	}
+	for _, opt := range opts {
+		opt(&p)
+	}
+	return p
+}
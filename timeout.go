@@ -0,0 +1,81 @@
+package graphqlhelpers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/graphql-go/graphql"
+)
+
+// TimeoutError is the error a field fails with when its resolver runs past the deadline set by
+// Timeout.  It matches context.DeadlineExceeded with errors.Is.
+type TimeoutError struct {
+	// Field is the schema coordinate of the field, like "Query.search".
+	Field string
+	// Timeout is how long the resolver was given.
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s timed out after %v", e.Field, e.Timeout)
+}
+
+// Unwrap returns context.DeadlineExceeded.
+func (e *TimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// Timeout returns middleware that gives every resolver call it wraps d to finish.  The resolver
+// gets a context with that deadline, and if it hasn't returned when the deadline passes, the field
+// fails with a *TimeoutError straight away, so resolvers that don't watch their context can't hold
+// up the rest of the query.  The resolver keeps running in the background until it returns, and
+// its result is thrown away.
+func Timeout(d time.Duration) Middleware {
+	return func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (interface{}, error) {
+			parent := nodeContext(p)
+			ctx, cancel := context.WithTimeout(parent, d)
+			defer cancel()
+			p.Context = ctx
+
+			type outcome struct {
+				result    interface{}
+				err       error
+				recovered interface{}
+			}
+			done := make(chan outcome, 1)
+			go func() {
+				var o outcome
+				defer func() {
+					o.recovered = recover()
+					done <- o
+				}()
+				o.result, o.err = next(p)
+			}()
+
+			select {
+			case o := <-done:
+				if o.recovered != nil {
+					// re-raised here, so that Recovery middleware around this one sees it.
+					panic(o.recovered)
+				}
+				if o.err != nil && ctx.Err() == context.DeadlineExceeded && parent.Err() == nil {
+					return nil, &TimeoutError{Field: FieldPath(p.Info), Timeout: d}
+				}
+				return o.result, o.err
+			case <-ctx.Done():
+				if parent.Err() != nil {
+					return nil, parent.Err()
+				}
+				return nil, &TimeoutError{Field: FieldPath(p.Info), Timeout: d}
+			}
+		}
+	}
+}
+
+// WithTimeout gives the field's resolver d to finish, with Timeout middleware inside any
+// middleware installed on the loader with Use.
+func WithTimeout(d time.Duration) FieldOption {
+	return WithMiddleware(Timeout(d))
+}
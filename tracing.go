@@ -0,0 +1,128 @@
+package graphqlhelpers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/graphql-go/graphql"
+)
+
+// TracingExtension is the tracing response extension in the Apollo tracing format (version 1).
+//
+// graphql-go parses, validates and executes a request in one call, so there are no separate parsing
+// and validation phases.  It also doesn't tell resolvers where in the response they are, so each
+// resolver's Path only holds its own response key, not its parents' keys or list indexes.
+type TracingExtension struct {
+	Version   int              `json:"version"`
+	StartTime time.Time        `json:"startTime"`
+	EndTime   time.Time        `json:"endTime"`
+	Duration  int64            `json:"duration"`
+	Execution TracingExecution `json:"execution"`
+}
+
+// TracingExecution holds the timings of the resolvers of a traced request.
+type TracingExecution struct {
+	Resolvers []ResolverTrace `json:"resolvers"`
+}
+
+// ResolverTrace is the timing of one resolver call.  StartOffset and Duration are in nanoseconds,
+// StartOffset counting from the start of the request.
+type ResolverTrace struct {
+	Path        []interface{} `json:"path"`
+	ParentType  string        `json:"parentType"`
+	FieldName   string        `json:"fieldName"`
+	ReturnType  string        `json:"returnType"`
+	StartOffset int64         `json:"startOffset"`
+	Duration    int64         `json:"duration"`
+}
+
+type traceKey struct{}
+
+// trace collects the resolver timings of one request.
+type trace struct {
+	start     time.Time
+	mu        sync.Mutex
+	resolvers []ResolverTrace
+}
+
+// ApolloTracing returns middleware timing every resolver it wraps for the Apollo tracing extension,
+// in requests run by ExecuteWithTracing.  Install it with Use; in other requests it does nothing.
+func ApolloTracing() Middleware {
+	return func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (interface{}, error) {
+			var t *trace
+			if p.Context != nil {
+				t, _ = p.Context.Value(traceKey{}).(*trace)
+			}
+			if t == nil {
+				return next(p)
+			}
+			start := time.Now()
+			result, err := next(p)
+			r := ResolverTrace{
+				Path:        []interface{}{responseKey(p.Info)},
+				FieldName:   p.Info.FieldName,
+				StartOffset: start.Sub(t.start).Nanoseconds(),
+				Duration:    time.Since(start).Nanoseconds(),
+			}
+			if p.Info.ParentType != nil {
+				r.ParentType = p.Info.ParentType.Name()
+			}
+			if p.Info.ReturnType != nil {
+				r.ReturnType = p.Info.ReturnType.String()
+			}
+			t.mu.Lock()
+			t.resolvers = append(t.resolvers, r)
+			t.mu.Unlock()
+			return result, err
+		}
+	}
+}
+
+// ExecuteWithTracing is like Execute, but adds the Apollo tracing extension to the response, under
+// "tracing", with the timings of the resolvers wrapped by ApolloTracing.  Errors are formatted with
+// DefaultErrorFormatter.
+func ExecuteWithTracing(params graphql.Params, rules ...QueryRule) *Response {
+	var log *errorLog
+	params.Context, log = withErrorLog(params.Context)
+	res, tracing := executeTraced(params, rules...)
+	resp := newResponse(params.Context, res, log, DefaultErrorFormatter)
+	resp.Extensions = map[string]interface{}{"tracing": tracing}
+	return resp
+}
+
+// executeTraced runs Execute while tracing the resolvers wrapped by ApolloTracing.
+func executeTraced(params graphql.Params, rules ...QueryRule) (*graphql.Result, TracingExtension) {
+	ctx := params.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	t := &trace{start: time.Now()}
+	params.Context = context.WithValue(ctx, traceKey{}, t)
+	res := Execute(params, rules...)
+	end := time.Now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	resolvers := t.resolvers
+	if resolvers == nil {
+		resolvers = []ResolverTrace{}
+	}
+	return res, TracingExtension{
+		Version:   1,
+		StartTime: t.start.UTC(),
+		EndTime:   end.UTC(),
+		Duration:  end.Sub(t.start).Nanoseconds(),
+		Execution: TracingExecution{Resolvers: resolvers},
+	}
+}
+
+// responseKey returns the key the field being resolved has in the response: its alias, if it has
+// one, or else its name.
+func responseKey(info graphql.ResolveInfo) string {
+	if len(info.FieldASTs) > 0 && info.FieldASTs[0].Alias != nil {
+		return info.FieldASTs[0].Alias.Value
+	}
+	return info.FieldName
+}
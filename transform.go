@@ -0,0 +1,134 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// transformTag lists, comma-separated, the transforms to apply to a string arg after it is loaded,
+// in order, like `transform:"trim,lower"`.  It may be used on fields of any string type, and on
+// pointers, slices and arrays of them, where every string is transformed.
+const transformTag = "transform"
+
+// Transform normalizes a string arg.  Transforms run after the loader func and before validation,
+// so Validate methods see the transformed values.
+type Transform func(string) string
+
+// defaultTransforms returns the transforms every loader starts with.
+func defaultTransforms() map[string]Transform {
+	return map[string]Transform{
+		"trim":  strings.TrimSpace,
+		"lower": strings.ToLower,
+		"upper": strings.ToUpper,
+	}
+}
+
+// RegisterTransform makes t available to transform tags as name, alongside the built-in "trim",
+// "lower" and "upper".
+func (e *ArgLoader) RegisterTransform(name string, t Transform) error {
+	if name == "" || strings.ContainsAny(name, ", ") {
+		return fmt.Errorf("%q is not a valid transform name", name)
+	}
+	if t == nil {
+		return fmt.Errorf("transform %s cannot be nil", name)
+	}
+	if _, ok := e.transforms[name]; ok {
+		return fmt.Errorf("a transform named %s has already been registered", name)
+	}
+	e.transforms[name] = t
+	return nil
+}
+
+// RegisterTransform registers a transform on the default loader.
+func RegisterTransform(name string, t Transform) error {
+	return Default().RegisterTransform(name, t)
+}
+
+// fieldTransforms returns the sanitizer in field's sanitize tag followed by the transforms listed in
+// its transform tag, checking that they exist and that the field holds strings.
+func (e *ArgLoader) fieldTransforms(field reflect.StructField) ([]Transform, error) {
+	sanitize, hasSanitizer := field.Tag.Lookup(sanitizeTag)
+	tag, ok := field.Tag.Lookup(transformTag)
+	if !ok && !hasSanitizer {
+		return nil, nil
+	}
+	if !holdsStrings(field.Type) {
+		name := transformTag
+		if hasSanitizer {
+			name = sanitizeTag
+		}
+		return nil, fmt.Errorf("the %s tag can only be used on strings, not %v", name, field.Type)
+	}
+	var out []Transform
+	if hasSanitizer {
+		s, err := e.sanitizer(sanitize)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	if !ok {
+		return out, nil
+	}
+	for _, name := range strings.Split(tag, ",") {
+		name = strings.TrimSpace(name)
+		t, ok := e.transforms[name]
+		if !ok {
+			return nil, fmt.Errorf("%q is not a registered transform", name)
+		}
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+// holdsStrings reports whether t is a string type, or a pointer, slice or array of one.
+func holdsStrings(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return holdsStrings(t.Elem())
+	}
+	return false
+}
+
+// transformValue returns a copy of v with ts applied to each of its strings.
+func transformValue(v reflect.Value, ts []Transform) reflect.Value {
+	if len(ts) == 0 {
+		return v
+	}
+	switch v.Kind() {
+	case reflect.String:
+		s := v.String()
+		for _, t := range ts {
+			s = t(s)
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.SetString(s)
+		return out
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type().Elem())
+		out.Elem().Set(transformValue(v.Elem(), ts))
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(transformValue(v.Index(i), ts))
+		}
+		return out
+	case reflect.Array:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(transformValue(v.Index(i), ts))
+		}
+		return out
+	}
+	return v
+}
@@ -0,0 +1,108 @@
+package graphqlhelpers
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrorKind is why LoadArgs failed, for an ErrorTranslator.
+type ErrorKind int
+
+const (
+	// MissingArg is a required arg that wasn't given.
+	MissingArg ErrorKind = iota
+	// InvalidArg is an arg that couldn't be loaded, because it has the wrong type, its loader func
+	// rejected it, or something nested in it is missing or invalid.
+	InvalidArg
+	// FailedValidation is an args struct, or an input object nested in it, whose Validate method
+	// failed.
+	FailedValidation
+)
+
+// ErrInfo describes an error LoadArgs failed with, for an ErrorTranslator.
+type ErrInfo struct {
+	Kind ErrorKind
+	// Arg is the name of the top-level arg that failed, or "" when the args struct itself failed
+	// validation.
+	Arg string
+	// Path leads from Arg to the nested value that failed, as in ArgError.
+	Path ArgPath
+	// Value is the raw value of Arg, or nil if it wasn't given.
+	Value interface{}
+	// Message is the message the error has without translation, including any errmsg tag.
+	Message string
+	// Err is the error, to inspect with errors.As.
+	Err error
+}
+
+// ErrorTranslator returns the message for a load or validation error, typically in the language of
+// the request's locale, read from ctx.  Returning "" keeps the error's own message.
+type ErrorTranslator func(ctx context.Context, info ErrInfo) string
+
+// SetErrorTranslator makes LoadArgs translate the messages of the errors it fails with when args
+// are missing, can't be loaded or fail validation:
+//
+//	loader.SetErrorTranslator(func(ctx context.Context, info graphqlhelpers.ErrInfo) string {
+//		if info.Kind == graphqlhelpers.MissingArg {
+//			return catalog.Printf(localeFrom(ctx), "%s is required", info.Arg)
+//		}
+//		return ""
+//	})
+//
+// Translated errors keep their types, so an *ArgError is still an *ArgError, and the untranslated
+// error can be reached with errors.Unwrap.
+func (e *ArgLoader) SetErrorTranslator(translator ErrorTranslator) {
+	e.translator = translator
+}
+
+// SetErrorTranslator sets the translator for the errors returned by the default loader.
+func SetErrorTranslator(translator ErrorTranslator) {
+	Default().SetErrorTranslator(translator)
+}
+
+// validationError marks an error returned by the Validate method of a nested input object, so that
+// it can be told apart from loading errors.
+type validationError struct {
+	err error
+}
+
+func (e *validationError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the error the Validate method returned.
+func (e *validationError) Unwrap() error {
+	return e.err
+}
+
+// translate applies the loader's ErrorTranslator to err, returned by loadStruct for args.
+func (e *ArgLoader) translate(ctx context.Context, args map[string]interface{}, err error) error {
+	if e.translator == nil {
+		return err
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	info := ErrInfo{Kind: FailedValidation, Message: err.Error(), Err: err}
+	argErr, isArgErr := err.(*ArgError)
+	if isArgErr {
+		var given bool
+		info.Arg, info.Path = argErr.Arg, argErr.Path
+		info.Value, given = args[argErr.Arg]
+		var validationErr *validationError
+		switch {
+		case !given:
+			info.Kind = MissingArg
+		case !errors.As(argErr.Err, &validationErr):
+			info.Kind = InvalidArg
+		}
+	}
+	msg := e.translator(ctx, info)
+	if msg == "" {
+		return err
+	}
+	if isArgErr {
+		return &ArgError{Arg: argErr.Arg, Path: argErr.Path, Err: &messageError{msg: msg, err: argErr.Err}}
+	}
+	return &messageError{msg: msg, err: err}
+}
@@ -0,0 +1,124 @@
+package graphqlhelpers
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Union builds a graphql union of the objects generated for each of members' struct types (see
+// OutputType).  Its ResolveType picks the member object by the Go dynamic type of the resolved
+// value, so a resolver can return any of the member structs, or pointers to them.
+func (e *ArgLoader) Union(name string, members ...interface{}) (*graphql.Union, error) {
+	if len(members) == 0 {
+		return nil, fmt.Errorf("union %s needs at least one member", name)
+	}
+	if _, taken := e.typeNames[name]; taken {
+		return nil, fmt.Errorf("cannot build union %s: the name is already used by %s", name, e.typeDescription(name))
+	}
+	objects, byType, err := e.memberObjects(name, members)
+	if err != nil {
+		return nil, err
+	}
+	union := graphql.NewUnion(graphql.UnionConfig{
+		Name:        name,
+		Types:       objects,
+		ResolveType: typeResolver(byType),
+	})
+	e.typeNames[name] = union
+	return union, nil
+}
+
+// RegisterUnion builds a union of members, as Union does, and registers it as the output type of
+// the interface type iface points to, so resolvers can declare that they return the interface.  iface
+// should be a nil pointer to the interface, like (*SearchResult)(nil), and every member must
+// implement it.
+//
+//	type SearchResult interface{ isSearchResult() }
+//
+//	err := RegisterUnion((*SearchResult)(nil), "SearchResult", User{}, Post{}, Comment{})
+//	field := FieldFromFunc(func(ctx context.Context, args SearchArgs) ([]SearchResult, error) { ... })
+func (e *ArgLoader) RegisterUnion(iface interface{}, name string, members ...interface{}) error {
+	t, err := interfaceType(iface)
+	if err != nil {
+		return err
+	}
+	if _, alreadyRegistered := e.outputTypes[t]; alreadyRegistered {
+		return fmt.Errorf("an output type has already been registered for the %v type", t)
+	}
+	for _, m := range members {
+		if !implements(reflect.TypeOf(m), t) {
+			return fmt.Errorf("cannot add %v to union %s: it does not implement %v", reflect.TypeOf(m), name, t)
+		}
+	}
+	union, err := e.Union(name, members...)
+	if err != nil {
+		return err
+	}
+	e.outputTypes[t] = union
+	return nil
+}
+
+// implements reports whether t, or a pointer to it, implements iface.
+func implements(t, iface reflect.Type) bool {
+	return t != nil && (t.Implements(iface) || (t.Kind() != reflect.Ptr && reflect.PtrTo(t).Implements(iface)))
+}
+
+// interfaceType returns the interface type iface points to.
+func interfaceType(iface interface{}) (reflect.Type, error) {
+	t := reflect.TypeOf(iface)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Interface {
+		return nil, fmt.Errorf("%v is not a pointer to an interface. pass one like (*MyInterface)(nil)", t)
+	}
+	return t.Elem(), nil
+}
+
+// memberObjects generates the objects for the struct types of members, returning them in order and
+// keyed by struct type.
+func (e *ArgLoader) memberObjects(name string, members []interface{}) ([]*graphql.Object, map[reflect.Type]*graphql.Object, error) {
+	objects := make([]*graphql.Object, 0, len(members))
+	byType := map[reflect.Type]*graphql.Object{}
+	for _, m := range members {
+		t := reflect.TypeOf(m)
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			return nil, nil, fmt.Errorf("cannot add %v to %s: members must be structs", reflect.TypeOf(m), name)
+		}
+		if _, dup := byType[t]; dup {
+			return nil, nil, fmt.Errorf("%v was added to %s twice", t, name)
+		}
+		obj, err := e.object(t)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot add %v to %s: %v", t, name, err)
+		}
+		objects = append(objects, obj)
+		byType[t] = obj
+	}
+	return objects, byType, nil
+}
+
+// typeResolver returns a ResolveTypeFn that picks an object by the struct type of the value, after
+// dereferencing any pointers.
+func typeResolver(byType map[reflect.Type]*graphql.Object) graphql.ResolveTypeFn {
+	return func(p graphql.ResolveTypeParams) *graphql.Object {
+		t := reflect.TypeOf(p.Value)
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		return byType[t]
+	}
+}
+
+// Union builds a graphql union of members' objects using the default loader.
+func Union(name string, members ...interface{}) (*graphql.Union, error) {
+	return Default().Union(name, members...)
+}
+
+// RegisterUnion builds a union and registers it as the output type of an interface on the default
+// loader.
+func RegisterUnion(iface interface{}, name string, members ...interface{}) error {
+	return Default().RegisterUnion(iface, name, members...)
+}
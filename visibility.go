@@ -0,0 +1,61 @@
+package graphqlhelpers
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+const visibilityTag = "visibility"
+
+// ErrHidden is the error a field tagged `visibility:"...,error"` fails with when the viewer may not
+// see it.
+var ErrHidden = errors.New("not authorized to view this field")
+
+// VisibilityFunc reports whether the viewer of a request, identified from its context, may see
+// fields with the given visibility.
+type VisibilityFunc func(ctx context.Context, visibility string) bool
+
+// SetVisibility sets the predicate deciding who can see output fields with a visibility tag, like
+//
+//	Email string `field:"email" visibility:"pii"`
+//
+// Hidden fields resolve to null, or fail with ErrHidden if the tag has an error option
+// (`visibility:"pii,error"`).  If no predicate has been set, every tagged field is hidden.  The
+// predicate is looked up when fields resolve, so it can be set after the objects are generated.
+func (e *ArgLoader) SetVisibility(visible VisibilityFunc) {
+	e.visible = visible
+}
+
+// SetVisibility sets the visibility predicate of the default loader.
+func SetVisibility(visible VisibilityFunc) {
+	Default().SetVisibility(visible)
+}
+
+// visibilityResolver wraps the resolver of an output field with a visibility tag.
+func (e *ArgLoader) visibilityResolver(tag string, resolve graphql.FieldResolveFn) graphql.FieldResolveFn {
+	visibility, asError := tag, false
+	if idx := strings.Index(tag, ","); idx >= 0 {
+		visibility = tag[:idx]
+		for _, opt := range strings.Split(tag[idx+1:], ",") {
+			if opt == "error" {
+				asError = true
+			}
+		}
+	}
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		ctx := p.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		if e.visible == nil || !e.visible(ctx, visibility) {
+			if asError {
+				return nil, ErrHidden
+			}
+			return nil, nil
+		}
+		return resolve(p)
+	}
+}